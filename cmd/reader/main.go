@@ -1,32 +1,79 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/state"
 	"github.com/fase22/tui/internal/ui"
+	"github.com/fase22/tui/internal/ui/components/messages"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Bitte geben Sie einen Dateinamen an")
+	diffMode := flag.Bool("diff", false, "Zwei Dateien vergleichen: reader --diff alt neu")
+	unified := flag.Bool("unified", false, "Diff untereinander statt nebeneinander anzeigen")
+	flag.Parse()
+	args := flag.Args()
+
+	// Ohne Dateinamen wird eine umgeleitete Eingabe angezeigt (git diff | reader)
+	if len(args) == 0 && file.IsPiped() {
+		args = []string{"-"}
+	}
+	if len(args) < 1 {
+		fmt.Println("Bitte geben Sie mindestens einen Dateinamen an")
+		os.Exit(1)
+	}
+	if *diffMode && len(args) != 2 {
+		fmt.Println("Für --diff werden genau zwei Dateien benötigt")
 		os.Exit(1)
 	}
 
 	// Lade Konfiguration
-	cfg, err := config.LoadConfig("")
-	if err != nil {
-		fmt.Printf("Warnung: Konnte Konfiguration nicht laden: %v\n", err)
+	cfg, cfgErr := config.LoadConfig("")
+	if cfgErr != nil {
 		cfg = config.DefaultConfig()
 	}
 
-	filename := os.Args[1]
-	p := tea.NewProgram(ui.NewModel(filename, &cfg))
+	var model *ui.Model
+	if *diffMode {
+		model = ui.NewDiffModel(args[0], args[1], *unified, &cfg)
+	} else {
+		model = ui.NewModel(args, &cfg)
+	}
+	if cfgErr != nil {
+		model.Notify(messages.Warn("Konnte Konfiguration nicht laden: %v", cfgErr))
+	}
+
+	// Marken und Positionen der letzten Sitzungen
+	if path, err := state.DefaultPath(); err == nil {
+		st, err := state.Load(path)
+		if err != nil {
+			model.Notify(messages.Warn("Konnte Zustand nicht laden: %v", err))
+		}
+		model.SetState(st)
+	}
+
+	var opts []tea.ProgramOption
+	for _, arg := range args {
+		// Die Standardeingabe liefert den Inhalt, Tasten kommen vom Terminal
+		if arg == "-" {
+			opts = append(opts, tea.WithInputTTY())
+			break
+		}
+	}
+	p := tea.NewProgram(model, opts...)
 
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Ahhh, es gab einen Fehler: %v", err)
 		os.Exit(1)
 	}
+
+	// Position, Suche und Schalter für die nächste Sitzung merken
+	if err := model.SaveState(); err != nil {
+		fmt.Printf("Konnte Zustand nicht speichern: %v\n", err)
+	}
 }
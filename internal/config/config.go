@@ -23,6 +23,9 @@ type Theme struct {
 	Selection   string    `json:"selection"`
 	Accent      string    `json:"accent"`
 	LineNumbers string    `json:"lineNumbers"`
+	Added       string    `json:"added"`   // Diff: hinzugefügte Zeilen
+	Removed     string    `json:"removed"` // Diff: entfernte Zeilen
+	Changed     string    `json:"changed"` // Diff: geänderte Zeilen
 }
 
 // Vordefinierte Themes
@@ -34,6 +37,9 @@ var (
 		Selection:   "#3e4451",
 		Accent:      "#61afef",
 		LineNumbers: "#4b5263",
+		Added:       "#98c379",
+		Removed:     "#e06c75",
+		Changed:     "#e5c07b",
 	}
 
 	LightTheme = Theme{
@@ -43,6 +49,9 @@ var (
 		Selection:   "#e5e5e6",
 		Accent:      "#4078f2",
 		LineNumbers: "#9d9d9f",
+		Added:       "#50a14f",
+		Removed:     "#e45649",
+		Changed:     "#c18401",
 	}
 
 	DraculaTheme = Theme{
@@ -52,6 +61,9 @@ var (
 		Selection:   "#44475a",
 		Accent:      "#bd93f9",
 		LineNumbers: "#6272a4",
+		Added:       "#50fa7b",
+		Removed:     "#ff5555",
+		Changed:     "#f1fa8c",
 	}
 )
 
@@ -61,10 +73,12 @@ type Config struct {
 
 	// Editor-Einstellungen
 	Editor struct {
-		ShowLineNumbers bool `json:"showLineNumbers"`
-		TabWidth        int  `json:"tabWidth"`
-		WordWrap        bool `json:"wordWrap"`
-		AutoIndent      bool `json:"autoIndent"`
+		ShowLineNumbers bool   `json:"showLineNumbers"`
+		TabWidth        int    `json:"tabWidth"`
+		WordWrap        bool   `json:"wordWrap"`
+		AutoIndent      bool   `json:"autoIndent"`
+		Encoding        string `json:"encoding"`        // "auto" oder z. B. "latin1"
+		MarkLineEndings bool   `json:"markLineEndings"` // Abweichende Zeilenenden hervorheben
 	} `json:"editor"`
 
 	// UI-Einstellungen
@@ -74,12 +88,63 @@ type Config struct {
 		ScrollStyle   string `json:"scrollStyle"` // "bar" oder "block"
 	} `json:"ui"`
 
-	// Tastatur-Shortcuts
+	// Tastatur-Shortcuts, mehrere Tasten durch Komma getrennt (z. B. "down,j")
 	Keybindings struct {
-		QuitKey        string `json:"quitKey"`
-		SaveKey        string `json:"saveKey"`
-		ToggleWrapKey  string `json:"toggleWrapKey"`
-		ToggleLinesKey string `json:"toggleLinesKey"`
+		QuitKey         string `json:"quitKey"`
+		SaveKey         string `json:"saveKey"`
+		ToggleWrapKey   string `json:"toggleWrapKey"`
+		ToggleLinesKey  string `json:"toggleLinesKey"`
+		UpKey           string `json:"upKey"`
+		DownKey         string `json:"downKey"`
+		PageUpKey       string `json:"pageUpKey"`
+		PageDownKey     string `json:"pageDownKey"`
+		TopKey          string `json:"topKey"`
+		BottomKey       string `json:"bottomKey"`
+		SearchKey       string `json:"searchKey"`
+		NextMatchKey    string `json:"nextMatchKey"`
+		PrevMatchKey    string `json:"prevMatchKey"`
+		CommandKey      string `json:"commandKey"`
+		MessagesKey     string `json:"messagesKey"`
+		HelpKey         string `json:"helpKey"`
+		NextBufferKey   string `json:"nextBufferKey"`
+		PrevBufferKey   string `json:"prevBufferKey"`
+		SplitKey        string `json:"splitKey"`
+		VSplitKey       string `json:"vsplitKey"`
+		NextPaneKey     string `json:"nextPaneKey"`
+		PaneLeftKey     string `json:"paneLeftKey"`
+		PaneDownKey     string `json:"paneDownKey"`
+		PaneUpKey       string `json:"paneUpKey"`
+		PaneRightKey    string `json:"paneRightKey"`
+		GrowPaneKey     string `json:"growPaneKey"`
+		ShrinkPaneKey   string `json:"shrinkPaneKey"`
+		ClosePaneKey    string `json:"closePaneKey"`
+		ScrollBindKey   string `json:"scrollBindKey"`
+		NextHunkKey     string `json:"nextHunkKey"`
+		PrevHunkKey     string `json:"prevHunkKey"`
+		NextFileKey     string `json:"nextFileKey"`
+		PrevFileKey     string `json:"prevFileKey"`
+		FilesKey        string `json:"filesKey"`
+		OutlineKey      string `json:"outlineKey"`
+		SetMarkKey      string `json:"setMarkKey"`
+		JumpMarkKey     string `json:"jumpMarkKey"`
+		HexKey          string `json:"hexKey"`
+		OpenKey         string `json:"openKey"`
+		ToggleModeKey   string `json:"toggleModeKey"`
+		FoldToggleKey   string `json:"foldToggleKey"`
+		FoldCloseKey    string `json:"foldCloseKey"`
+		FoldOpenKey     string `json:"foldOpenKey"`
+		FoldCloseAllKey string `json:"foldCloseAllKey"`
+		FoldOpenAllKey  string `json:"foldOpenAllKey"`
+		CopyPathKey     string `json:"copyPathKey"`
+		ColumnLeftKey   string `json:"columnLeftKey"`
+		ColumnRightKey  string `json:"columnRightKey"`
+		MoveLeftKey     string `json:"moveLeftKey"`
+		MoveRightKey    string `json:"moveRightKey"`
+		SortKey         string `json:"sortKey"`
+		HideColumnKey   string `json:"hideColumnKey"`
+		InsertKey       string `json:"insertKey"`
+		UndoKey         string `json:"undoKey"`
+		RedoKey         string `json:"redoKey"`
 	} `json:"keybindings"`
 }
 
@@ -95,6 +160,8 @@ func DefaultConfig() Config {
 	cfg.Editor.TabWidth = 4
 	cfg.Editor.WordWrap = false
 	cfg.Editor.AutoIndent = true
+	cfg.Editor.Encoding = "auto"
+	cfg.Editor.MarkLineEndings = false
 
 	// Standard UI-Einstellungen
 	cfg.UI.ShowScrollbar = true
@@ -106,10 +173,76 @@ func DefaultConfig() Config {
 	cfg.Keybindings.SaveKey = "ctrl+s"
 	cfg.Keybindings.ToggleWrapKey = "ctrl+w"
 	cfg.Keybindings.ToggleLinesKey = "ctrl+l"
+	cfg.Keybindings.UpKey = "up,k"
+	cfg.Keybindings.DownKey = "down,j"
+	cfg.Keybindings.PageUpKey = "pgup"
+	cfg.Keybindings.PageDownKey = "pgdown"
+	cfg.Keybindings.TopKey = "home,gg"
+	cfg.Keybindings.BottomKey = "end,G"
+	cfg.Keybindings.SearchKey = "/"
+	cfg.Keybindings.NextMatchKey = "n"
+	cfg.Keybindings.PrevMatchKey = "N"
+	cfg.Keybindings.CommandKey = ":"
+	cfg.Keybindings.MessagesKey = "M"
+	cfg.Keybindings.HelpKey = "?"
+	cfg.Keybindings.NextBufferKey = "gt"
+	cfg.Keybindings.PrevBufferKey = "gT"
+	cfg.Keybindings.SplitKey = "ctrl+x s"
+	cfg.Keybindings.VSplitKey = "ctrl+x v"
+	cfg.Keybindings.NextPaneKey = "ctrl+x w"
+	cfg.Keybindings.PaneLeftKey = "ctrl+x h"
+	cfg.Keybindings.PaneDownKey = "ctrl+x j"
+	cfg.Keybindings.PaneUpKey = "ctrl+x k"
+	cfg.Keybindings.PaneRightKey = "ctrl+x l"
+	cfg.Keybindings.GrowPaneKey = "ctrl+x +"
+	cfg.Keybindings.ShrinkPaneKey = "ctrl+x -"
+	cfg.Keybindings.ClosePaneKey = "ctrl+x c"
+	cfg.Keybindings.ScrollBindKey = "ctrl+x b"
+	cfg.Keybindings.NextHunkKey = "]c,]h"
+	cfg.Keybindings.PrevHunkKey = "[c,[h"
+	cfg.Keybindings.NextFileKey = "]f"
+	cfg.Keybindings.PrevFileKey = "[f"
+	cfg.Keybindings.FilesKey = "F"
+	cfg.Keybindings.OutlineKey = "O"
+	cfg.Keybindings.SetMarkKey = "m"
+	cfg.Keybindings.JumpMarkKey = "'"
+	cfg.Keybindings.HexKey = "H"
+	cfg.Keybindings.OpenKey = "enter"
+	cfg.Keybindings.ToggleModeKey = "R"
+	cfg.Keybindings.FoldToggleKey = "za"
+	cfg.Keybindings.FoldCloseKey = "zc"
+	cfg.Keybindings.FoldOpenKey = "zo"
+	cfg.Keybindings.FoldCloseAllKey = "zM"
+	cfg.Keybindings.FoldOpenAllKey = "zR"
+	cfg.Keybindings.CopyPathKey = "yp"
+	cfg.Keybindings.ColumnLeftKey = "left,h"
+	cfg.Keybindings.ColumnRightKey = "right,l"
+	cfg.Keybindings.MoveLeftKey = "<"
+	cfg.Keybindings.MoveRightKey = ">"
+	cfg.Keybindings.SortKey = "s"
+	cfg.Keybindings.HideColumnKey = "x"
+	cfg.Keybindings.InsertKey = "i"
+	cfg.Keybindings.UndoKey = "u"
+	cfg.Keybindings.RedoKey = "ctrl+r"
 
 	return cfg
 }
 
+// Themes liefert alle vordefinierten Themes
+func Themes() []Theme {
+	return []Theme{DarkTheme, LightTheme, DraculaTheme}
+}
+
+// ThemeByName sucht ein vordefiniertes Theme anhand seines Namens
+func ThemeByName(name ThemeName) (Theme, bool) {
+	for _, theme := range Themes() {
+		if theme.Name == name {
+			return theme, true
+		}
+	}
+	return Theme{}, false
+}
+
 // GetTheme gibt das konfigurierte Theme zurück
 func (c Config) GetTheme() Theme {
 	switch c.Theme.Name {
@@ -0,0 +1,359 @@
+package diff
+
+// Kind beschreibt, wie eine Zeile in den Vergleich eingeht
+type Kind int
+
+const (
+	Equal Kind = iota
+	Delete
+	Insert
+)
+
+// Edit ist eine Zeile des Vergleichs. OldLine und NewLine sind 0-basiert,
+// -1 wenn die Zeile auf der jeweiligen Seite fehlt.
+type Edit struct {
+	Kind    Kind
+	OldLine int
+	NewLine int
+	Text    string
+}
+
+// Hunk fasst zusammenhängende Änderungen mit Kontextzeilen zusammen.
+// Start ist der Index der ersten Edit in der vollständigen Edit-Liste.
+type Hunk struct {
+	Start    int
+	End      int // exklusiv
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+}
+
+// Range ist ein Byte-Bereich innerhalb einer Zeile
+type Range struct {
+	Start int
+	End   int
+}
+
+// Lines vergleicht zwei Zeilenlisten mit dem Myers-Algorithmus
+func Lines(a, b []string) []Edit {
+	var edits []Edit
+	for _, o := range myers(a, b) {
+		switch o.kind {
+		case Equal:
+			edits = append(edits, Edit{Kind: Equal, OldLine: o.ai, NewLine: o.bi, Text: a[o.ai]})
+		case Delete:
+			edits = append(edits, Edit{Kind: Delete, OldLine: o.ai, NewLine: -1, Text: a[o.ai]})
+		case Insert:
+			edits = append(edits, Edit{Kind: Insert, OldLine: -1, NewLine: o.bi, Text: b[o.bi]})
+		}
+	}
+	return edits
+}
+
+// Hunks gruppiert die Änderungen mit context Zeilen Kontext
+func Hunks(edits []Edit, context int) []Hunk {
+	var hunks []Hunk
+
+	for i := 0; i < len(edits); {
+		if edits[i].Kind == Equal {
+			i++
+			continue
+		}
+
+		start := max(i-context, 0)
+		end := i
+		for end < len(edits) {
+			if edits[end].Kind != Equal {
+				end++
+				continue
+			}
+			// Gleichbleibende Zeilen zählen, bis die nächste Änderung zu weit weg ist
+			run := end
+			for run < len(edits) && edits[run].Kind == Equal {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end = min(end+context, len(edits))
+				break
+			}
+			end = run
+		}
+
+		hunks = append(hunks, newHunk(edits, start, end))
+		i = end
+	}
+
+	return hunks
+}
+
+func newHunk(edits []Edit, start, end int) Hunk {
+	h := Hunk{Start: start, End: end, OldStart: -1, NewStart: -1}
+	for _, e := range edits[start:end] {
+		if e.OldLine >= 0 {
+			if h.OldStart < 0 {
+				h.OldStart = e.OldLine
+			}
+			h.OldLines++
+		}
+		if e.NewLine >= 0 {
+			if h.NewStart < 0 {
+				h.NewStart = e.NewLine
+			}
+			h.NewLines++
+		}
+	}
+	return h
+}
+
+// Pair ordnet eine gelöschte einer eingefügten Zeile zu (geänderte Zeile)
+type Pair struct {
+	Delete int // Index in der Edit-Liste
+	Insert int
+}
+
+// Pairs findet Blöcke aus Löschungen gefolgt von Einfügungen und ordnet sie
+// zeilenweise einander zu
+func Pairs(edits []Edit) []Pair {
+	var pairs []Pair
+	for i := 0; i < len(edits); {
+		if edits[i].Kind != Delete {
+			i++
+			continue
+		}
+
+		delStart := i
+		for i < len(edits) && edits[i].Kind == Delete {
+			i++
+		}
+		insStart := i
+		for i < len(edits) && edits[i].Kind == Insert {
+			i++
+		}
+
+		for j := 0; j < insStart-delStart && j < i-insStart; j++ {
+			pairs = append(pairs, Pair{Delete: delStart + j, Insert: insStart + j})
+		}
+	}
+	return pairs
+}
+
+// Chars vergleicht zwei Zeilen zeichenweise und liefert die geänderten
+// Bereiche der alten und der neuen Zeile
+func Chars(a, b string) (oldRanges, newRanges []Range) {
+	ar, br := []rune(a), []rune(b)
+	aOffsets, bOffsets := byteOffsets(ar), byteOffsets(br)
+
+	for _, o := range myers(ar, br) {
+		switch o.kind {
+		case Delete:
+			oldRanges = appendRange(oldRanges, aOffsets[o.ai], aOffsets[o.ai+1])
+		case Insert:
+			newRanges = appendRange(newRanges, bOffsets[o.bi], bOffsets[o.bi+1])
+		}
+	}
+	return oldRanges, newRanges
+}
+
+// byteOffsets liefert für jede Rune ihren Byte-Offset plus das Ende
+func byteOffsets(runes []rune) []int {
+	offsets := make([]int, len(runes)+1)
+	pos := 0
+	for i, r := range runes {
+		offsets[i] = pos
+		pos += len(string(r))
+	}
+	offsets[len(runes)] = pos
+	return offsets
+}
+
+// appendRange hängt einen Bereich an und verschmilzt ihn mit dem vorherigen
+func appendRange(ranges []Range, start, end int) []Range {
+	if n := len(ranges); n > 0 && ranges[n-1].End == start {
+		ranges[n-1].End = end
+		return ranges
+	}
+	return append(ranges, Range{Start: start, End: end})
+}
+
+type op struct {
+	kind Kind
+	ai   int
+	bi   int
+}
+
+// maxCost begrenzt die Schritte, mit denen nach dem Teilungspunkt eines
+// Abschnitts gesucht wird. Bei mehr Unterschieden wird an dem Punkt geteilt,
+// den die Suche bis dahin am weitesten gebracht hat. Das Ergebnis ist dann
+// nicht mehr unbedingt das kürzeste, die Laufzeit bleibt aber beschränkt.
+const maxCost = 1024
+
+// myers berechnet die kürzeste Edit-Folge von a nach b mit der Variante von
+// Myers in linearem Speicher: Jeder Abschnitt wird an der Mitte eines
+// kürzesten Weges geteilt und beide Hälften werden rekursiv verglichen.
+func myers[T comparable](a, b []T) []op {
+	s := differ[T]{a: a, b: b, ops: make([]op, 0, len(a)+len(b))}
+	s.compare(0, len(a), 0, len(b))
+	return deletesFirst(s.ops)
+}
+
+type differ[T comparable] struct {
+	a, b   []T
+	vf, vb []int // Weiteste x je Diagonale vorwärts bzw. rückwärts
+	ops    []op
+}
+
+// compare vergleicht a[aLo:aHi] mit b[bLo:bHi] und hängt die Edits an
+func (s *differ[T]) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && s.a[aLo] == s.b[bLo] {
+		s.ops = append(s.ops, op{kind: Equal, ai: aLo, bi: bLo})
+		aLo++
+		bLo++
+	}
+	// Das gemeinsame Ende folgt nach dem Rest
+	suffix := 0
+	for aLo < aHi && bLo < bHi && s.a[aHi-1] == s.b[bHi-1] {
+		aHi--
+		bHi--
+		suffix++
+	}
+
+	switch {
+	case aLo == aHi:
+		for ; bLo < bHi; bLo++ {
+			s.ops = append(s.ops, op{kind: Insert, ai: -1, bi: bLo})
+		}
+	case bLo == bHi:
+		for ; aLo < aHi; aLo++ {
+			s.ops = append(s.ops, op{kind: Delete, ai: aLo, bi: -1})
+		}
+	default:
+		x, y := s.split(aLo, aHi, bLo, bHi)
+		s.compare(aLo, x, bLo, y)
+		s.compare(x, aHi, y, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		s.ops = append(s.ops, op{kind: Equal, ai: aHi + i, bi: bHi + i})
+	}
+}
+
+// split sucht von beiden Enden gleichzeitig, bis sich die Wege treffen, und
+// liefert den Treffpunkt. Anfang und Ende der Abschnitte unterscheiden
+// sich, der Punkt liegt daher echt zwischen beiden Ecken.
+func (s *differ[T]) split(aLo, aHi, bLo, bHi int) (int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	limit := min((n+m+1)/2, maxCost)
+	off := limit + 1
+
+	s.vf = reset(s.vf, 2*limit+3)
+	s.vb = reset(s.vb, 2*limit+3)
+	vf, vb := s.vf, s.vb
+	vf[off+1], vb[off+1] = 0, 0
+
+	// Diagonalen, deren Wege das Feld verlassen haben, werden übersprungen
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for d := 0; d <= limit; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			x := furthest(vf, off, k, d)
+			y := x - k
+			for x < n && y < m && s.a[aLo+x] == s.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				if r := off + delta - k; r >= 0 && r < len(vb) && vb[r] >= 0 && x+vb[r] >= n {
+					return aLo + x, bLo + y
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			x := furthest(vb, off, k, d)
+			y := x - k
+			for x < n && y < m && s.a[aHi-1-x] == s.b[bHi-1-y] {
+				x++
+				y++
+			}
+			vb[off+k] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				if f := off + delta - k; f >= 0 && f < len(vf) && vf[f] >= 0 && vf[f]+x >= n {
+					return aHi - x, bHi - y
+				}
+			}
+		}
+	}
+
+	// Zu teuer: am weitesten vorwärts gekommenen Punkt im Feld teilen
+	bestX, bestY := 0, 0
+	for k := -limit; k <= limit; k++ {
+		x := vf[off+k]
+		if y := x - k; x >= 0 && x <= n && y >= 0 && y <= m && x+y > bestX+bestY {
+			bestX, bestY = x, y
+		}
+	}
+	return aLo + bestX, bLo + bestY
+}
+
+// furthest liefert das x, von dem der Weg auf Diagonale k im Schritt d
+// ausgeht: eine Zeile tiefer von k+1 oder eine Spalte weiter von k-1
+func furthest(v []int, off, k, d int) int {
+	if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+		return v[off+k+1]
+	}
+	return v[off+k-1] + 1
+}
+
+// reset liefert v mit n Einträgen, alle -1 für noch nicht erreicht
+func reset(v []int, n int) []int {
+	if cap(v) < n {
+		v = make([]int, n)
+	}
+	v = v[:n]
+	for i := range v {
+		v[i] = -1
+	}
+	return v
+}
+
+// deletesFirst ordnet jeden Block von Änderungen so, dass die Löschungen
+// vor den Einfügungen stehen, wie Pairs sie erwartet
+func deletesFirst(ops []op) []op {
+	var inserts []op
+	for i := 0; i < len(ops); {
+		if ops[i].kind == Equal {
+			i++
+			continue
+		}
+		start, end := i, i
+		for end < len(ops) && ops[end].kind != Equal {
+			end++
+		}
+		inserts = inserts[:0]
+		j := start
+		for _, o := range ops[start:end] {
+			if o.kind == Delete {
+				ops[j] = o
+				j++
+			} else {
+				inserts = append(inserts, o)
+			}
+		}
+		copy(ops[j:end], inserts)
+		i = end
+	}
+	return ops
+}
@@ -0,0 +1,151 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// apply prüft, dass die Edits a vollständig und der Reihe nach in b
+// überführen, und liefert die Anzahl der Änderungen
+func apply(t *testing.T, a, b []string, edits []Edit) int {
+	t.Helper()
+	ai, bi, changes := 0, 0, 0
+	for _, e := range edits {
+		switch e.Kind {
+		case Equal:
+			if e.OldLine != ai || e.NewLine != bi || a[ai] != b[bi] {
+				t.Fatalf("Equal %+v an Position %d/%d", e, ai, bi)
+			}
+			ai++
+			bi++
+		case Delete:
+			if e.OldLine != ai {
+				t.Fatalf("Delete %+v an Position %d", e, ai)
+			}
+			ai++
+			changes++
+		case Insert:
+			if e.NewLine != bi {
+				t.Fatalf("Insert %+v an Position %d", e, bi)
+			}
+			bi++
+			changes++
+		}
+	}
+	if ai != len(a) || bi != len(b) {
+		t.Fatalf("Edits enden bei %d/%d statt %d/%d", ai, bi, len(a), len(b))
+	}
+	return changes
+}
+
+// lcs liefert die Länge der längsten gemeinsamen Teilfolge
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			default:
+				cur[j+1] = max(cur[j], prev[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func randomLines(rng *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(rune('a' + rng.Intn(4)))
+	}
+	return lines
+}
+
+func TestLinesMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		a := randomLines(rng, rng.Intn(30))
+		b := randomLines(rng, rng.Intn(30))
+		changes := apply(t, a, b, Lines(a, b))
+		if want := len(a) + len(b) - 2*lcs(a, b); changes != want {
+			t.Fatalf("%q → %q: %d Änderungen, kürzeste hat %d", a, b, changes, want)
+		}
+	}
+}
+
+func TestLinesDeletesBeforeInserts(t *testing.T) {
+	edits := Lines([]string{"a", "x", "y", "b"}, []string{"a", "1", "2", "3", "b"})
+	var kinds []Kind
+	for _, e := range edits {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []Kind{Equal, Delete, Delete, Insert, Insert, Insert, Equal}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) {
+		t.Errorf("Reihenfolge %v, erwartet %v", kinds, want)
+	}
+	if pairs := Pairs(edits); len(pairs) != 2 {
+		t.Errorf("Pairs = %v", pairs)
+	}
+}
+
+// TestLinesLarge vergleicht große, völlig verschiedene Eingaben. Der
+// Speicherbedarf darf dabei nicht mit der Anzahl der Unterschiede wachsen.
+func TestLinesLarge(t *testing.T) {
+	for _, n := range []int{5000, 20000} {
+		a := make([]string, n)
+		b := make([]string, n)
+		for i := range a {
+			a[i] = fmt.Sprintf("alt %d", i)
+			b[i] = fmt.Sprintf("neu %d", i)
+		}
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		edits := Lines(a, b)
+		runtime.ReadMemStats(&after)
+
+		if changes := apply(t, a, b, edits); changes != 2*n {
+			t.Errorf("%d Zeilen: %d Änderungen, erwartet %d", n, changes, 2*n)
+		}
+		if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 64<<20 {
+			t.Errorf("%d Zeilen: %d MB angefordert", n, alloc>>20)
+		}
+	}
+}
+
+// TestLinesLargeSimilar prüft, dass verstreute Änderungen in großen
+// Eingaben einzeln gefunden werden
+func TestLinesLargeSimilar(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	a := make([]string, 50000)
+	for i := range a {
+		a[i] = fmt.Sprintf("zeile %d", i)
+	}
+	b := append([]string(nil), a...)
+	for i := 0; i < 3000; i++ {
+		b[rng.Intn(len(b))] = "geändert"
+	}
+	if changes := apply(t, a, b, Lines(a, b)); changes > 6000 {
+		t.Errorf("%d Änderungen für höchstens 3000 geänderte Zeilen", changes)
+	}
+}
+
+func TestChars(t *testing.T) {
+	oldRanges, newRanges := Chars("Grüße aus Köln", "Grüße nach Köln")
+	if fmt.Sprint(oldRanges) != "[{9 11}]" || fmt.Sprint(newRanges) != "[{8 9} {10 12}]" {
+		t.Errorf("Chars = %v, %v", oldRanges, newRanges)
+	}
+
+	// Lange, verschiedene Zeilen
+	a := strings.Repeat("ab", 50000)
+	b := strings.Repeat("cd", 50000)
+	oldRanges, newRanges = Chars(a, b)
+	if fmt.Sprint(oldRanges) != "[{0 100000}]" || fmt.Sprint(newRanges) != "[{0 100000}]" {
+		t.Errorf("Chars langer Zeilen = %v, %v", oldRanges, newRanges)
+	}
+}
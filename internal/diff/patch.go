@@ -0,0 +1,221 @@
+package diff
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineKind beschreibt die Rolle einer Zeile in einem Patch
+type LineKind int
+
+const (
+	PatchOther      LineKind = iota // Text außerhalb von Dateien, z. B. Commit-Nachricht
+	PatchFileHeader                 // diff --git, index, ---, +++ usw.
+	PatchHunkHeader                 // @@ -a,b +c,d @@
+	PatchContext
+	PatchAdded
+	PatchRemoved
+)
+
+// PatchHunk ist ein Abschnitt eines Patches
+type PatchHunk struct {
+	Line     int // Zeile des Hunk-Kopfs, 0-basiert
+	OldStart int
+	NewStart int
+}
+
+// PatchFile ist eine Datei in einem Patch
+type PatchFile struct {
+	Name    string
+	Line    int // Erste Kopfzeile, 0-basiert
+	Added   int
+	Removed int
+	Hunks   []PatchHunk
+}
+
+// Patch ist ein eingelesener Unified Diff
+type Patch struct {
+	Files []PatchFile
+	Kinds []LineKind // Rolle jeder Zeile
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParsePatch erkennt einen Unified Diff (z. B. aus git diff) und zerlegt ihn
+// in Dateien und Hunks. ok ist false, wenn der Text kein Patch ist.
+func ParsePatch(lines []string) (patch *Patch, ok bool) {
+	patch = &Patch{Kinds: make([]LineKind, len(lines))}
+
+	var file *PatchFile
+	oldLeft, newLeft := 0, 0
+	startFile := func(line int) {
+		patch.Files = append(patch.Files, PatchFile{Line: line})
+		file = &patch.Files[len(patch.Files)-1]
+	}
+
+	for i, line := range lines {
+		// Innerhalb eines Hunks bestimmen die Zeilenzähler die Rolle
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				patch.Kinds[i] = PatchAdded
+				file.Added++
+				newLeft--
+				continue
+			case strings.HasPrefix(line, "-"):
+				patch.Kinds[i] = PatchRemoved
+				file.Removed++
+				oldLeft--
+				continue
+			case strings.HasPrefix(line, " "), line == "":
+				patch.Kinds[i] = PatchContext
+				oldLeft--
+				newLeft--
+				continue
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+				patch.Kinds[i] = PatchContext
+				continue
+			}
+			oldLeft, newLeft = 0, 0
+		}
+
+		switch {
+		case strings.HasPrefix(line, `\`) && i > 0 && inHunk(patch.Kinds[i-1]):
+			// "\ No newline at end of file" nach der letzten Zeile des Hunks
+			patch.Kinds[i] = PatchContext
+
+		case strings.HasPrefix(line, "diff "):
+			startFile(i)
+			file.Name = gitFileName(line)
+			patch.Kinds[i] = PatchFileHeader
+
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			// Ohne "diff"-Zeile beginnt die Datei mit den Namenszeilen
+			if file == nil || len(file.Hunks) > 0 {
+				startFile(i)
+			}
+			patch.Kinds[i] = PatchFileHeader
+
+		case strings.HasPrefix(line, "+++ ") && file != nil && len(file.Hunks) == 0:
+			if name := headerFileName(line); name != "" {
+				file.Name = name
+			} else if file.Name == "" {
+				file.Name = headerFileName("+++ " + strings.TrimPrefix(lines[max(i-1, 0)], "--- "))
+			}
+			patch.Kinds[i] = PatchFileHeader
+
+		case hunkHeader.MatchString(line) && file != nil:
+			m := hunkHeader.FindStringSubmatch(line)
+			oldLeft, newLeft = hunkCount(m[2]), hunkCount(m[4])
+			oldStart, _ := strconv.Atoi(m[1])
+			newStart, _ := strconv.Atoi(m[3])
+			file.Hunks = append(file.Hunks, PatchHunk{Line: i, OldStart: oldStart, NewStart: newStart})
+			patch.Kinds[i] = PatchHunkHeader
+
+		case file != nil && len(file.Hunks) == 0:
+			// index, new file mode, similarity usw.
+			patch.Kinds[i] = PatchFileHeader
+
+		default:
+			patch.Kinds[i] = PatchOther
+		}
+	}
+
+	for _, f := range patch.Files {
+		if len(f.Hunks) > 0 {
+			return patch, true
+		}
+	}
+	return patch, false
+}
+
+// FileAt liefert den Index der Datei, zu der eine Zeile (0-basiert) gehört,
+// oder -1 vor der ersten Datei
+func (p *Patch) FileAt(line int) int {
+	index := -1
+	for i, f := range p.Files {
+		if f.Line > line {
+			break
+		}
+		index = i
+	}
+	return index
+}
+
+// HunkAt liefert den Index des Hunks innerhalb einer Datei, zu dem eine Zeile
+// gehört, oder -1 im Dateikopf
+func (p *Patch) HunkAt(file, line int) int {
+	index := -1
+	for i, h := range p.Files[file].Hunks {
+		if h.Line > line {
+			break
+		}
+		index = i
+	}
+	return index
+}
+
+// HunkLines liefert die Zeilen (0-basiert) aller Hunk-Köpfe
+func (p *Patch) HunkLines() []int {
+	var lines []int
+	for _, f := range p.Files {
+		for _, h := range f.Hunks {
+			lines = append(lines, h.Line)
+		}
+	}
+	return lines
+}
+
+// FileLines liefert die erste Zeile (0-basiert) jeder Datei
+func (p *Patch) FileLines() []int {
+	lines := make([]int, len(p.Files))
+	for i, f := range p.Files {
+		lines[i] = f.Line
+	}
+	return lines
+}
+
+// inHunk meldet, ob eine Zeile mit dieser Rolle zum Inhalt eines Hunks gehört
+func inHunk(kind LineKind) bool {
+	return kind == PatchContext || kind == PatchAdded || kind == PatchRemoved
+}
+
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// gitFileName liest den neuen Dateinamen aus "diff --git a/x b/y"
+func gitFileName(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	name := fields[len(fields)-1]
+	if strings.HasPrefix(name, "b/") {
+		return name[2:]
+	}
+	return name
+}
+
+// headerFileName liest den Dateinamen aus "+++ b/y" oder "--- a/x".
+// /dev/null (gelöschte Datei) ergibt "".
+func headerFileName(line string) string {
+	name := strings.TrimSpace(line[4:])
+	// Zeitstempel von diff -u abschneiden
+	if i := strings.Index(name, "\t"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		return name[2:]
+	}
+	return name
+}
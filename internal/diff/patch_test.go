@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// patchLines trennt die erwartete Rolle vom Zeilentext. Jede Zeile beginnt
+// mit einem Zeichen für die Rolle und einem Leerzeichen: O Text außerhalb,
+// F Dateikopf, H Hunk-Kopf, C Kontext, + und -.
+func patchLines(t *testing.T, marked []string) ([]string, []LineKind) {
+	t.Helper()
+	kinds := map[byte]LineKind{
+		'O': PatchOther, 'F': PatchFileHeader, 'H': PatchHunkHeader,
+		'C': PatchContext, '+': PatchAdded, '-': PatchRemoved,
+	}
+	lines := make([]string, len(marked))
+	want := make([]LineKind, len(marked))
+	for i, m := range marked {
+		kind, ok := kinds[m[0]]
+		if !ok || len(m) < 2 || m[1] != ' ' {
+			t.Fatalf("Zeile %d ohne Rolle: %q", i, m)
+		}
+		lines[i], want[i] = m[2:], kind
+	}
+	return lines, want
+}
+
+func TestParsePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		files []PatchFile
+	}{
+		{
+			name: "git mit mehreren Dateien",
+			lines: []string{
+				"O commit 0123456789abcdef",
+				"O Author: Jemand <jemand@example.com>",
+				"O ",
+				"O     Dateien umbenannt und ergänzt",
+				"O ",
+				"F diff --git a/main.go b/main.go",
+				"F index 1111111..2222222 100644",
+				"F --- a/main.go",
+				"F +++ b/main.go",
+				"H @@ -1,3 +1,3 @@ package main",
+				"C  package main",
+				"- -func a() {}",
+				"+ +func b() {}",
+				"C  ",
+				"H @@ -10 +10,2 @@",
+				"C  }",
+				"+ +// Ende",
+				"F diff --git a/alt.txt b/neu.txt",
+				"F similarity index 90%",
+				"F rename from alt.txt",
+				"F rename to neu.txt",
+				"F index 3333333..4444444 100644",
+				"F --- a/alt.txt",
+				"F +++ b/neu.txt",
+				"H @@ -1,2 +1,2 @@",
+				"C  erste",
+				"- -zweite",
+				"+ +Zweite",
+				"F diff --git a/gleich.txt b/anders.txt",
+				"F similarity index 100%",
+				"F rename from gleich.txt",
+				"F rename to anders.txt",
+			},
+			files: []PatchFile{
+				{Name: "main.go", Line: 5, Added: 2, Removed: 1, Hunks: []PatchHunk{{9, 1, 1}, {14, 10, 10}}},
+				{Name: "neu.txt", Line: 17, Added: 1, Removed: 1, Hunks: []PatchHunk{{24, 1, 1}}},
+				{Name: "anders.txt", Line: 28},
+			},
+		},
+		{
+			name: "neue und gelöschte Datei",
+			lines: []string{
+				"F diff --git a/neu.txt b/neu.txt",
+				"F new file mode 100644",
+				"F index 0000000..5555555",
+				"F --- /dev/null",
+				"F +++ b/neu.txt",
+				"H @@ -0,0 +1,2 @@",
+				"+ +eins",
+				"+ +zwei",
+				"F diff --git a/weg.txt b/weg.txt",
+				"F deleted file mode 100644",
+				"F index 6666666..0000000",
+				"F --- a/weg.txt",
+				"F +++ /dev/null",
+				"H @@ -1 +0,0 @@",
+				"- -weg",
+			},
+			files: []PatchFile{
+				{Name: "neu.txt", Line: 0, Added: 2, Hunks: []PatchHunk{{5, 0, 1}}},
+				{Name: "weg.txt", Line: 8, Removed: 1, Hunks: []PatchHunk{{13, 1, 0}}},
+			},
+		},
+		{
+			name: "diff -u ohne git",
+			lines: []string{
+				"F --- alt/a.txt\t2024-01-01 10:00:00.000000000 +0100",
+				"F +++ neu/a.txt\t2024-01-02 10:00:00.000000000 +0100",
+				"H @@ -1,2 +1,2 @@",
+				"C  a",
+				"- --- b",
+				"+ ++++ b",
+				"F --- alt/c.txt",
+				"F +++ /dev/null",
+				"H @@ -1 +0,0 @@",
+				"- -c",
+			},
+			files: []PatchFile{
+				{Name: "neu/a.txt", Line: 0, Added: 1, Removed: 1, Hunks: []PatchHunk{{2, 1, 1}}},
+				{Name: "alt/c.txt", Line: 6, Removed: 1, Hunks: []PatchHunk{{8, 1, 0}}},
+			},
+		},
+		{
+			name: "ohne Zeilenumbruch am Ende",
+			lines: []string{
+				"F diff --git a/a.txt b/a.txt",
+				"F --- a/a.txt",
+				"F +++ b/a.txt",
+				"H @@ -1,2 +1,2 @@",
+				"C  gleich",
+				"- -alt",
+				"C \\ No newline at end of file",
+				"+ +neu",
+				"C \\ No newline at end of file",
+				"F diff --git a/b.txt b/b.txt",
+				"F --- a/b.txt",
+				"F +++ b/b.txt",
+				"H @@ -1 +1 @@",
+				"- -x",
+				"+ +y",
+				"C \\ No newline at end of file",
+			},
+			files: []PatchFile{
+				{Name: "a.txt", Line: 0, Added: 1, Removed: 1, Hunks: []PatchHunk{{3, 1, 1}}},
+				{Name: "b.txt", Line: 9, Added: 1, Removed: 1, Hunks: []PatchHunk{{12, 1, 1}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, want := patchLines(t, tt.lines)
+			patch, ok := ParsePatch(lines)
+			if !ok {
+				t.Fatal("Patch nicht erkannt")
+			}
+			if got := fmt.Sprintf("%+v", patch.Files); got != fmt.Sprintf("%+v", tt.files) {
+				t.Errorf("Dateien\n%s\nerwartet\n%+v", got, tt.files)
+			}
+			for i, kind := range patch.Kinds {
+				if kind != want[i] {
+					t.Errorf("Zeile %d %q: Rolle %d, erwartet %d", i, lines[i], kind, want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParsePatchNoPatch(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"Nur Text\nohne Änderungen",
+		"--- a/x\n+++ b/x\nkein Hunk",
+		"@@ -1 +1 @@\n-a\n+b", // Hunk ohne Datei
+	} {
+		if _, ok := ParsePatch(strings.Split(text, "\n")); ok {
+			t.Errorf("%q als Patch erkannt", text)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+// Package editor enthält ein bearbeitbares Textdokument mit Cursor und
+// Verlauf zum Rückgängigmachen, unabhängig von der Darstellung
+package editor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fase22/tui/internal/rope"
+)
+
+// Position ist eine Stelle im Text: Zeile und Spalte in Runen, 0-basiert
+type Position struct {
+	Line int
+	Col  int
+}
+
+// change ersetzt die Zeilen old ab line durch new
+type change struct {
+	line   int
+	old    []string
+	new    []string
+	before Position // Cursor vor der Änderung
+	after  Position // Cursor nach der Änderung
+}
+
+// Splice ist eine Änderung am Text: Die Zeilen Old ab Line wurden durch
+// New ersetzt
+type Splice struct {
+	Line int
+	Old  []string
+	New  []string
+}
+
+// group ist ein Schritt im Verlauf, z. B. alles, was in einem Zug getippt
+// wurde
+type group struct {
+	id      int
+	changes []change
+}
+
+// Document ist ein Text aus Zeilen mit Cursor. Änderungen werden zu
+// Schritten gruppiert, die sich rückgängig machen und wiederholen lassen.
+type Document struct {
+	text   rope.Rope
+	cursor Position
+	want   int // Gewünschte Spalte bei senkrechter Bewegung
+
+	undo    []group
+	redo    []group
+	open    []change // Änderungen des noch offenen Schritts
+	spliced []Splice // Änderungen seit dem letzten Aufruf von Splices
+	nextID  int
+	savedID int // Schritt, bei dem zuletzt gespeichert wurde
+}
+
+// New erzeugt ein Dokument aus Text mit LF als Zeilenende
+func New(text string) *Document {
+	return &Document{text: rope.New(text)}
+}
+
+// Text liefert den Inhalt mit LF als Zeilenende
+func (d *Document) Text() string {
+	return d.text.String()
+}
+
+// Rope liefert den aktuellen Stand des Inhalts, ohne ihn zusammenzusetzen.
+// Spätere Änderungen wirken sich auf den gelieferten Stand nicht aus.
+func (d *Document) Rope() rope.Rope {
+	return d.text
+}
+
+// LineCount liefert die Anzahl der Zeilen
+func (d *Document) LineCount() int {
+	return d.text.LineCount()
+}
+
+// Line liefert eine Zeile (0-basiert)
+func (d *Document) Line(i int) string {
+	return d.text.Line(i)
+}
+
+// Cursor liefert die Cursorposition
+func (d *Document) Cursor() Position {
+	return d.cursor
+}
+
+// SetCursor setzt den Cursor, die Position wird in den Text gezogen
+func (d *Document) SetCursor(line, col int) {
+	d.cursor.Line = clamp(line, 0, d.text.LineCount()-1)
+	d.cursor.Col = clamp(col, 0, d.lineLen(d.cursor.Line))
+	d.want = d.cursor.Col
+}
+
+// MoveLines bewegt den Cursor um n Zeilen und behält die Spalte bei, soweit
+// die Zeile lang genug ist
+func (d *Document) MoveLines(n int) {
+	d.cursor.Line = clamp(d.cursor.Line+n, 0, d.text.LineCount()-1)
+	d.cursor.Col = min(d.want, d.lineLen(d.cursor.Line))
+}
+
+// MoveCols bewegt den Cursor um n Zeichen, am Zeilenende weiter in die
+// nächste bzw. vorherige Zeile
+func (d *Document) MoveCols(n int) {
+	for ; n > 0; n-- {
+		if d.cursor.Col < d.lineLen(d.cursor.Line) {
+			d.cursor.Col++
+		} else if d.cursor.Line < d.text.LineCount()-1 {
+			d.cursor = Position{Line: d.cursor.Line + 1}
+		}
+	}
+	for ; n < 0; n++ {
+		if d.cursor.Col > 0 {
+			d.cursor.Col--
+		} else if d.cursor.Line > 0 {
+			d.cursor.Line--
+			d.cursor.Col = d.lineLen(d.cursor.Line)
+		}
+	}
+	d.want = d.cursor.Col
+}
+
+// LineStart setzt den Cursor an den Anfang des Textes in der Zeile, steht
+// er schon dort, an den Zeilenanfang
+func (d *Document) LineStart() {
+	indent := len([]rune(leadingSpace(d.text.Line(d.cursor.Line))))
+	if d.cursor.Col == indent {
+		indent = 0
+	}
+	d.cursor.Col = indent
+	d.want = indent
+}
+
+// LineEnd setzt den Cursor an das Zeilenende
+func (d *Document) LineEnd() {
+	d.cursor.Col = d.lineLen(d.cursor.Line)
+	d.want = d.cursor.Col
+}
+
+// Insert fügt Text ohne Zeilenumbrüche am Cursor ein
+func (d *Document) Insert(s string) {
+	line := []rune(d.text.Line(d.cursor.Line))
+	col := d.cursor.Col
+	text := string(line[:col]) + s + string(line[col:])
+	d.replace(d.cursor.Line, 1, []string{text}, Position{Line: d.cursor.Line, Col: col + len([]rune(s))})
+}
+
+// Newline teilt die Zeile am Cursor. Mit autoIndent übernimmt die neue
+// Zeile die Einrückung der alten.
+func (d *Document) Newline(autoIndent bool) {
+	line := []rune(d.text.Line(d.cursor.Line))
+	head, tail := string(line[:d.cursor.Col]), string(line[d.cursor.Col:])
+
+	indent := ""
+	if autoIndent {
+		indent = leadingSpace(head)
+		tail = strings.TrimLeftFunc(tail, isBlank)
+	}
+	// Eine Zeile, die nur aus Einrückung bestand, bleibt nicht als Leerraum stehen
+	if autoIndent && strings.TrimSpace(head) == "" {
+		head = ""
+	}
+	d.replace(d.cursor.Line, 1, []string{head, indent + tail},
+		Position{Line: d.cursor.Line + 1, Col: len([]rune(indent))})
+}
+
+// Backspace löscht das Zeichen vor dem Cursor, am Zeilenanfang wird die
+// Zeile an die vorherige angehängt
+func (d *Document) Backspace() {
+	pos := d.cursor
+	if pos.Col > 0 {
+		line := []rune(d.text.Line(pos.Line))
+		text := string(line[:pos.Col-1]) + string(line[pos.Col:])
+		d.replace(pos.Line, 1, []string{text}, Position{Line: pos.Line, Col: pos.Col - 1})
+		return
+	}
+	if pos.Line == 0 {
+		return
+	}
+	prev := d.text.Line(pos.Line - 1)
+	d.replace(pos.Line-1, 2, []string{prev + d.text.Line(pos.Line)},
+		Position{Line: pos.Line - 1, Col: len([]rune(prev))})
+}
+
+// Delete löscht das Zeichen unter dem Cursor, am Zeilenende wird die
+// nächste Zeile angehängt
+func (d *Document) Delete() {
+	pos := d.cursor
+	line := []rune(d.text.Line(pos.Line))
+	if pos.Col < len(line) {
+		text := string(line[:pos.Col]) + string(line[pos.Col+1:])
+		d.replace(pos.Line, 1, []string{text}, pos)
+		return
+	}
+	if pos.Line == d.text.LineCount()-1 {
+		return
+	}
+	d.replace(pos.Line, 2, []string{d.text.Line(pos.Line) + d.text.Line(pos.Line+1)}, pos)
+}
+
+// DeleteLine löscht die Zeile unter dem Cursor
+func (d *Document) DeleteLine() {
+	line := d.cursor.Line
+	if d.text.LineCount() == 1 {
+		d.replace(0, 1, []string{""}, Position{})
+		return
+	}
+	next := min(line, d.text.LineCount()-2)
+	d.replace(line, 1, nil, Position{Line: next, Col: min(d.cursor.Col, d.lineLenAfter(line, next))})
+}
+
+// DuplicateLine setzt eine Kopie der Zeile unter dem Cursor darunter
+func (d *Document) DuplicateLine() {
+	line := d.text.Line(d.cursor.Line)
+	d.replace(d.cursor.Line, 1, []string{line, line}, Position{Line: d.cursor.Line + 1, Col: d.cursor.Col})
+}
+
+// MoveLine verschiebt die Zeile unter dem Cursor um eine Zeile nach oben
+// (dir < 0) oder unten
+func (d *Document) MoveLine(dir int) {
+	line := d.cursor.Line
+	other := line + 1
+	if dir < 0 {
+		other = line - 1
+	}
+	if other < 0 || other >= d.text.LineCount() {
+		return
+	}
+	first := min(line, other)
+	d.replace(first, 2, []string{d.text.Line(first + 1), d.text.Line(first)}, Position{Line: other, Col: d.cursor.Col})
+}
+
+// JoinLines hängt die nächste Zeile ohne ihre Einrückung an die Zeile unter
+// dem Cursor an, getrennt durch ein Leerzeichen
+func (d *Document) JoinLines() {
+	line := d.cursor.Line
+	if line == d.text.LineCount()-1 {
+		return
+	}
+	head := strings.TrimRightFunc(d.text.Line(line), isBlank)
+	tail := strings.TrimLeftFunc(d.text.Line(line+1), isBlank)
+	sep := " "
+	if head == "" || tail == "" {
+		sep = ""
+	}
+	d.replace(line, 2, []string{head + sep + tail}, Position{Line: line, Col: len([]rune(head))})
+}
+
+// Commit schließt den offenen Schritt ab, die nächste Änderung beginnt einen
+// neuen
+func (d *Document) Commit() {
+	if len(d.open) == 0 {
+		return
+	}
+	d.nextID++
+	d.undo = append(d.undo, group{id: d.nextID, changes: d.open})
+	d.open = nil
+}
+
+// Undo macht den letzten Schritt rückgängig und meldet, ob es einen gab
+func (d *Document) Undo() bool {
+	d.Commit()
+	if len(d.undo) == 0 {
+		return false
+	}
+	g := d.undo[len(d.undo)-1]
+	d.undo = d.undo[:len(d.undo)-1]
+	for i := len(g.changes) - 1; i >= 0; i-- {
+		c := g.changes[i]
+		d.splice(c.line, len(c.new), c.old)
+		d.cursor = c.before
+	}
+	d.want = d.cursor.Col
+	d.redo = append(d.redo, g)
+	return true
+}
+
+// Redo wiederholt den zuletzt rückgängig gemachten Schritt
+func (d *Document) Redo() bool {
+	d.Commit()
+	if len(d.redo) == 0 {
+		return false
+	}
+	g := d.redo[len(d.redo)-1]
+	d.redo = d.redo[:len(d.redo)-1]
+	for _, c := range g.changes {
+		d.splice(c.line, len(c.old), c.new)
+		d.cursor = c.after
+	}
+	d.want = d.cursor.Col
+	d.undo = append(d.undo, g)
+	return true
+}
+
+// Modified meldet, ob sich der Text seit dem letzten Speichern geändert hat
+func (d *Document) Modified() bool {
+	return len(d.open) > 0 || d.currentID() != d.savedID
+}
+
+// MarkSaved merkt den aktuellen Stand als gespeichert
+func (d *Document) MarkSaved() {
+	d.Commit()
+	d.savedID = d.currentID()
+}
+
+func (d *Document) currentID() int {
+	if len(d.undo) == 0 {
+		return 0
+	}
+	return d.undo[len(d.undo)-1].id
+}
+
+// replace ersetzt n Zeilen ab line, merkt die Änderung im offenen Schritt
+// und setzt den Cursor. Aufeinanderfolgende Änderungen derselben Zeile
+// werden zusammengefasst.
+func (d *Document) replace(line, n int, lines []string, cursor Position) {
+	c := change{
+		line:   line,
+		old:    d.text.Lines(line, line+n),
+		new:    lines,
+		before: d.cursor,
+		after:  cursor,
+	}
+	d.splice(line, n, lines)
+	d.cursor = cursor
+	d.want = cursor.Col
+	d.redo = nil
+
+	if last := len(d.open) - 1; last >= 0 {
+		prev := &d.open[last]
+		if prev.line == line && len(prev.new) == 1 && n == 1 && len(lines) == 1 {
+			prev.new = lines
+			prev.after = cursor
+			return
+		}
+	}
+	d.open = append(d.open, c)
+}
+
+// splice ersetzt n Zeilen ab line ohne Verlauf
+func (d *Document) splice(line, n int, lines []string) {
+	d.spliced = append(d.spliced, Splice{Line: line, Old: d.text.Lines(line, line+n), New: lines})
+	d.text.ReplaceLines(line, line+n, lines)
+}
+
+// Splices liefert die Änderungen seit dem letzten Aufruf in ihrer
+// Reihenfolge, auch die durch Undo und Redo, z. B. um Marken nachzuführen
+func (d *Document) Splices() []Splice {
+	spliced := d.spliced
+	d.spliced = nil
+	return spliced
+}
+
+func (d *Document) lineLen(line int) int {
+	return utf8.RuneCountInString(d.text.Line(line))
+}
+
+// lineLenAfter liefert die Länge der Zeile target, wie sie nach dem
+// Löschen der Zeile removed heißt
+func (d *Document) lineLenAfter(removed, target int) int {
+	if target >= removed {
+		target++
+	}
+	return d.lineLen(target)
+}
+
+// leadingSpace liefert die Einrückung einer Zeile
+func leadingSpace(s string) string {
+	return s[:len(s)-len(strings.TrimLeftFunc(s, isBlank))]
+}
+
+func isBlank(r rune) bool {
+	return r == ' ' || r == '\t' || (unicode.IsSpace(r) && r != '\n')
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
@@ -0,0 +1,90 @@
+package editor
+
+import "testing"
+
+func TestUndoRedoAtLastLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		line   int
+		edit   func(d *Document)
+		edited string
+	}{
+		{"letzte Zeile löschen", "a\nb", 1, (*Document).DeleteLine, "a"},
+		{"leere letzte Zeile löschen", "a\nb\n", 2, (*Document).DeleteLine, "a\nb"},
+		{"vorletzte Zeile löschen", "a\nb\n", 1, (*Document).DeleteLine, "a\n"},
+		{"einzige Zeile löschen", "a", 0, (*Document).DeleteLine, ""},
+		{"letzte Zeile verdoppeln", "a\nb", 1, (*Document).DuplicateLine, "a\nb\nb"},
+		{"neue Zeile am Ende", "a\nb", 1, func(d *Document) {
+			d.LineEnd()
+			d.Newline(false)
+			d.Insert("c")
+		}, "a\nb\nc"},
+		{"letzte Zeilen verbinden", "a\nb", 0, (*Document).JoinLines, "a b"},
+		{"letzte Zeile nach oben", "a\nb", 1, func(d *Document) { d.MoveLine(-1) }, "b\na"},
+		{"Rückschritt am Anfang der letzten Zeile", "a\nb", 1, (*Document).Backspace, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New(tt.text)
+			d.SetCursor(tt.line, 0)
+			tt.edit(d)
+			if got := d.Text(); got != tt.edited {
+				t.Fatalf("nach der Änderung %q, erwartet %q", got, tt.edited)
+			}
+			if !d.Undo() {
+				t.Fatal("Undo meldet keinen Schritt")
+			}
+			if got := d.Text(); got != tt.text {
+				t.Fatalf("nach Undo %q, erwartet %q", got, tt.text)
+			}
+			if !d.Redo() {
+				t.Fatal("Redo meldet keinen Schritt")
+			}
+			if got := d.Text(); got != tt.edited {
+				t.Fatalf("nach Redo %q, erwartet %q", got, tt.edited)
+			}
+			if !d.Modified() {
+				t.Error("Modified() nach Redo false")
+			}
+		})
+	}
+}
+
+func TestUndoRestoresCursor(t *testing.T) {
+	d := New("a\nb")
+	d.SetCursor(1, 1)
+	d.DeleteLine()
+	if got := d.Cursor(); got != (Position{Line: 0, Col: 1}) {
+		t.Errorf("Cursor nach DeleteLine %+v", got)
+	}
+	d.Undo()
+	if got := d.Cursor(); got != (Position{Line: 1, Col: 1}) {
+		t.Errorf("Cursor nach Undo %+v", got)
+	}
+}
+
+func TestSplices(t *testing.T) {
+	d := New("a\nb\nc")
+	d.SetCursor(1, 0)
+	d.DeleteLine()
+	d.Undo()
+	d.Redo()
+	want := []Splice{
+		{Line: 1, Old: []string{"b"}},
+		{Line: 1, New: []string{"b"}},
+		{Line: 1, Old: []string{"b"}},
+	}
+	got := d.Splices()
+	if len(got) != len(want) {
+		t.Fatalf("%d Änderungen %v, erwartet %v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i].Line != want[i].Line || len(got[i].Old) != len(want[i].Old) || len(got[i].New) != len(want[i].New) {
+			t.Errorf("Änderung %d = %+v, erwartet %+v", i, got[i], want[i])
+		}
+	}
+	if again := d.Splices(); len(again) != 0 {
+		t.Errorf("Splices liefert dieselben Änderungen erneut: %v", again)
+	}
+}
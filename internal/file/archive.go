@@ -0,0 +1,173 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveKind ist das erkannte Archivformat
+type ArchiveKind int
+
+const (
+	ArchiveNone ArchiveKind = iota
+	ArchiveTar
+	ArchiveZip
+)
+
+func (k ArchiveKind) String() string {
+	switch k {
+	case ArchiveTar:
+		return "tar"
+	case ArchiveZip:
+		return "zip"
+	default:
+		return ""
+	}
+}
+
+// Entry ist ein Eintrag eines Archivs
+type Entry struct {
+	Name    string
+	Size    int64
+	Mode    fs.FileMode
+	ModTime time.Time
+}
+
+// Archive ist ein tar- oder zip-Archiv im Speicher. Einträge werden erst
+// beim Öffnen gelesen und nie auf die Platte entpackt.
+type Archive struct {
+	Kind    ArchiveKind
+	Entries []Entry // Nach Namen sortiert
+	data    string
+	zip     *zip.Reader
+}
+
+// DetectArchive erkennt zip am lokalen Dateikopf und tar am ustar-Kennzeichen
+func DetectArchive(data []byte) ArchiveKind {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		return ArchiveZip
+	case len(data) >= 262 && bytes.Equal(data[257:262], []byte("ustar")):
+		return ArchiveTar
+	}
+	return ArchiveNone
+}
+
+// OpenArchive liest das Inhaltsverzeichnis eines bereits entpackten Archivs
+func OpenArchive(data string) (*Archive, error) {
+	a := &Archive{data: data}
+	a.Kind = DetectArchive([]byte(data[:min(len(data), 512)]))
+
+	switch a.Kind {
+	case ArchiveTar:
+		tr := tar.NewReader(strings.NewReader(data))
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Fehler beim Lesen des tar-Archivs: %w", err)
+			}
+			a.Entries = append(a.Entries, Entry{
+				Name:    hdr.Name,
+				Size:    hdr.Size,
+				Mode:    hdr.FileInfo().Mode(),
+				ModTime: hdr.ModTime,
+			})
+		}
+	case ArchiveZip:
+		zr, err := zip.NewReader(strings.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("Fehler beim Lesen des zip-Archivs: %w", err)
+		}
+		a.zip = zr
+		for _, f := range zr.File {
+			a.Entries = append(a.Entries, Entry{
+				Name:    f.Name,
+				Size:    int64(f.UncompressedSize64),
+				Mode:    f.Mode(),
+				ModTime: f.Modified,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("Kein tar- oder zip-Archiv")
+	}
+
+	sort.SliceStable(a.Entries, func(i, j int) bool {
+		return a.Entries[i].Name < a.Entries[j].Name
+	})
+	return a, nil
+}
+
+// ReadEntry liest einen Eintrag und entpackt ihn bei Bedarf wie ReadFileInfo
+func (a *Archive) ReadEntry(name string, enc Encoding) (string, Info, error) {
+	data, err := a.readEntry(name)
+	if err != nil {
+		return "", Info{}, err
+	}
+	return decode(data, enc)
+}
+
+// ReadEntryRaw liest einen Eintrag und entpackt ihn bei Bedarf wie ReadRaw
+func (a *Archive) ReadEntryRaw(name string) ([]byte, error) {
+	data, err := a.readEntry(name)
+	if err != nil {
+		return nil, err
+	}
+	raw, _, err := Decompress(data)
+	return raw, err
+}
+
+func (a *Archive) readEntry(name string) ([]byte, error) {
+	var data []byte
+	var err error
+	switch a.Kind {
+	case ArchiveTar:
+		data, err = a.readTar(name)
+	case ArchiveZip:
+		data, err = a.readZip(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Fehler beim Lesen von %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func (a *Archive) readTar(name string) ([]byte, error) {
+	tr := tar.NewReader(strings.NewReader(a.data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fs.ErrNotExist
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Name == name {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+func (a *Archive) readZip(name string) ([]byte, error) {
+	for _, f := range a.zip.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fs.ErrNotExist
+}
@@ -0,0 +1,113 @@
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// sniffSize ist die Anzahl der Bytes, die für die Erkennung gelesen werden
+	sniffSize = 8 << 10
+	// maxInvalidRatio ist der Anteil ungültiger UTF-8-Bytes, ab dem ein
+	// Inhalt als binär gilt
+	maxInvalidRatio = 0.1
+)
+
+// IsBinary erkennt binäre Inhalte an NUL-Bytes oder einem hohen Anteil
+// ungültiger UTF-8-Sequenzen
+func IsBinary(data []byte) bool {
+	if len(data) > sniffSize {
+		data = data[:sniffSize]
+	}
+	if len(data) == 0 {
+		return false
+	}
+
+	invalid := 0
+	for i := 0; i < len(data); {
+		if data[i] == 0 {
+			return true
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		// Eine am Ende abgeschnittene Sequenz zählt nicht als ungültig
+		if r == utf8.RuneError && size == 1 && len(data)-i >= utf8.UTFMax {
+			invalid++
+		}
+		i += size
+	}
+	return float64(invalid)/float64(len(data)) > maxInvalidRatio
+}
+
+// Format beschreibt den am Dateianfang erkannten Inhalt
+type Format struct {
+	Compression Compression
+	Archive     ArchiveKind
+	Binary      bool
+}
+
+// Sniff liest den Anfang einer Datei, bei komprimierten Dateien entpackt,
+// und erkennt Kompression, Archive und binären Inhalt
+func Sniff(filename string) (Format, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Format{}, fmt.Errorf("Fehler beim Öffnen der Datei: %w", err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	header, _ := br.Peek(4)
+	c := DetectCompression(header)
+
+	data := make([]byte, sniffSize)
+	n, err := readSample(br, c, data)
+	if err != nil && c == CompressionZlib {
+		// Doch kein zlib, unverändert lesen
+		c = CompressionNone
+		if _, err = f.Seek(0, io.SeekStart); err == nil {
+			n, err = readSample(f, c, data)
+		}
+	}
+	if err != nil {
+		return Format{}, fmt.Errorf("Fehler beim Lesen der Datei: %w", err)
+	}
+	enc, _ := DetectEncoding(data[:n])
+	return Format{
+		Compression: c,
+		Archive:     DetectArchive(data[:n]),
+		Binary:      enc == EncodingAuto,
+	}, nil
+}
+
+func readSample(r io.Reader, c Compression, data []byte) (int, error) {
+	dr, err := decompressor(r, c)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(dr, data)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return n, err
+}
+
+// Printable ersetzt Steuerzeichen und ungültige Bytes durch Punkte, damit
+// binäre Inhalte gefahrlos als Text angezeigt werden können
+func Printable(content string) string {
+	var b strings.Builder
+	b.Grow(len(content))
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		switch {
+		case r == utf8.RuneError && size == 1, r < 0x20 && r != '\t' && r != '\n', r == 0x7f:
+			b.WriteByte('.')
+		default:
+			b.WriteString(content[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package file
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// Compression ist das anhand der Magic Bytes erkannte Kompressionsformat
+type Compression int
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionBzip2
+	CompressionZlib
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionGzip:
+		return "gzip"
+	case CompressionBzip2:
+		return "bzip2"
+	case CompressionZlib:
+		return "zlib"
+	default:
+		return ""
+	}
+}
+
+// Info beschreibt eine gelesene Datei
+type Info struct {
+	Compression Compression
+	Size        int64 // Größe auf dem Datenträger
+	RawSize     int64 // Größe nach dem Entpacken
+	Encoding    Encoding
+	BOM         bool // Der Text begann mit einem Byte Order Mark
+	LineEndings LineEndings
+}
+
+// DetectCompression erkennt gzip, bzip2 und zlib an den ersten Bytes
+func DetectCompression(header []byte) Compression {
+	switch {
+	case len(header) >= 2 && header[0] == 0x1f && header[1] == 0x8b:
+		return CompressionGzip
+	case len(header) >= 3 && bytes.Equal(header[:3], []byte("BZh")):
+		return CompressionBzip2
+	case len(header) >= 2 && header[0] == 0x78 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0:
+		// Deflate mit Prüfsumme im Header, siehe RFC 1950
+		return CompressionZlib
+	}
+	return CompressionNone
+}
+
+// decompressor liefert einen Reader, der beim Lesen entpackt
+func decompressor(r io.Reader, c Compression) (io.Reader, error) {
+	switch c {
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionBzip2:
+		return bzip2.NewReader(r), nil
+	case CompressionZlib:
+		return zlib.NewReader(r)
+	}
+	return r, nil
+}
+
+// Decompress entpackt Daten, falls sie komprimiert sind. Da der zlib-Header
+// auch am Anfang gewöhnlicher Texte stehen kann, gelten nicht entpackbare
+// zlib-Daten als unkomprimiert.
+func Decompress(data []byte) ([]byte, Compression, error) {
+	c := DetectCompression(data)
+	if c == CompressionNone {
+		return data, c, nil
+	}
+
+	r, err := decompressor(bytes.NewReader(data), c)
+	if err == nil {
+		var raw []byte
+		if raw, err = io.ReadAll(r); err == nil {
+			return raw, c, nil
+		}
+	}
+	if c == CompressionZlib {
+		return data, CompressionNone, nil
+	}
+	return nil, c, fmt.Errorf("Fehler beim Entpacken (%s): %w", c, err)
+}
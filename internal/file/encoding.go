@@ -0,0 +1,245 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+// Encoding ist die Zeichenkodierung eines Textes. EncodingAuto steht für
+// automatische Erkennung bzw. für binäre Inhalte, die nicht umgewandelt werden.
+type Encoding int
+
+const (
+	EncodingAuto Encoding = iota
+	EncodingUTF8
+	EncodingUTF16LE
+	EncodingUTF16BE
+	EncodingUTF32LE
+	EncodingUTF32BE
+	EncodingLatin1
+	EncodingWindows1252
+)
+
+var encodingNames = []string{
+	EncodingAuto:        "auto",
+	EncodingUTF8:        "utf-8",
+	EncodingUTF16LE:     "utf-16le",
+	EncodingUTF16BE:     "utf-16be",
+	EncodingUTF32LE:     "utf-32le",
+	EncodingUTF32BE:     "utf-32be",
+	EncodingLatin1:      "latin1",
+	EncodingWindows1252: "windows-1252",
+}
+
+// encodingAliases sind weitere gebräuchliche Schreibweisen
+var encodingAliases = map[string]Encoding{
+	"utf8":       EncodingUTF8,
+	"utf16le":    EncodingUTF16LE,
+	"utf16be":    EncodingUTF16BE,
+	"utf32le":    EncodingUTF32LE,
+	"utf32be":    EncodingUTF32BE,
+	"iso-8859-1": EncodingLatin1,
+	"latin-1":    EncodingLatin1,
+	"cp1252":     EncodingWindows1252,
+}
+
+// boms sind die Byte Order Marks, UTF-32 vor UTF-16 wegen gleichem Anfang
+var boms = []struct {
+	enc Encoding
+	bom []byte
+}{
+	{EncodingUTF8, []byte{0xef, 0xbb, 0xbf}},
+	{EncodingUTF32LE, []byte{0xff, 0xfe, 0x00, 0x00}},
+	{EncodingUTF32BE, []byte{0x00, 0x00, 0xfe, 0xff}},
+	{EncodingUTF16LE, []byte{0xff, 0xfe}},
+	{EncodingUTF16BE, []byte{0xfe, 0xff}},
+}
+
+func (e Encoding) String() string {
+	if e < 0 || int(e) >= len(encodingNames) {
+		return ""
+	}
+	return encodingNames[e]
+}
+
+// EncodingNames liefert alle Namen, die ParseEncoding kennt, ohne Aliase
+func EncodingNames() []string {
+	return append([]string(nil), encodingNames...)
+}
+
+// ParseEncoding sucht eine Kodierung anhand ihres Namens
+func ParseEncoding(name string) (Encoding, error) {
+	name = strings.ToLower(name)
+	for i, n := range encodingNames {
+		if n == name {
+			return Encoding(i), nil
+		}
+	}
+	if enc, ok := encodingAliases[name]; ok {
+		return enc, nil
+	}
+	return EncodingAuto, fmt.Errorf("Unbekannte Kodierung: %s", name)
+}
+
+func (e Encoding) decoder() *encoding.Decoder {
+	switch e {
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+	case EncodingUTF32LE:
+		return utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM).NewDecoder()
+	case EncodingUTF32BE:
+		return utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM).NewDecoder()
+	case EncodingLatin1:
+		return charmap.ISO8859_1.NewDecoder()
+	case EncodingWindows1252:
+		return charmap.Windows1252.NewDecoder()
+	}
+	return nil
+}
+
+// DetectEncoding erkennt die Kodierung am BOM, UTF-16 ohne BOM an der
+// Verteilung der NUL-Bytes und sonst UTF-8 oder, bei ungültigem UTF-8,
+// Windows-1252 bzw. Latin-1. Binäre Inhalte (NUL-Bytes oder viele
+// Steuerzeichen) liefern EncodingAuto. Zurück kommt auch die Länge des BOM.
+func DetectEncoding(data []byte) (Encoding, int) {
+	for _, b := range boms {
+		if bytes.HasPrefix(data, b.bom) {
+			return b.enc, len(b.bom)
+		}
+	}
+
+	sample := data[:min(len(data), sniffSize)]
+	if enc := guessUTF16(sample); enc != EncodingAuto {
+		return enc, 0
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return EncodingAuto, 0
+	}
+	if utf8.Valid(data) {
+		return EncodingUTF8, 0
+	}
+	if controlBytes(sample)*100 > len(sample) {
+		return EncodingAuto, 0
+	}
+	// 0x80 bis 0x9f sind in Latin-1 Steuerzeichen, in Windows-1252 z. B. €
+	for _, c := range data {
+		if c >= 0x80 && c <= 0x9f {
+			return EncodingWindows1252, 0
+		}
+	}
+	return EncodingLatin1, 0
+}
+
+// controlBytes zählt Steuerzeichen, die in Texten nicht vorkommen
+func controlBytes(sample []byte) int {
+	n := 0
+	for _, c := range sample {
+		if c < 0x20 && !strings.ContainsRune("\t\n\v\f\r\x1b", rune(c)) || c == 0x7f {
+			n++
+		}
+	}
+	return n
+}
+
+// guessUTF16 erkennt UTF-16 ohne BOM daran, dass bei überwiegend ASCII-Text
+// fast jedes zweite Byte NUL ist
+func guessUTF16(sample []byte) Encoding {
+	pairs := len(sample) / 2
+	if pairs < 2 {
+		return EncodingAuto
+	}
+	var even, odd int
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			even++
+		}
+		if sample[i+1] == 0 {
+			odd++
+		}
+	}
+	switch {
+	case odd*10 > pairs*4 && even*20 < pairs:
+		return EncodingUTF16LE
+	case even*10 > pairs*4 && odd*20 < pairs:
+		return EncodingUTF16BE
+	}
+	return EncodingAuto
+}
+
+// DecodeText wandelt Text in UTF-8 um und entfernt ein passendes BOM. Bei
+// EncodingAuto wird die Kodierung erkannt. Zurück kommen die verwendete
+// Kodierung und ob ein BOM vorhanden war.
+func DecodeText(data []byte, enc Encoding) (string, Encoding, bool, error) {
+	detected, bom := DetectEncoding(data)
+	if enc == EncodingAuto {
+		enc = detected
+	} else if enc != detected {
+		bom = 0
+	}
+
+	switch enc {
+	case EncodingAuto:
+		return string(data), enc, false, nil
+	case EncodingUTF8:
+		return string(data[bom:]), enc, bom > 0, nil
+	}
+
+	text, err := enc.decoder().Bytes(data[bom:])
+	if err != nil {
+		return "", enc, false, fmt.Errorf("Fehler beim Umwandeln aus %s: %w", enc, err)
+	}
+	return string(text), enc, bom > 0, nil
+}
+
+func (e Encoding) encoder() *encoding.Encoder {
+	switch e {
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewEncoder()
+	case EncodingUTF32LE:
+		return utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM).NewEncoder()
+	case EncodingUTF32BE:
+		return utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM).NewEncoder()
+	case EncodingLatin1:
+		return charmap.ISO8859_1.NewEncoder()
+	case EncodingWindows1252:
+		return charmap.Windows1252.NewEncoder()
+	}
+	return nil
+}
+
+// EncodeText wandelt UTF-8-Text in eine Kodierung um und stellt auf Wunsch
+// das passende BOM voran. Zeichen, die die Kodierung nicht kennt, ergeben
+// einen Fehler.
+func EncodeText(text string, enc Encoding, bom bool) ([]byte, error) {
+	var data []byte
+	switch enc {
+	case EncodingAuto, EncodingUTF8:
+		data = []byte(text)
+	default:
+		encoded, err := enc.encoder().Bytes([]byte(text))
+		if err != nil {
+			return nil, fmt.Errorf("Text lässt sich nicht in %s umwandeln: %w", enc, err)
+		}
+		data = encoded
+	}
+	if !bom {
+		return data, nil
+	}
+	for _, b := range boms {
+		if b.enc == enc {
+			return append(append([]byte(nil), b.bom...), data...), nil
+		}
+	}
+	return data, nil
+}
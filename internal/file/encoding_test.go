@@ -0,0 +1,88 @@
+package file
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeText(t *testing.T) {
+	// Jeweils "Aü\n", bei Windows-1252 "€ü\n"
+	tests := []struct {
+		name string
+		data []byte
+		enc  Encoding // Vorgabe, EncodingAuto erkennt die Kodierung
+		want Encoding
+		bom  bool
+		text string
+	}{
+		{"utf-8", []byte{0x41, 0xc3, 0xbc, 0x0a}, EncodingAuto, EncodingUTF8, false, "Aü\n"},
+		{"utf-8 mit BOM", []byte{0xef, 0xbb, 0xbf, 0x41, 0xc3, 0xbc, 0x0a}, EncodingAuto, EncodingUTF8, true, "Aü\n"},
+		{"utf-16le", []byte{0x41, 0x00, 0xfc, 0x00, 0x0a, 0x00}, EncodingAuto, EncodingUTF16LE, false, "Aü\n"},
+		{"utf-16le mit BOM", []byte{0xff, 0xfe, 0x41, 0x00, 0xfc, 0x00, 0x0a, 0x00}, EncodingAuto, EncodingUTF16LE, true, "Aü\n"},
+		{"utf-16be", []byte{0x00, 0x41, 0x00, 0xfc, 0x00, 0x0a}, EncodingAuto, EncodingUTF16BE, false, "Aü\n"},
+		{"utf-16be mit BOM", []byte{0xfe, 0xff, 0x00, 0x41, 0x00, 0xfc, 0x00, 0x0a}, EncodingAuto, EncodingUTF16BE, true, "Aü\n"},
+		{"utf-32le", []byte{0x41, 0, 0, 0, 0xfc, 0, 0, 0, 0x0a, 0, 0, 0}, EncodingUTF32LE, EncodingUTF32LE, false, "Aü\n"},
+		{"utf-32le mit BOM", []byte{0xff, 0xfe, 0, 0, 0x41, 0, 0, 0, 0xfc, 0, 0, 0, 0x0a, 0, 0, 0}, EncodingAuto, EncodingUTF32LE, true, "Aü\n"},
+		{"utf-32be", []byte{0, 0, 0, 0x41, 0, 0, 0, 0xfc, 0, 0, 0, 0x0a}, EncodingUTF32BE, EncodingUTF32BE, false, "Aü\n"},
+		{"utf-32be mit BOM", []byte{0, 0, 0xfe, 0xff, 0, 0, 0, 0x41, 0, 0, 0, 0xfc, 0, 0, 0, 0x0a}, EncodingAuto, EncodingUTF32BE, true, "Aü\n"},
+		{"latin1", []byte{0x41, 0xfc, 0x0a}, EncodingAuto, EncodingLatin1, false, "Aü\n"},
+		{"windows-1252", []byte{0x80, 0xfc, 0x0a}, EncodingAuto, EncodingWindows1252, false, "€ü\n"},
+		{"latin1 vorgegeben", []byte{0x80, 0xfc, 0x0a}, EncodingLatin1, EncodingLatin1, false, "\u0080ü\n"},
+		{"BOM anderer Kodierung", []byte{0xef, 0xbb, 0xbf, 0x41}, EncodingLatin1, EncodingLatin1, false, "ï»¿A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, enc, bom, err := DecodeText(tt.data, tt.enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text != tt.text || enc != tt.want || bom != tt.bom {
+				t.Errorf("DecodeText = %q, %s, BOM %v, erwartet %q, %s, BOM %v",
+					text, enc, bom, tt.text, tt.want, tt.bom)
+			}
+
+			// Zurückgeschrieben ergeben sich wieder dieselben Bytes
+			data, err := EncodeText(text, enc, bom)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Errorf("EncodeText = % x, erwartet % x", data, tt.data)
+			}
+		})
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Encoding
+		bom  int
+	}{
+		{"leer", nil, EncodingUTF8, 0},
+		{"ascii", []byte("hallo\n"), EncodingUTF8, 0},
+		{"nur BOM", []byte{0xef, 0xbb, 0xbf}, EncodingUTF8, 3},
+		{"utf-32le vor utf-16le", []byte{0xff, 0xfe, 0, 0}, EncodingUTF32LE, 4},
+		{"utf-16le ohne BOM", []byte("z\x00e\x00i\x00l\x00e\x00\n\x00"), EncodingUTF16LE, 0},
+		{"utf-16be ohne BOM", []byte("\x00z\x00e\x00i\x00l\x00e\x00\n"), EncodingUTF16BE, 0},
+		{"NUL-Bytes", []byte("text\x00mit\x00nul"), EncodingAuto, 0},
+		{"Steuerzeichen", []byte("\x01\x02\x03\x04\x05\xff"), EncodingAuto, 0},
+		{"latin1", []byte("Stra\xdfe"), EncodingLatin1, 0},
+		{"windows-1252", []byte("\x84Zitat\x93"), EncodingWindows1252, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, bom := DetectEncoding(tt.data)
+			if enc != tt.want || bom != tt.bom {
+				t.Errorf("DetectEncoding = %s, %d, erwartet %s, %d", enc, bom, tt.want, tt.bom)
+			}
+		})
+	}
+}
+
+func TestEncodeTextUnknownChar(t *testing.T) {
+	if data, err := EncodeText("5 €", EncodingLatin1, false); err == nil {
+		t.Errorf("EncodeText = % x, erwartet einen Fehler", data)
+	}
+}
@@ -0,0 +1,131 @@
+package file
+
+import "strings"
+
+// LineEnding ist die Art eines Zeilenendes
+type LineEnding int
+
+const (
+	LineEndingNone LineEnding = iota // Text ohne Zeilenumbruch
+	LineEndingLF
+	LineEndingCRLF
+	LineEndingCR
+)
+
+func (e LineEnding) String() string {
+	switch e {
+	case LineEndingLF:
+		return "LF"
+	case LineEndingCRLF:
+		return "CRLF"
+	case LineEndingCR:
+		return "CR"
+	default:
+		return ""
+	}
+}
+
+func (e LineEnding) bytes() string {
+	switch e {
+	case LineEndingCRLF:
+		return "\r\n"
+	case LineEndingCR:
+		return "\r"
+	default:
+		return "\n"
+	}
+}
+
+// LineEndings beschreibt die Zeilenenden eines Textes. Zusammen mit dem
+// normalisierten Text lassen sich die ursprünglichen Bytes wiederherstellen.
+type LineEndings struct {
+	Style      LineEnding         // Überwiegendes Zeilenende
+	Deviations map[int]LineEnding // Abweichende Zeilen (0-basiert)
+}
+
+// Mixed meldet, ob der Text unterschiedliche Zeilenenden enthält
+func (le LineEndings) Mixed() bool {
+	return len(le.Deviations) > 0
+}
+
+func (le LineEndings) String() string {
+	if le.Mixed() {
+		return "gemischt " + le.Style.String()
+	}
+	return le.Style.String()
+}
+
+// NormalizeLineEndings wandelt CRLF und CR in LF um und merkt sich, welche
+// Zeilen vom überwiegenden Zeilenende abweichen
+func NormalizeLineEndings(text string) (string, LineEndings) {
+	if !strings.Contains(text, "\r") {
+		le := LineEndings{}
+		if strings.Contains(text, "\n") {
+			le.Style = LineEndingLF
+		}
+		return text, le
+	}
+
+	var (
+		b      strings.Builder
+		ends   []LineEnding
+		counts [LineEndingCR + 1]int
+	)
+	b.Grow(len(text))
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\r' && i+1 < len(text) && text[i+1] == '\n':
+			ends = append(ends, LineEndingCRLF)
+			i++
+		case c == '\r':
+			ends = append(ends, LineEndingCR)
+		case c == '\n':
+			ends = append(ends, LineEndingLF)
+		default:
+			b.WriteByte(c)
+			continue
+		}
+		counts[ends[len(ends)-1]]++
+		b.WriteByte('\n')
+	}
+
+	le := LineEndings{Style: LineEndingLF}
+	for e := LineEndingLF; e <= LineEndingCR; e++ {
+		if counts[e] > counts[le.Style] {
+			le.Style = e
+		}
+	}
+	for line, e := range ends {
+		if e != le.Style {
+			if le.Deviations == nil {
+				le.Deviations = make(map[int]LineEnding)
+			}
+			le.Deviations[line] = e
+		}
+	}
+	return b.String(), le
+}
+
+// Restore setzt die ursprünglichen Zeilenenden in einen normalisierten
+// Text wieder ein
+func (le LineEndings) Restore(text string) string {
+	if le.Style <= LineEndingLF && !le.Mixed() {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	var b strings.Builder
+	b.Grow(len(text) + len(lines))
+	for i, line := range lines {
+		b.WriteString(line)
+		if i == len(lines)-1 {
+			break
+		}
+		if e, ok := le.Deviations[i]; ok {
+			b.WriteString(e.bytes())
+		} else {
+			b.WriteString(le.Style.bytes())
+		}
+	}
+	return b.String()
+}
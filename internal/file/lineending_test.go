@@ -0,0 +1,45 @@
+package file
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNormalizeLineEndings(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		normalized string
+		style      LineEnding
+		deviations map[int]LineEnding
+	}{
+		{"leer", "", "", LineEndingNone, nil},
+		{"ohne Umbruch", "eine Zeile", "eine Zeile", LineEndingNone, nil},
+		{"LF", "a\nb\n", "a\nb\n", LineEndingLF, nil},
+		{"CRLF", "a\r\nb\r\n", "a\nb\n", LineEndingCRLF, nil},
+		{"CR", "a\rb\r", "a\nb\n", LineEndingCR, nil},
+		{"LF ohne letzten Umbruch", "a\nb", "a\nb", LineEndingLF, nil},
+		{"CRLF ohne letzten Umbruch", "a\r\nb", "a\nb", LineEndingCRLF, nil},
+		{"gemischt", "a\r\nb\nc\r\nd\re", "a\nb\nc\nd\ne", LineEndingCRLF,
+			map[int]LineEnding{1: LineEndingLF, 3: LineEndingCR}},
+		{"gleich viele", "a\nb\r\n", "a\nb\n", LineEndingLF, map[int]LineEnding{1: LineEndingCRLF}},
+		{"einzelnes CR am Ende", "a\r\nb\r\nc\r", "a\nb\nc\n", LineEndingCRLF, map[int]LineEnding{2: LineEndingCR}},
+		{"nur CR", "\r", "\n", LineEndingCR, nil},
+		{"CR vor LF-Zeile", "a\r\nb", "a\nb", LineEndingCRLF, nil},
+		{"leere Zeilen", "\r\n\r\n\n", "\n\n\n", LineEndingCRLF, map[int]LineEnding{2: LineEndingLF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, le := NormalizeLineEndings(tt.text)
+			if normalized != tt.normalized {
+				t.Errorf("normalisiert %q, erwartet %q", normalized, tt.normalized)
+			}
+			if le.Style != tt.style || fmt.Sprint(le.Deviations) != fmt.Sprint(tt.deviations) {
+				t.Errorf("Zeilenenden %s %v, erwartet %s %v", le.Style, le.Deviations, tt.style, tt.deviations)
+			}
+			if restored := le.Restore(normalized); restored != tt.text {
+				t.Errorf("Restore = %q, erwartet %q", restored, tt.text)
+			}
+		})
+	}
+}
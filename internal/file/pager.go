@@ -0,0 +1,184 @@
+package file
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// DefaultPageSize ist die Größe einer Seite im Cache
+	DefaultPageSize = 64 << 10
+	// DefaultCachePages begrenzt die Anzahl der gleichzeitig gehaltenen Seiten
+	DefaultCachePages = 64
+)
+
+type page struct {
+	index int64
+	data  []byte
+}
+
+// Pager liest eine Datei seitenweise und hält die zuletzt benutzten Seiten
+// in einem LRU-Cache. So bleibt der Speicherbedarf auch bei sehr großen
+// Dateien begrenzt.
+type Pager struct {
+	src      io.ReaderAt
+	closer   io.Closer
+	size     int64
+	pageSize int
+	maxPages int
+
+	mu    sync.Mutex
+	pages map[int64]*list.Element
+	lru   *list.List // Vorne die zuletzt benutzte Seite
+}
+
+// NewPager erzeugt einen Pager über einer beliebigen Quelle
+func NewPager(src io.ReaderAt, size int64, pageSize, maxPages int) *Pager {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if maxPages <= 0 {
+		maxPages = DefaultCachePages
+	}
+	return &Pager{
+		src:      src,
+		size:     size,
+		pageSize: pageSize,
+		maxPages: maxPages,
+		pages:    make(map[int64]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// OpenPaged öffnet eine Datei für den seitenweisen Zugriff
+func OpenPaged(filename string) (*Pager, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Fehler beim Öffnen der Datei: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("Fehler beim Lesen der Datei: %w", err)
+	}
+
+	p := NewPager(f, info.Size(), DefaultPageSize, DefaultCachePages)
+	p.closer = f
+	return p, nil
+}
+
+// Size liefert die Größe der Quelle in Bytes
+func (p *Pager) Size() int64 {
+	return p.size
+}
+
+// Close schließt die zugrunde liegende Datei
+func (p *Pager) Close() error {
+	if p.closer == nil {
+		return nil
+	}
+	return p.closer.Close()
+}
+
+// ReadAt erfüllt io.ReaderAt und liest über den Seiten-Cache
+func (p *Pager) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("Ungültiger Offset: %d", off)
+	}
+
+	n := 0
+	for n < len(b) {
+		pos := off + int64(n)
+		if pos >= p.size {
+			return n, io.EOF
+		}
+
+		data, err := p.page(pos / int64(p.pageSize))
+		if err != nil {
+			return n, err
+		}
+		start := int(pos % int64(p.pageSize))
+		if start >= len(data) {
+			return n, io.EOF
+		}
+		n += copy(b[n:], data[start:])
+	}
+	return n, nil
+}
+
+// page liefert eine Seite aus dem Cache oder liest sie nach
+func (p *Pager) page(index int64) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if el, ok := p.pages[index]; ok {
+		p.lru.MoveToFront(el)
+		return el.Value.(*page).data, nil
+	}
+
+	start := index * int64(p.pageSize)
+	length := min(int64(p.pageSize), p.size-start)
+	data := make([]byte, length)
+	n, err := p.src.ReadAt(data, start)
+	if err != nil && !(err == io.EOF && int64(n) == length) {
+		return nil, fmt.Errorf("Fehler beim Lesen der Datei: %w", err)
+	}
+
+	p.pages[index] = p.lru.PushFront(&page{index: index, data: data})
+	if p.lru.Len() > p.maxPages {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.pages, oldest.Value.(*page).index)
+	}
+	return data, nil
+}
+
+// Index sucht das erste Vorkommen von pattern ab Offset from, -1 wenn
+// es keines gibt
+func (p *Pager) Index(pattern []byte, from int64) (int64, error) {
+	if len(pattern) == 0 {
+		return -1, nil
+	}
+
+	chunk := make([]byte, p.pageSize+len(pattern)-1)
+	for pos := max(from, 0); pos < p.size; pos += int64(p.pageSize) {
+		n, err := p.ReadAt(chunk, pos)
+		if err != nil && err != io.EOF {
+			return -1, err
+		}
+		if i := bytes.Index(chunk[:n], pattern); i >= 0 {
+			return pos + int64(i), nil
+		}
+	}
+	return -1, nil
+}
+
+// LastIndex sucht das letzte Vorkommen von pattern, das vor Offset before
+// beginnt, -1 wenn es keines gibt
+func (p *Pager) LastIndex(pattern []byte, before int64) (int64, error) {
+	if len(pattern) == 0 {
+		return -1, nil
+	}
+
+	end := min(before+int64(len(pattern))-1, p.size)
+	chunk := make([]byte, p.pageSize+len(pattern)-1)
+	for end > 0 {
+		pos := max(end-int64(len(chunk)), 0)
+		n, err := p.ReadAt(chunk[:end-pos], pos)
+		if err != nil && err != io.EOF {
+			return -1, err
+		}
+		if i := bytes.LastIndex(chunk[:n], pattern); i >= 0 {
+			return pos + int64(i), nil
+		}
+		end = pos + int64(len(pattern)) - 1
+		if pos == 0 {
+			break
+		}
+	}
+	return -1, nil
+}
@@ -4,20 +4,110 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 )
 
-// ReadFile liest eine Textdatei und gibt deren Inhalt zurück
+// ReadFile liest eine Textdatei und gibt deren Inhalt zurück. "-" liest die
+// Standardeingabe. Komprimierte Dateien werden transparent entpackt, Texte
+// aus ihrer erkannten Kodierung nach UTF-8 umgewandelt.
 func ReadFile(filename string) (string, error) {
+	content, _, err := ReadFileInfo(filename, EncodingAuto)
+	return content, err
+}
+
+// ReadFileInfo liest eine Datei wie ReadFile in der angegebenen Kodierung
+// und liefert zusätzlich Kompression, Kodierung und Größen
+func ReadFileInfo(filename string, enc Encoding) (string, Info, error) {
+	data, err := readData(filename)
+	if err != nil {
+		return "", Info{}, err
+	}
+	return decode(data, enc)
+}
+
+// ReadRaw liest eine Datei und entpackt sie bei Bedarf. Kodierung und
+// Zeilenenden bleiben unverändert, z. B. für die Hexansicht.
+func ReadRaw(filename string) ([]byte, error) {
+	data, err := readData(filename)
+	if err != nil {
+		return nil, err
+	}
+	raw, _, err := Decompress(data)
+	return raw, err
+}
+
+// readData liest eine Datei oder mit "-" die Standardeingabe unverändert
+func readData(filename string) ([]byte, error) {
+	if filename == "-" {
+		return ReadStdin()
+	}
+
 	file, err := os.Open(filename)
 	if err != nil {
-		return "", fmt.Errorf("Fehler beim Öffnen der Datei: %w", err)
+		return nil, fmt.Errorf("Fehler beim Öffnen der Datei: %w", err)
 	}
 	defer file.Close()
 
-	content, err := io.ReadAll(file)
+	data, err := io.ReadAll(file)
 	if err != nil {
-		return "", fmt.Errorf("Fehler beim Lesen der Datei: %w", err)
+		return nil, fmt.Errorf("Fehler beim Lesen der Datei: %w", err)
 	}
+	return data, nil
+}
 
-	return string(content), nil
+// decode entpackt gelesene Daten, wandelt Texte nach UTF-8 und ihre
+// Zeilenenden nach LF um und ermittelt die Größen. Archive bleiben
+// unverändert.
+func decode(data []byte, enc Encoding) (string, Info, error) {
+	raw, compression, err := Decompress(data)
+	if err != nil {
+		return "", Info{}, err
+	}
+	info := Info{
+		Compression: compression,
+		Size:        int64(len(data)),
+		RawSize:     int64(len(raw)),
+	}
+	if DetectArchive(raw[:min(len(raw), 512)]) != ArchiveNone {
+		return string(raw), info, nil
+	}
+
+	text, enc, bom, err := DecodeText(raw, enc)
+	if err != nil {
+		return "", Info{}, err
+	}
+	info.Encoding = enc
+	info.BOM = bom
+	if enc != EncodingAuto {
+		text, info.LineEndings = NormalizeLineEndings(text)
+	}
+	return text, info, nil
+}
+
+var stdin struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// ReadStdin liest die Standardeingabe vollständig und unverändert. Sie lässt
+// sich nur einmal lesen, daher liefern weitere Aufrufe den
+// zwischengespeicherten Inhalt.
+func ReadStdin() ([]byte, error) {
+	stdin.once.Do(func() {
+		stdin.data, stdin.err = io.ReadAll(os.Stdin)
+		if stdin.err != nil {
+			stdin.err = fmt.Errorf("Fehler beim Lesen der Standardeingabe: %w", stdin.err)
+		}
+	})
+	return stdin.data, stdin.err
+}
+
+// IsPiped meldet, ob die Standardeingabe umgeleitet ist (z. B. git diff | reader)
+func IsPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
 }
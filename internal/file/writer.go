@@ -0,0 +1,64 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileInfo schreibt normalisierten Text zurück in eine Datei, mit
+// Kodierung, BOM und Zeilenende aus info. Gemischte Zeilenenden werden dabei
+// auf das überwiegende vereinheitlicht. Geschrieben wird in eine temporäre
+// Datei im selben Verzeichnis, die anschließend die alte ersetzt; Rechte
+// der alten Datei bleiben erhalten, symbolische Links zeigen weiter auf die
+// neue.
+func WriteFileInfo(filename, text string, info Info) error {
+	if info.Compression != CompressionNone {
+		return fmt.Errorf("Komprimierte Dateien lassen sich nicht speichern")
+	}
+	text = LineEndings{Style: info.LineEndings.Style}.Restore(text)
+	data, err := EncodeText(text, info.Encoding, info.BOM)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(filename, data)
+}
+
+// writeAtomic ersetzt eine Datei über eine temporäre Datei und rename, damit
+// nach einem Abbruch nie eine halb geschriebene Datei zurückbleibt
+func writeAtomic(filename string, data []byte) error {
+	if target, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = target
+	}
+	perm := os.FileMode(0o644)
+	if stat, err := os.Stat(filename); err == nil {
+		perm = stat.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return fmt.Errorf("Fehler beim Speichern: %w", err)
+	}
+	// Nach erfolgreichem rename existiert die temporäre Datei nicht mehr
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Fehler beim Speichern: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Fehler beim Speichern: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Fehler beim Speichern: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Fehler beim Speichern: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("Fehler beim Speichern: %w", err)
+	}
+	return nil
+}
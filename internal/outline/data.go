@@ -0,0 +1,64 @@
+package outline
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// JSON liefert die Schlüssel des Objekts auf oberster Ebene
+func JSON(src string) []Symbol {
+	dec := json.NewDecoder(strings.NewReader(src))
+	var symbols []Symbol
+	depth := 0
+	key := true // Im Objekt wechseln sich Schlüssel und Werte ab
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return symbols
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			if t == '[' && depth == 0 {
+				return nil // Arrays haben keine Schlüssel
+			}
+			switch t {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			if depth == 1 && (t == '}' || t == ']') {
+				key = true
+			}
+			continue
+		case string:
+			if depth == 1 && key {
+				// Der Offset liegt vor dem Trenner, der Schlüssel folgt danach
+				start := int(offset) + strings.IndexByte(src[offset:], '"')
+				line := strings.Count(src[:start], "\n") + 1
+				symbols = append(symbols, Symbol{Name: t, Kind: "key", Level: 1, Line: line})
+				key = false
+				continue
+			}
+		}
+		if depth == 1 {
+			key = true
+		}
+	}
+}
+
+var yamlKeyRe = regexp.MustCompile(`^([^\s#\-][^:#]*|"[^"]*"|'[^']*'):(\s|$)`)
+
+// YAML liefert die Schlüssel der obersten Ebene aller Dokumente einer Datei
+func YAML(src string) []Symbol {
+	var symbols []Symbol
+	for i, line := range strings.Split(src, "\n") {
+		if m := yamlKeyRe.FindStringSubmatch(line); m != nil {
+			name := strings.Trim(strings.TrimSpace(m[1]), `"'`)
+			symbols = append(symbols, Symbol{Name: name, Kind: "key", Level: 1, Line: i + 1})
+		}
+	}
+	return symbols
+}
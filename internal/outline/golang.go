@@ -0,0 +1,72 @@
+package outline
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// Go liefert die Deklarationen der obersten Ebene. Auch eine fehlerhafte
+// Datei liefert alle Deklarationen, die der Parser bis zum Fehler erkennt.
+func Go(name, src string) []Symbol {
+	fset := token.NewFileSet()
+	file, _ := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+	if file == nil {
+		return nil
+	}
+
+	var symbols []Symbol
+	add := func(name, kind string, pos token.Pos) {
+		symbols = append(symbols, Symbol{Name: name, Kind: kind, Level: 1, Line: fset.Position(pos).Line})
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			kind := "func"
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = "(" + receiver(d.Recv.List[0].Type) + ") " + name
+				kind = "method"
+			}
+			add(name, kind, d.Pos())
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					kind := "type"
+					switch s.Type.(type) {
+					case *ast.StructType:
+						kind = "struct"
+					case *ast.InterfaceType:
+						kind = "interface"
+					}
+					add(s.Name.Name, kind, s.Pos())
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if n.Name != "_" {
+							add(n.Name, d.Tok.String(), n.Pos())
+						}
+					}
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// receiver liefert den Typ eines Empfängers ohne Typparameter, z. B. *Model
+func receiver(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + receiver(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiver(t.X)
+	case *ast.IndexListExpr:
+		return receiver(t.X)
+	}
+	return "?"
+}
@@ -0,0 +1,55 @@
+package outline
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingRe = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	setextRe  = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	fenceRe   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// Markdown liefert die Überschriften. Zeilen in Codeblöcken zählen nicht.
+func Markdown(src string) []Symbol {
+	var symbols []Symbol
+	lines := strings.Split(src, "\n")
+	fence := ""
+	for i, line := range lines {
+		if m := fenceRe.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1][:1]
+			case strings.HasPrefix(m[1], fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			symbols = append(symbols, heading(m[2], len(m[1]), i+1))
+			continue
+		}
+		// Setext-Überschriften sind durch die folgende Zeile unterstrichen
+		text := strings.TrimSpace(line)
+		if text != "" && i+1 < len(lines) && setextRe.MatchString(lines[i+1]) &&
+			!strings.HasPrefix(text, "-") && !strings.HasPrefix(text, "|") {
+			level := 1
+			if strings.Contains(lines[i+1], "-") {
+				level = 2
+			}
+			symbols = append(symbols, heading(text, level, i+1))
+		}
+	}
+	return symbols
+}
+
+func heading(text string, level, line int) Symbol {
+	text = strings.NewReplacer("**", "", "__", "", "`", "").Replace(text)
+	return Symbol{Name: text, Kind: fmt.Sprintf("h%d", level), Level: level, Line: line}
+}
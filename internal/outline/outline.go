@@ -0,0 +1,58 @@
+// Package outline ermittelt die Gliederung einer Datei: Überschriften in
+// Markdown, Deklarationen in Go sowie Schlüssel der obersten Ebene in JSON
+// und YAML.
+package outline
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// Symbol ist ein Eintrag der Gliederung
+type Symbol struct {
+	Name  string
+	Kind  string // z. B. "func", "type", "h2", "key"
+	Level int    // Verschachtelung ab 1, z. B. die Ebene einer Überschrift
+	Line  int    // Quellzeile, 1-basiert
+}
+
+// Extract wählt den Parser nach Dateiendung, ohne passende Endung wird JSON
+// am Inhalt erkannt. Unbekannte Formate haben keine Gliederung.
+func Extract(name, src string) []Symbol {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return Markdown(src)
+	case ".go":
+		return Go(name, src)
+	case ".json":
+		return JSON(src)
+	case ".yaml", ".yml":
+		return YAML(src)
+	}
+
+	trimmed := strings.TrimSpace(src)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return JSON(src)
+	}
+	return nil
+}
+
+// Match prüft, ob alle Zeichen des Musters in dieser Reihenfolge im Text
+// vorkommen, ohne Rücksicht auf Groß- und Kleinschreibung. "hdl" passt
+// z. B. auf "handleRequest".
+func Match(pattern, text string) bool {
+	text = strings.ToLower(text)
+	for _, r := range strings.ToLower(pattern) {
+		if r == ' ' {
+			continue
+		}
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+utf8.RuneLen(r):]
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package rope
+
+// Change beschreibt, welche Zeilen sich zwischen zwei Ständen geändert
+// haben: ab Line (0-basiert) wurden Old Zeilen durch New Zeilen ersetzt
+type Change struct {
+	Line int
+	Old  int
+	New  int
+}
+
+// Diff vergleicht zwei Stände und liefert den geänderten Zeilenbereich.
+// Gemeinsame Teilbäume werden übersprungen, bei Ständen, die durch
+// Bearbeiten auseinander hervorgehen, kostet der Vergleich daher nur
+// etwa so viel wie die Änderung selbst. false heißt, dass beide gleich sind.
+func Diff(a, b Rope) (Change, bool) {
+	lenA, lenB := a.Len(), b.Len()
+	prefix := commonLen(a.root, b.root, false)
+	if prefix == lenA && prefix == lenB {
+		return Change{}, false
+	}
+	suffix := min(commonLen(a.root, b.root, true), lenA-prefix, lenB-prefix)
+
+	line := a.LineAt(prefix)
+	return Change{
+		Line: line,
+		Old:  a.LineAt(lenA-suffix) - line + 1,
+		New:  b.LineAt(lenB-suffix) - line + 1,
+	}, true
+}
+
+// commonLen liefert die Länge des gemeinsamen Anfangs zweier Bäume in
+// Bytes, mit rev die des gemeinsamen Endes
+func commonLen(a, b *node, rev bool) int {
+	sa, sb := push(nil, a), push(nil, b)
+	n := 0
+	for len(sa) > 0 && len(sb) > 0 {
+		x, y := sa[len(sa)-1], sb[len(sb)-1]
+		switch {
+		case x == y:
+			n += x.length
+			sa, sb = sa[:len(sa)-1], sb[:len(sb)-1]
+		case !x.isLeaf() && (x.length >= y.length || y.isLeaf()):
+			sa = expand(sa, rev)
+		case !y.isLeaf():
+			sb = expand(sb, rev)
+		default:
+			k := commonBytes(x.text, y.text, rev)
+			n += k
+			if k < min(x.length, y.length) {
+				return n
+			}
+			sa, sb = sa[:len(sa)-1], sb[:len(sb)-1]
+			sa = push(sa, rest(x.text, k, rev))
+			sb = push(sb, rest(y.text, k, rev))
+		}
+	}
+	return n
+}
+
+func push(stack []*node, n *node) []*node {
+	if n == nil {
+		return stack
+	}
+	return append(stack, n)
+}
+
+// expand ersetzt den obersten Knoten durch seine Kinder, das in
+// Leserichtung nächste liegt oben
+func expand(stack []*node, rev bool) []*node {
+	n := stack[len(stack)-1]
+	stack = stack[:len(stack)-1]
+	if rev {
+		return append(stack, n.left, n.right)
+	}
+	return append(stack, n.right, n.left)
+}
+
+// rest liefert ein Blatt mit dem noch nicht verglichenen Teil eines Textes
+func rest(s string, k int, rev bool) *node {
+	if rev {
+		return newLeaf(s[:len(s)-k])
+	}
+	return newLeaf(s[k:])
+}
+
+func commonBytes(a, b string, rev bool) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if rev && a[len(a)-1-i] != b[len(b)-1-i] || !rev && a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,367 @@
+// Package rope enthält einen Text als balancierten Baum aus Textstücken.
+// Jeder Knoten kennt Länge und Anzahl der Zeilenumbrüche seines Teilbaums,
+// damit lassen sich Zeilen in O(log n) finden und Text an beliebiger Stelle
+// einfügen oder löschen, ohne den ganzen Text zu kopieren.
+//
+// Knoten werden nie verändert, Änderungen erzeugen neue Knoten auf dem Pfad
+// zur Wurzel. Eine Kopie eines Rope ist daher ein unveränderlicher Stand, den
+// z. B. eine Suche im Hintergrund lesen kann, während weiter bearbeitet wird.
+package rope
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLeaf ist die größte Länge eines Textstücks in Bytes
+const maxLeaf = 1024
+
+type node struct {
+	left   *node
+	right  *node
+	text   string // Nur in Blättern
+	length int    // Länge des Teilbaums in Bytes
+	lines  int    // Anzahl '\n' im Teilbaum
+	height int
+}
+
+func (n *node) isLeaf() bool {
+	return n.left == nil
+}
+
+func newLeaf(s string) *node {
+	if s == "" {
+		return nil
+	}
+	return &node{text: s, length: len(s), lines: strings.Count(s, "\n"), height: 1}
+}
+
+func newBranch(left, right *node) *node {
+	return &node{
+		left:   left,
+		right:  right,
+		length: left.length + right.length,
+		lines:  left.lines + right.lines,
+		height: max(left.height, right.height) + 1,
+	}
+}
+
+func height(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// Rope ist ein Text mit Zeilenindex. Der Nullwert ist ein leerer Text.
+type Rope struct {
+	root *node
+}
+
+// New erzeugt ein Rope aus einem Text. Die Textstücke verweisen in den
+// Text, er wird nicht kopiert.
+func New(s string) Rope {
+	return Rope{root: build(s)}
+}
+
+// build zerlegt einen Text in einen ausgeglichenen Baum, Schnitte liegen
+// an Zeichengrenzen
+func build(s string) *node {
+	if len(s) <= maxLeaf {
+		return newLeaf(s)
+	}
+	mid := len(s) / 2
+	for mid < len(s) && !utf8.RuneStart(s[mid]) {
+		mid++
+	}
+	return concat(build(s[:mid]), build(s[mid:]))
+}
+
+// concat verbindet zwei Bäume und hält die Höhen ausgeglichen (AVL). Kleine
+// benachbarte Blätter werden zusammengelegt.
+func concat(l, r *node) *node {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.isLeaf() && r.isLeaf() && l.length+r.length <= maxLeaf:
+		return newLeaf(l.text + r.text)
+	case l.height > r.height+1:
+		return balance(l.left, concat(l.right, r))
+	case r.height > l.height+1:
+		return balance(concat(l, r.left), r.right)
+	}
+	return newBranch(l, r)
+}
+
+// balance erzeugt einen Knoten aus zwei Teilbäumen, deren Höhen sich um
+// höchstens zwei unterscheiden, und rotiert bei Bedarf
+func balance(l, r *node) *node {
+	switch {
+	case height(l) > height(r)+1:
+		if height(l.left) >= height(l.right) {
+			return newBranch(l.left, newBranch(l.right, r))
+		}
+		return newBranch(newBranch(l.left, l.right.left), newBranch(l.right.right, r))
+	case height(r) > height(l)+1:
+		if height(r.right) >= height(r.left) {
+			return newBranch(newBranch(l, r.left), r.right)
+		}
+		return newBranch(newBranch(l, r.left.left), newBranch(r.left.right, r.right))
+	}
+	return newBranch(l, r)
+}
+
+// split teilt einen Baum am Byte-Offset
+func split(n *node, off int) (*node, *node) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case off <= 0:
+		return nil, n
+	case off >= n.length:
+		return n, nil
+	case n.isLeaf():
+		return newLeaf(n.text[:off]), newLeaf(n.text[off:])
+	case off < n.left.length:
+		ll, lr := split(n.left, off)
+		return ll, concat(lr, n.right)
+	case off == n.left.length:
+		return n.left, n.right
+	}
+	rl, rr := split(n.right, off-n.left.length)
+	return concat(n.left, rl), rr
+}
+
+// Len liefert die Länge in Bytes
+func (r Rope) Len() int {
+	if r.root == nil {
+		return 0
+	}
+	return r.root.length
+}
+
+// LineCount liefert die Anzahl der Zeilen. Ein leerer Text hat eine Zeile,
+// ein Text mit abschließendem Zeilenumbruch eine leere letzte Zeile.
+func (r Rope) LineCount() int {
+	if r.root == nil {
+		return 1
+	}
+	return r.root.lines + 1
+}
+
+// String setzt den ganzen Text zusammen
+func (r Rope) String() string {
+	if r.root != nil && r.root.isLeaf() {
+		return r.root.text
+	}
+	var b strings.Builder
+	b.Grow(r.Len())
+	r.root.each(0, func(chunk string) bool {
+		b.WriteString(chunk)
+		return true
+	})
+	return b.String()
+}
+
+// Slice liefert den Text zwischen zwei Byte-Offsets
+func (r Rope) Slice(from, to int) string {
+	from, to = max(from, 0), min(to, r.Len())
+	if from >= to {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(to - from)
+	r.root.each(from, func(chunk string) bool {
+		chunk = chunk[:min(len(chunk), to-from-b.Len())]
+		b.WriteString(chunk)
+		return b.Len() < to-from
+	})
+	return b.String()
+}
+
+// each ruft fn für die Textstücke ab dem Byte-Offset from auf, bis fn false
+// liefert. Das erste Stück beginnt bei from.
+func (n *node) each(from int, fn func(chunk string) bool) bool {
+	switch {
+	case n == nil:
+		return true
+	case n.isLeaf():
+		if from < n.length {
+			return fn(n.text[from:])
+		}
+		return true
+	case from < n.left.length:
+		if !n.left.each(from, fn) {
+			return false
+		}
+		return n.right.each(0, fn)
+	}
+	return n.right.each(from-n.left.length, fn)
+}
+
+// LineStart liefert den Byte-Offset, an dem eine Zeile (0-basiert) beginnt.
+// Zeilen hinter dem Ende liefern die Länge.
+func (r Rope) LineStart(line int) int {
+	if line <= 0 {
+		return 0
+	}
+	if line >= r.LineCount() {
+		return r.Len()
+	}
+
+	// Offset direkt hinter dem line-ten Zeilenumbruch
+	n, off, k := r.root, 0, line
+	for !n.isLeaf() {
+		if k <= n.left.lines {
+			n = n.left
+		} else {
+			k -= n.left.lines
+			off += n.left.length
+			n = n.right
+		}
+	}
+	for i := 0; i < len(n.text); i++ {
+		if n.text[i] == '\n' {
+			if k--; k == 0 {
+				return off + i + 1
+			}
+		}
+	}
+	return off + len(n.text)
+}
+
+// LineAt liefert die Zeile (0-basiert), in der ein Byte-Offset liegt
+func (r Rope) LineAt(off int) int {
+	n, line := r.root, 0
+	off = min(max(off, 0), r.Len())
+	for n != nil && !n.isLeaf() {
+		if off < n.left.length {
+			n = n.left
+		} else {
+			off -= n.left.length
+			line += n.left.lines
+			n = n.right
+		}
+	}
+	if n != nil {
+		line += strings.Count(n.text[:min(off, len(n.text))], "\n")
+	}
+	return line
+}
+
+// Line liefert eine Zeile (0-basiert) ohne Zeilenumbruch
+func (r Rope) Line(line int) string {
+	if line < 0 || line >= r.LineCount() {
+		return ""
+	}
+	end := r.Len()
+	if line+1 < r.LineCount() {
+		end = r.LineStart(line+1) - 1
+	}
+	return r.Slice(r.LineStart(line), end)
+}
+
+// Lines liefert die Zeilen from bis to (ausschließlich)
+func (r Rope) Lines(from, to int) []string {
+	from, to = max(from, 0), min(to, r.LineCount())
+	if from >= to {
+		return nil
+	}
+	lines := make([]string, 0, to-from)
+	r.EachLine(from, func(i int, line string) bool {
+		lines = append(lines, line)
+		return i+1 < to
+	})
+	return lines
+}
+
+// EachLine ruft fn für die Zeilen ab from (0-basiert) der Reihe nach auf,
+// bis fn false liefert oder der Text endet. Zeilen innerhalb eines
+// Textstücks werden nicht kopiert.
+func (r Rope) EachLine(from int, fn func(i int, line string) bool) {
+	if from < 0 || from >= r.LineCount() {
+		return
+	}
+	i := from
+	var pending strings.Builder // Anfang einer Zeile, die über Stücke reicht
+	stopped := false
+	r.root.each(r.LineStart(from), func(chunk string) bool {
+		for {
+			nl := strings.IndexByte(chunk, '\n')
+			if nl < 0 {
+				pending.WriteString(chunk)
+				return true
+			}
+			line := chunk[:nl]
+			if pending.Len() > 0 {
+				pending.WriteString(line)
+				line = pending.String()
+				pending.Reset()
+			}
+			if !fn(i, line) {
+				stopped = true
+				return false
+			}
+			i++
+			chunk = chunk[nl+1:]
+		}
+	})
+	if !stopped {
+		fn(i, pending.String())
+	}
+}
+
+// Insert fügt Text am Byte-Offset ein
+func (r *Rope) Insert(off int, s string) {
+	if s == "" {
+		return
+	}
+	l, rest := split(r.root, off)
+	r.root = concat(concat(l, build(s)), rest)
+}
+
+// Delete löscht den Text zwischen zwei Byte-Offsets
+func (r *Rope) Delete(from, to int) {
+	if from >= to {
+		return
+	}
+	l, _ := split(r.root, from)
+	_, rest := split(r.root, to)
+	r.root = concat(l, rest)
+}
+
+// Append hängt Text an, z. B. neue Zeilen einer wachsenden Datei
+func (r *Rope) Append(s string) {
+	r.root = concat(r.root, build(s))
+}
+
+// ReplaceLines ersetzt die Zeilen from bis to (ausschließlich, 0-basiert)
+// durch lines. Ohne neue Zeilen verschwinden die alten samt Zeilenumbruch.
+// Mit from gleich LineCount werden die Zeilen hinten angefügt.
+func (r *Rope) ReplaceLines(from, to int, lines []string) {
+	start := r.LineStart(from)
+	text := strings.Join(lines, "\n")
+	if from >= r.LineCount() {
+		// Hinter der letzten Zeile gibt es nichts zu ersetzen
+		if len(lines) > 0 {
+			r.Append("\n" + text)
+		}
+		return
+	}
+	var end int
+	if to < r.LineCount() {
+		end = r.LineStart(to)
+		if len(lines) > 0 {
+			text += "\n"
+		}
+	} else {
+		end = r.Len()
+		if len(lines) == 0 && from > 0 {
+			start-- // Zeilenumbruch vor der ersten gelöschten Zeile
+		}
+	}
+	r.Delete(start, end)
+	r.Insert(start, text)
+}
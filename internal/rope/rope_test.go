@@ -0,0 +1,101 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplaceLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		from, to int
+		lines    []string
+		want     string
+	}{
+		{"ersetzen", "a\nb\nc", 1, 2, []string{"x"}, "a\nx\nc"},
+		{"einfügen vorne", "a\nb", 0, 0, []string{"x"}, "x\na\nb"},
+		{"einfügen in der Mitte", "a\nb", 1, 1, []string{"x", "y"}, "a\nx\ny\nb"},
+		{"einfügen hinten", "a\nb", 2, 2, []string{"c"}, "a\nb\nc"},
+		{"einfügen hinter Umbruch am Ende", "a\nb\n", 3, 3, []string{""}, "a\nb\n\n"},
+		{"einfügen in einzeiligen Text", "x", 1, 1, []string{"y"}, "x\ny"},
+		{"einfügen in leeren Text", "", 1, 1, []string{"y"}, "\ny"},
+		{"nichts einfügen hinten", "a\nb\n", 3, 3, nil, "a\nb\n"},
+		{"letzte Zeile ersetzen", "a\nb", 1, 2, []string{"c"}, "a\nc"},
+		{"letzte Zeile löschen", "a\nb", 1, 2, nil, "a"},
+		{"leere letzte Zeile löschen", "a\nb\n", 2, 3, nil, "a\nb"},
+		{"erste Zeile löschen", "a\nb", 0, 1, nil, "b"},
+		{"alles löschen", "a\nb", 0, 2, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.text)
+			r.ReplaceLines(tt.from, tt.to, tt.lines)
+			if got := r.String(); got != tt.want {
+				t.Errorf("ReplaceLines(%d, %d, %q) auf %q = %q, erwartet %q",
+					tt.from, tt.to, tt.lines, tt.text, got, tt.want)
+			}
+			if got, want := r.LineCount(), strings.Count(tt.want, "\n")+1; got != want {
+				t.Errorf("LineCount() = %d, erwartet %d", got, want)
+			}
+		})
+	}
+}
+
+// TestReplaceLinesRoundTrip löscht jede Zeile und fügt sie wieder ein, wie
+// es Rückgängigmachen tut. Einzeilige Texte behalten beim Löschen eine
+// leere Zeile, das Dokument ersetzt sie dort statt sie zu löschen.
+func TestReplaceLinesRoundTrip(t *testing.T) {
+	for _, text := range []string{"a\nb", "a\nb\n", "\n", "a\n\nb\n\n"} {
+		r := New(text)
+		for i := 0; i < r.LineCount(); i++ {
+			line := r.Line(i)
+			edited := r
+			edited.ReplaceLines(i, i+1, nil)
+			edited.ReplaceLines(i, i, []string{line})
+			if got := edited.String(); got != text {
+				t.Errorf("Zeile %d von %q gelöscht und eingefügt: %q", i, text, got)
+			}
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	// Mehrere Blätter, damit Zeilen über Stückgrenzen reichen
+	var b strings.Builder
+	for i := 0; i < 2000; i++ {
+		b.WriteString(strings.Repeat("x", i%7) + "\n")
+	}
+	text := b.String()
+	r := New(text)
+
+	want := strings.Split(text, "\n")
+	if got := r.LineCount(); got != len(want) {
+		t.Fatalf("LineCount() = %d, erwartet %d", got, len(want))
+	}
+	for i, line := range want {
+		if got := r.Line(i); got != line {
+			t.Fatalf("Line(%d) = %q, erwartet %q", i, got, line)
+		}
+		if got := r.LineAt(r.LineStart(i)); got != i {
+			t.Fatalf("LineAt(LineStart(%d)) = %d", i, got)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := New(strings.Repeat("zeile\n", 3000))
+	b := a
+	b.ReplaceLines(1500, 1501, []string{"neu", "neu"})
+
+	c, ok := Diff(a, b)
+	if !ok {
+		t.Fatal("Diff meldet keine Änderung")
+	}
+	if c != (Change{Line: 1500, Old: 1, New: 2}) {
+		t.Errorf("Diff = %+v", c)
+	}
+	if _, ok := Diff(a, a); ok {
+		t.Error("Diff eines Standes mit sich selbst meldet eine Änderung")
+	}
+}
@@ -0,0 +1,39 @@
+package state
+
+import (
+	"os"
+	"time"
+)
+
+// Identity beschreibt eine Datei so, dass sich erkennen lässt, ob unter
+// einem Pfad noch dieselbe Datei liegt
+type Identity struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Inode   uint64    `json:"inode,omitempty"` // 0, wo das System keine kennt
+}
+
+// Stat ermittelt die Identität der Datei unter einem Pfad
+func Stat(path string) (Identity, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{Size: info.Size(), ModTime: info.ModTime(), Inode: inode(info)}, nil
+}
+
+// Continues meldet, ob id dieselbe Datei wie old ist. Eine gewachsene Datei,
+// z. B. ein fortgeschriebenes Log, zählt als dieselbe, eine ersetzte oder
+// gekürzte nicht.
+func (id Identity) Continues(old Identity) bool {
+	if id.Size < old.Size {
+		return false
+	}
+	if id.Inode != 0 && old.Inode != 0 {
+		return id.Inode == old.Inode
+	}
+	if id.Size == old.Size {
+		return id.ModTime.Equal(old.ModTime)
+	}
+	return !id.ModTime.Before(old.ModTime)
+}
@@ -0,0 +1,169 @@
+// Package state speichert, was zwischen zwei Sitzungen erhalten bleibt,
+// z. B. Marken und die letzte Position je Datei. Der Zustand liegt als JSON
+// neben der Konfiguration.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	MaxFiles  = 500     // Gemerkte Dateien, darüber fallen die am längsten unbenutzten heraus
+	MaxSearch = 256     // Längere Suchbegriffe werden nicht gespeichert
+	maxBytes  = 4 << 20 // Größere Zustandsdateien werden nicht gelesen
+)
+
+// State ist der gespeicherte Zustand aller Dateien
+type State struct {
+	Files map[string]*File `json:"files,omitempty"` // Nach absolutem Pfad
+	Marks map[string]Mark  `json:"marks,omitempty"` // Globale Marken A-Z
+	path  string
+}
+
+// File ist der gespeicherte Zustand einer Datei
+type File struct {
+	Marks       map[string]int `json:"marks,omitempty"`  // Marken a-z mit Zeile (1-basiert)
+	Identity    Identity       `json:"identity"`         // Datei beim letzten Speichern
+	Line        int            `json:"line,omitempty"`   // Zeile des Cursors (1-basiert)
+	Offset      int            `json:"offset,omitempty"` // Erste sichtbare Zeile der Ansicht
+	Search      string         `json:"search,omitempty"` // Letzter Suchbegriff
+	Wrap        *bool          `json:"wrap,omitempty"`   // Zeilenumbruch, nil folgt der Konfiguration
+	LineNumbers *bool          `json:"lineNumbers,omitempty"`
+	Used        time.Time      `json:"used"` // Letzte Verwendung für die Verdrängung
+}
+
+// Mark ist eine globale Marke, die auf eine Zeile einer Datei zeigt
+type Mark struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+// DefaultPath liefert ~/.config/tui/state.json
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "tui", "state.json"), nil
+}
+
+// New erzeugt einen leeren Zustand. Ohne Pfad wird er nicht gespeichert.
+func New(path string) *State {
+	return &State{
+		Files: make(map[string]*File),
+		Marks: make(map[string]Mark),
+		path:  path,
+	}
+}
+
+// Load liest den Zustand aus einer Datei. Fehlt sie, ist der Zustand leer.
+// Bei einem Fehler wird ein leerer Zustand geliefert, der beim Speichern
+// die Datei ersetzt.
+func Load(path string) (*State, error) {
+	s := New(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+	if info.Size() > maxBytes {
+		return s, fmt.Errorf("%s ist größer als %d MB", path, maxBytes>>20)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return New(path), err
+	}
+	if s.Files == nil {
+		s.Files = make(map[string]*File)
+	}
+	if s.Marks == nil {
+		s.Marks = make(map[string]Mark)
+	}
+	return s, nil
+}
+
+// Save schreibt den Zustand über eine temporäre Datei, damit ein Abbruch
+// keine halb geschriebene Datei hinterlässt
+func (s *State) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.evict(MaxFiles)
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "    ")
+	// Eine zu große Datei würde Load nicht mehr lesen
+	for err == nil && len(data) > maxBytes && len(s.Files) > 0 {
+		s.evict(len(s.Files) / 2)
+		data, err = json.MarshalIndent(s, "", "    ")
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) > maxBytes {
+		return fmt.Errorf("%s wäre größer als %d MB", s.path, maxBytes>>20)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".state-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// File liefert den Zustand einer Datei und legt ihn bei Bedarf an
+func (s *State) File(path string) *File {
+	f, ok := s.Files[path]
+	if !ok {
+		f = &File{}
+		s.Files[path] = f
+	}
+	return f
+}
+
+// SetMarks speichert die Marken einer Datei. Wie eine Position zählt das
+// als Verwendung, sonst würde die Datei als erste verdrängt.
+func (s *State) SetMarks(path string, marks map[string]int) {
+	f := s.File(path)
+	f.Marks = marks
+	f.Used = time.Now()
+}
+
+// evict entfernt die am längsten unbenutzten Dateien über n
+func (s *State) evict(n int) {
+	if len(s.Files) <= n {
+		return
+	}
+	paths := make([]string, 0, len(s.Files))
+	for path := range s.Files {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return s.Files[paths[i]].Used.Before(s.Files[paths[j]].Used)
+	})
+	for _, path := range paths[:len(paths)-n] {
+		delete(s.Files, path)
+	}
+}
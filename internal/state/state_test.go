@@ -0,0 +1,61 @@
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveEvictsLeastUsed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := New(path)
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < MaxFiles+10; i++ {
+		s.File(fmt.Sprintf("/datei/%d", i)).Used = start.Add(time.Duration(i) * time.Second)
+	}
+	// Die älteste Datei bekommt Marken und gilt damit als zuletzt verwendet
+	s.SetMarks("/datei/0", map[string]int{"a": 3})
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Files) != MaxFiles {
+		t.Errorf("%d Dateien gespeichert, erwartet %d", len(loaded.Files), MaxFiles)
+	}
+	if f, ok := loaded.Files["/datei/0"]; !ok || f.Marks["a"] != 3 {
+		t.Error("Datei mit Marken verdrängt")
+	}
+	for i := 1; i <= 11; i++ {
+		_, ok := loaded.Files[fmt.Sprintf("/datei/%d", i)]
+		if want := i == 11; ok != want {
+			t.Errorf("/datei/%d gespeichert: %v, erwartet %v", i, ok, want)
+		}
+	}
+}
+
+func TestSaveSizeLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := New(path)
+	long := strings.Repeat("x", 16<<10)
+	for i := 0; i < 400; i++ {
+		s.File(fmt.Sprintf("/%s/%d", long, i)).Used = time.Now()
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Die gespeicherte Datei muss sich wieder lesen lassen
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Files) == 0 || len(loaded.Files) >= 400 {
+		t.Errorf("%d von 400 Dateien gespeichert", len(loaded.Files))
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/ui/components/messages"
+)
+
+// archiveListing erzeugt die Liste der Einträge wie bei ls -l, eine Zeile
+// je Eintrag in der Reihenfolge von Archive.Entries
+func archiveListing(archive *file.Archive) string {
+	width := 1
+	for _, e := range archive.Entries {
+		width = max(width, len(fmt.Sprint(e.Size)))
+	}
+
+	lines := make([]string, len(archive.Entries))
+	for i, e := range archive.Entries {
+		lines[i] = fmt.Sprintf("%s  %*d  %s  %s",
+			e.Mode, width, e.Size, e.ModTime.Format("2006-01-02 15:04"), e.Name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// openEntry öffnet den Archiveintrag unter dem Cursor als neuen Buffer oder
+// wechselt zu ihm. In der JSON-Darstellung klappt es den Knoten auf oder zu,
+// in der NDJSON-Darstellung öffnet oder schließt es das Detail-Pane.
+func (m *Model) openEntry() tea.Cmd {
+	b := m.buf()
+	switch b.mode {
+	case modeJSON:
+		return m.fold(foldToggle)
+	case modeNDJSON:
+		return m.toggleDetails()
+	}
+	if b.archive == nil || b.state != bufferReady || b.hex {
+		return nil
+	}
+	line := m.tv().GetCurrentLine() - 1
+	if line < 0 || line >= len(b.archive.Entries) {
+		return nil
+	}
+
+	entry := b.archive.Entries[line]
+	if !entry.Mode.IsRegular() {
+		return messages.Info("%s ist keine Datei", entry.Name)
+	}
+	for i, other := range m.buffers {
+		if other.parent == b && other.entry == entry.Name {
+			return m.switchBuffer(i)
+		}
+	}
+
+	m.buffers = append(m.buffers, newEntryBuffer(b, entry.Name))
+	return m.switchBuffer(len(m.buffers) - 1)
+}
@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/editor"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/outline"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/fase22/tui/internal/ui/components/diffview"
+	"github.com/fase22/tui/internal/ui/components/jsonview"
+	"github.com/fase22/tui/internal/ui/components/logview"
+	"github.com/fase22/tui/internal/ui/components/markdown"
+	"github.com/fase22/tui/internal/ui/components/tableview"
+)
+
+type bufferState int
+
+const (
+	bufferUnloaded bufferState = iota
+	bufferLoading
+	bufferReady
+	bufferFailed
+)
+
+// Buffer ist eine geöffnete Datei mit Suchzustand und Filter. Die
+// Scrollposition gehört zur Ansicht im jeweiligen Pane. Buffer ohne Pfad
+// sind erzeugt, z. B. die Seiten eines Diffs oder Einträge eines Archivs.
+// Der Pfad "-" steht für die Standardeingabe.
+type Buffer struct {
+	path        string
+	name        string    // Anzeigename, z. B. für Diffs und die Standardeingabe
+	source      string    // Geladener Text
+	content     rope.Rope // Angezeigter Text, je nach Darstellung aus source erzeugt
+	mode        viewMode  // Darstellung, z. B. Text oder JSON-Baum
+	state       bufferState
+	err         error
+	searchQuery string
+	searchIndex int           // Aktueller Treffer-Index
+	searchHits  []searchMatch // Treffer in der Reihenfolge des Textes
+	searching   *searchJob    // Laufende Suche, sonst nil
+	filter      string
+	diff        *diffview.View      // Diff-Darstellung, nil bei normalen Dateien
+	patch       *diffview.PatchView // Erkannter Unified Diff, sonst nil
+	pager       *file.Pager         // Seitenweiser Zugriff für die Hexansicht
+	binary      bool                // Inhalt ist binär, Text nur bei Bedarf geladen
+	hex         bool                // Hexansicht statt Text anzeigen
+	hexPattern  []byte              // Gesuchte Bytefolge in der Hexansicht
+	info        file.Info           // Kompression und Größen der Datei
+	archive     *file.Archive       // Archiv, dessen Einträge der Inhalt auflistet
+	parent      *Buffer             // Archiv-Buffer, aus dem der Eintrag stammt
+	entry       string              // Name des Eintrags im Archiv
+	encoding    file.Encoding       // Vorgegebene Kodierung, EncodingAuto erkennt sie
+	json        *jsonview.View      // JSON-Baum, erst bei Bedarf erzeugt
+	log         *logview.View       // Spaltenansicht für JSON Lines
+	table       *tableview.View     // Tabelle für CSV und TSV
+	markdown    *markdown.View      // Gerendertes Markdown
+	symbols     []outline.Symbol    // Gliederung der Quelle, nil bis zum ersten Abruf
+	marks       map[rune]int        // Marken a-z mit Quellzeile (1-basiert)
+	markLines   map[int]rune        // Marken je angezeigter Zeile (0-basiert) für die Randspalte
+	jump        int                 // Quellzeile, zu der nach dem Laden gesprungen wird
+	restore     *position           // Gespeicherte Position, die nach dem Laden gilt
+	wrap        *bool               // Zeilenumbruch des Buffers, nil folgt der Konfiguration
+	numbers     *bool               // Zeilennummern des Buffers, nil folgt der Konfiguration
+	doc         *editor.Document    // Bearbeiteter Text, nil solange nicht bearbeitet wurde
+	stale       bool                // source ist älter als doc, siehe sourceText
+}
+
+type errMsg struct {
+	buf *Buffer
+	err error
+}
+
+type fileLoadedMsg struct {
+	buf     *Buffer
+	content string
+	pager   *file.Pager // Nur bei binären Inhalten
+	binary  bool
+	hex     bool // Hexansicht aktivieren
+	info    file.Info
+	archive *file.Archive
+}
+
+func newBuffer(path string) *Buffer {
+	b := &Buffer{path: path}
+	if path == "-" {
+		b.name = "stdin"
+	}
+	return b
+}
+
+// newDiffBuffer erzeugt einen fertig geladenen Buffer für eine Diff-Ansicht
+func newDiffBuffer(name string, view *diffview.View) *Buffer {
+	return &Buffer{
+		name:    name,
+		source:  view.Content(),
+		content: rope.New(view.Content()),
+		state:   bufferReady,
+		diff:    view,
+	}
+}
+
+// newEntryBuffer erzeugt einen Buffer für einen Eintrag eines Archivs
+func newEntryBuffer(parent *Buffer, entry string) *Buffer {
+	return &Buffer{
+		name:   parent.Name() + "/" + path.Clean(entry),
+		parent: parent,
+		entry:  entry,
+	}
+}
+
+// Title liefert den Namen für Tableiste und Pane-Titel
+func (b *Buffer) Title() string {
+	if b.entry != "" {
+		return path.Base(b.entry)
+	}
+	if b.name != "" {
+		return b.name
+	}
+	return filepath.Base(b.path)
+}
+
+// Name liefert den Pfad oder bei erzeugten Buffern den Anzeigenamen
+func (b *Buffer) Name() string {
+	if b.name != "" {
+		return b.name
+	}
+	return b.path
+}
+
+// sourceText liefert den Quelltext. Nach dem Bearbeiten wird er erst bei
+// Bedarf aus dem Dokument übernommen, nicht bei jedem Tastendruck.
+func (b *Buffer) sourceText() string {
+	if b.stale {
+		b.source, b.stale = b.doc.Text(), false
+	}
+	return b.source
+}
+
+// ensureLoaded startet das Laden, falls der Buffer noch nicht geladen ist
+func (b *Buffer) ensureLoaded() tea.Cmd {
+	if b.state != bufferUnloaded {
+		return nil
+	}
+	b.state = bufferLoading
+	return b.load
+}
+
+// reload lädt die Datei unabhängig vom bisherigen Zustand neu
+func (b *Buffer) reload() tea.Cmd {
+	b.state = bufferLoading
+	return b.load
+}
+
+// load liest den Buffer ein. Unkomprimierte Binärdateien werden nicht
+// vollständig gelesen, sondern seitenweise für die Hexansicht geöffnet.
+// Komprimierte Dateien werden entpackt, Archive als Liste ihrer Einträge
+// angezeigt.
+func (b *Buffer) load() tea.Msg {
+	if b.path != "-" && b.parent == nil {
+		format, err := file.Sniff(b.path)
+		if err != nil {
+			return errMsg{buf: b, err: err}
+		}
+		if format.Binary && format.Compression == file.CompressionNone &&
+			format.Archive == file.ArchiveNone && b.encoding == file.EncodingAuto {
+			pager, err := file.OpenPaged(b.path)
+			if err != nil {
+				return errMsg{buf: b, err: err}
+			}
+			info := file.Info{Size: pager.Size(), RawSize: pager.Size()}
+			return fileLoadedMsg{buf: b, pager: pager, binary: true, hex: true, info: info}
+		}
+	}
+
+	content, info, err := b.read()
+	if err != nil {
+		return errMsg{buf: b, err: err}
+	}
+	if file.DetectArchive([]byte(content[:min(len(content), 512)])) != file.ArchiveNone {
+		archive, err := file.OpenArchive(content)
+		if err != nil {
+			return errMsg{buf: b, err: err}
+		}
+		return fileLoadedMsg{buf: b, content: archiveListing(archive), archive: archive, info: info}
+	}
+	if file.IsBinary([]byte(content[:min(len(content), 8<<10)])) {
+		// Standardeingabe und entpackte Inhalte liegen ohnehin im Speicher
+		return fileLoadedMsg{
+			buf:     b,
+			content: file.Printable(content),
+			pager:   newContentPager(content),
+			binary:  true,
+			hex:     true,
+			info:    info,
+		}
+	}
+	return fileLoadedMsg{buf: b, content: content, info: info}
+}
+
+// read liest den vollständigen, entpackten Inhalt aus Datei, Standardeingabe
+// oder Archiv
+func (b *Buffer) read() (string, file.Info, error) {
+	if b.parent != nil {
+		return b.parent.archive.ReadEntry(b.entry, b.encoding)
+	}
+	return file.ReadFileInfo(b.path, b.encoding)
+}
+
+// loadText liest eine Binärdatei für die Textansicht vollständig ein
+func (b *Buffer) loadText() tea.Cmd {
+	pager := b.pager
+	return func() tea.Msg {
+		content, info, err := b.read()
+		if err != nil {
+			return errMsg{buf: b, err: err}
+		}
+		return fileLoadedMsg{buf: b, content: file.Printable(content), pager: pager, binary: true, info: info}
+	}
+}
+
+// hexPager liefert den Pager für die Hexansicht. Bei Textdateien zeigt er
+// die Bytes der Datei, nur entpackt, nicht in Kodierung und Zeilenenden
+// umgewandelt und ohne ungespeicherte Änderungen.
+func (b *Buffer) hexPager() *file.Pager {
+	if b.pager == nil {
+		b.pager = b.rawPager()
+	}
+	return b.pager
+}
+
+// rawPager öffnet unkomprimierte Dateien seitenweise, andere Inhalte werden
+// entpackt in den Speicher gelesen. Ist die Datei nicht mehr lesbar, bleibt
+// nur der geladene Text.
+func (b *Buffer) rawPager() *file.Pager {
+	if b.path != "-" && b.parent == nil && b.info.Compression == file.CompressionNone {
+		if pager, err := file.OpenPaged(b.path); err == nil {
+			return pager
+		}
+	}
+
+	var data []byte
+	var err error
+	if b.parent != nil {
+		data, err = b.parent.archive.ReadEntryRaw(b.entry)
+	} else {
+		data, err = file.ReadRaw(b.path)
+	}
+	if err != nil {
+		return newContentPager(b.sourceText())
+	}
+	return newContentPager(string(data))
+}
+
+func newContentPager(content string) *file.Pager {
+	return file.NewPager(strings.NewReader(content), int64(len(content)), 0, 0)
+}
+
+// close gibt die Datei der Hexansicht frei
+func (b *Buffer) close() {
+	if b.pager != nil {
+		b.pager.Close()
+		b.pager = nil
+	}
+}
+
+func (b *Buffer) resetSearch() {
+	b.stopSearch()
+	b.searchQuery = ""
+	b.searchHits = nil
+	b.searchIndex = 0
+	b.hexPattern = nil
+}
@@ -0,0 +1,531 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// command ist ein Befehl der Kommandozeile (":")
+type command struct {
+	name     string
+	aliases  []string
+	usage    string
+	complete func(m *Model, arg string) []string
+	run      func(m *Model, args string, force bool) tea.Cmd
+}
+
+var commands = []command{
+	{
+		name:    "quit",
+		aliases: []string{"q"},
+		usage:   "Programm beenden, :q! auch mit ungespeicherten Änderungen",
+		run: func(m *Model, _ string, force bool) tea.Cmd {
+			return m.quit(force)
+		},
+	},
+	{
+		name:    "wq",
+		aliases: []string{"x"},
+		usage:   "Speichern und beenden",
+		run:     (*Model).cmdWriteQuit,
+	},
+	{
+		name:     "set",
+		aliases:  []string{"se"},
+		usage:    "Option setzen, z. B. :set nowrap oder :set tabwidth=8",
+		complete: (*Model).completeOption,
+		run:      (*Model).cmdSet,
+	},
+	{
+		name:     "theme",
+		usage:    "Theme wechseln",
+		complete: (*Model).completeTheme,
+		run:      (*Model).cmdTheme,
+	},
+	{
+		name:     "edit",
+		aliases:  []string{"e"},
+		usage:    "Datei öffnen",
+		complete: (*Model).completePath,
+		run:      (*Model).cmdEdit,
+	},
+	{
+		name:    "bnext",
+		aliases: []string{"bn"},
+		usage:   "Nächster Buffer",
+		run: func(m *Model, _ string, _ bool) tea.Cmd {
+			return m.switchBuffer(m.currentIndex() + 1)
+		},
+	},
+	{
+		name:    "bprevious",
+		aliases: []string{"bp"},
+		usage:   "Vorheriger Buffer",
+		run: func(m *Model, _ string, _ bool) tea.Cmd {
+			return m.switchBuffer(m.currentIndex() - 1)
+		},
+	},
+	{
+		name:    "buffer",
+		aliases: []string{"b"},
+		usage:   "Zu Buffer Nummer oder Name wechseln",
+		run:     (*Model).cmdBuffer,
+	},
+	{
+		name:    "buffers",
+		aliases: []string{"ls"},
+		usage:   "Geöffnete Buffer auflisten",
+		run:     (*Model).cmdBuffers,
+	},
+	{
+		name:    "bdelete",
+		aliases: []string{"bd"},
+		usage:   "Buffer schließen, :bd! verwirft ungespeicherte Änderungen",
+		run: func(m *Model, _ string, force bool) tea.Cmd {
+			return m.closeBuffer(force)
+		},
+	},
+	{
+		name:     "split",
+		aliases:  []string{"sp"},
+		usage:    "Pane horizontal teilen, optional mit Datei",
+		complete: (*Model).completePath,
+		run: func(m *Model, args string, _ bool) tea.Cmd {
+			return m.cmdSplit(splitHorizontal, args)
+		},
+	},
+	{
+		name:     "vsplit",
+		aliases:  []string{"vs"},
+		usage:    "Pane vertikal teilen, optional mit Datei",
+		complete: (*Model).completePath,
+		run: func(m *Model, args string, _ bool) tea.Cmd {
+			return m.cmdSplit(splitVertical, args)
+		},
+	},
+	{
+		name:    "close",
+		aliases: []string{"clo"},
+		usage:   "Pane schließen",
+		run: func(m *Model, _ string, _ bool) tea.Cmd {
+			return m.closePane()
+		},
+	},
+	{
+		name:    "only",
+		aliases: []string{"on"},
+		usage:   "Alle anderen Panes schließen",
+		run: func(m *Model, _ string, _ bool) tea.Cmd {
+			m.onlyPane()
+			return nil
+		},
+	},
+	{
+		name:    "resize",
+		aliases: []string{"res"},
+		usage:   "Pane in Prozentpunkten vergrößern (+N) oder verkleinern (-N)",
+		run:     (*Model).cmdResize,
+	},
+	{
+		name:    "scrollbind",
+		aliases: []string{"scb"},
+		usage:   "Scrollbindung des Panes umschalten",
+		run: func(m *Model, _ string, _ bool) tea.Cmd {
+			return m.toggleScrollBind()
+		},
+	},
+	{
+		name:     "diff",
+		usage:    "Zwei Dateien vergleichen",
+		complete: (*Model).completePath,
+		run:      (*Model).cmdDiff,
+	},
+	{
+		name:     "diffmode",
+		usage:    "Diff untereinander (unified) oder nebeneinander (split) anzeigen",
+		complete: (*Model).completeDiffMode,
+		run:      (*Model).cmdDiffMode,
+	},
+	{
+		name:  "hex",
+		usage: "Text- und Hexansicht umschalten",
+		run:   (*Model).cmdHex,
+	},
+	{
+		name:     "mode",
+		usage:    "Darstellung wählen (text, json, ndjson, table, markdown), ohne Argument anzeigen",
+		complete: (*Model).completeMode,
+		run:      (*Model).cmdMode,
+	},
+	{
+		name:     "columns",
+		aliases:  []string{"cols"},
+		usage:    "Angezeigte Felder bzw. Spalten wählen und ordnen, ohne Argument die Vorgabe",
+		complete: (*Model).completeField,
+		run:      (*Model).cmdColumns,
+	},
+	{
+		name:     "sort",
+		usage:    "Tabelle nach einer Spalte sortieren (:sort! absteigend), ohne Argument aufheben",
+		complete: (*Model).completeField,
+		run:      (*Model).cmdSort,
+	},
+	{
+		name:     "where",
+		usage:    `NDJSON-Einträge nach Feldern filtern (level=error msg~"connection refused"), ohne Argument aufheben`,
+		complete: (*Model).completeField,
+		run:      (*Model).cmdWhere,
+	},
+	{
+		name:  "files",
+		usage: "Dateiliste eines Patches ein- oder ausblenden",
+		run:   (*Model).cmdFiles,
+	},
+	{
+		name:  "outline",
+		usage: "Gliederung (Überschriften, Deklarationen, Schlüssel) ein- oder ausblenden",
+		run:   (*Model).cmdOutline,
+	},
+	{
+		name:  "marks",
+		usage: "Marken des Buffers und globale Marken auflisten",
+		run:   (*Model).cmdMarks,
+	},
+	{
+		name:    "delmarks",
+		aliases: []string{"delm"},
+		usage:   "Marken löschen (:delmarks a B), mit ! alle Marken a-z des Buffers",
+		run:     (*Model).cmdDelmarks,
+	},
+	{
+		name:     "write",
+		aliases:  []string{"w"},
+		usage:    "Angezeigten Inhalt in eine Datei schreiben",
+		complete: (*Model).completePath,
+		run:      (*Model).cmdWrite,
+	},
+	{
+		name:  "filter",
+		usage: "Nur Zeilen mit dem Begriff anzeigen, ohne Argument aufheben",
+		run:   (*Model).cmdFilter,
+	},
+	{
+		name:    "goto",
+		aliases: []string{"go"},
+		usage:   "Zu einer Zeile springen, in der Hexansicht zu einem Offset",
+		run:     (*Model).cmdGoto,
+	},
+	{
+		name:    "messages",
+		aliases: []string{"mes"},
+		usage:   "Meldungsprotokoll anzeigen",
+		run: func(m *Model, _ string, _ bool) tea.Cmd {
+			m.openLog()
+			return nil
+		},
+	},
+}
+
+func findCommand(name string) (*command, bool) {
+	for i := range commands {
+		cmd := &commands[i]
+		if cmd.name == name {
+			return cmd, true
+		}
+		for _, alias := range cmd.aliases {
+			if alias == name {
+				return cmd, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// runCommand führt eine Eingabe der Kommandozeile aus
+func (m *Model) runCommand(line string) tea.Cmd {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+
+	// ":42" springt wie in vim direkt zur Zeile, in der Hexansicht auch ":0x1f0"
+	if _, err := strconv.Atoi(line); err == nil {
+		return m.cmdGoto(line, false)
+	}
+	if _, ok := parseOffset(line); ok && m.buf().hex {
+		return m.cmdGoto(line, false)
+	}
+
+	name, args, _ := strings.Cut(line, " ")
+	force := strings.HasSuffix(name, "!")
+	name = strings.TrimSuffix(name, "!")
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		return messages.Error(fmt.Errorf("Unbekannter Befehl: %s", name))
+	}
+	return cmd.run(m, strings.TrimSpace(args), force)
+}
+
+func (m *Model) cmdSet(args string, _ bool) tea.Cmd {
+	if args == "" {
+		return messages.Info("%s", describeOptions(m.config))
+	}
+
+	encoding := m.config.Editor.Encoding
+	wrap, numbers := m.config.Editor.WordWrap, m.config.Editor.ShowLineNumbers
+	var results []string
+	for _, arg := range strings.Fields(args) {
+		result, err := applySetting(m.config, arg)
+		if err != nil {
+			m.applyConfig()
+			return messages.Error(err)
+		}
+		if result != "" {
+			results = append(results, result)
+		}
+	}
+
+	m.resetBufferOptions(wrap, numbers)
+	m.applyConfig()
+	var cmd tea.Cmd
+	if m.config.Editor.Encoding != encoding {
+		cmd = m.reloadEncoding()
+	}
+	if len(results) > 0 {
+		return tea.Batch(cmd, messages.Info("%s", strings.Join(results, " ")))
+	}
+	return cmd
+}
+
+// reloadEncoding lädt den aktuellen Buffer in der eingestellten Kodierung neu
+func (m *Model) reloadEncoding() tea.Cmd {
+	b := m.buf()
+	if b.path == "" && b.parent == nil {
+		return messages.Warn("%s hat keine Datei zum Neuladen", b.Title())
+	}
+	b.encoding, _ = file.ParseEncoding(m.config.Editor.Encoding)
+	return b.reload()
+}
+
+func (m *Model) cmdTheme(args string, _ bool) tea.Cmd {
+	if args == "" {
+		return messages.Info("theme=%s", m.config.Theme.Name)
+	}
+	return m.cmdSet("theme="+args, false)
+}
+
+func (m *Model) cmdEdit(args string, force bool) tea.Cmd {
+	if args == "" {
+		// Aktuelle Datei neu laden
+		if m.buf().path == "" && m.buf().parent == nil {
+			return messages.Warn("%s hat keine Datei zum Neuladen", m.buf().Title())
+		}
+		if m.buf().modified() && !force {
+			return messages.Warn("Ungespeicherte Änderungen in %s (:e! verwirft sie)", m.buf().Title())
+		}
+		return m.buf().reload()
+	}
+	if _, err := os.Stat(args); err != nil {
+		return messages.Error(err)
+	}
+	return m.openBuffer(args)
+}
+
+func (m *Model) cmdWrite(args string, force bool) tea.Cmd {
+	if args == "" {
+		// Ohne Dateinamen wird der bearbeitete Buffer gespeichert
+		if m.buf().doc == nil {
+			return messages.Error(fmt.Errorf("Kein Dateiname angegeben und %s wurde nicht bearbeitet", m.buf().Title()))
+		}
+		return m.saveBuffer(m.buf())
+	}
+	if _, err := os.Stat(args); err == nil && !force {
+		return messages.Error(fmt.Errorf("Datei %s existiert bereits (:w! zum Überschreiben)", args))
+	}
+
+	content := m.tv().VisibleContent()
+	if err := os.WriteFile(args, []byte(content), 0644); err != nil {
+		return messages.Error(err)
+	}
+	return messages.Info("%q geschrieben, %d Bytes", args, len(content))
+}
+
+func (m *Model) cmdFilter(args string, _ bool) tea.Cmd {
+	b := m.buf()
+	b.filter = args
+	m.eachView(b, func(tv *textview.TextView) {
+		tv.SetFilter(args)
+	})
+	if args == "" {
+		return messages.Info("Filter aufgehoben")
+	}
+	return messages.Info("Filter %q: %d Zeilen", args, m.tv().GetDisplayLines())
+}
+
+func (m *Model) cmdGoto(args string, _ bool) tea.Cmd {
+	if m.buf().hex {
+		return m.gotoOffset(args)
+	}
+
+	line, err := strconv.Atoi(args)
+	if err != nil || line < 1 {
+		return messages.Error(fmt.Errorf("Ungültige Zeilennummer: %s", args))
+	}
+	if total := m.tv().GetTotalLines(); line > total {
+		line = total
+	}
+	m.jumpToLine(line)
+	return nil
+}
+
+func (m *Model) cmdBuffer(args string, _ bool) tea.Cmd {
+	if args == "" {
+		return messages.Info("%d: %s", m.currentIndex()+1, m.buf().Name())
+	}
+
+	index, err := strconv.Atoi(args)
+	if err != nil {
+		// Buffer über einen Teil seines Namens suchen
+		for i, b := range m.buffers {
+			if strings.Contains(b.Name(), args) {
+				return m.switchBuffer(i)
+			}
+		}
+		return messages.Error(fmt.Errorf("Kein passender Buffer: %s", args))
+	}
+	if index < 1 || index > len(m.buffers) {
+		return messages.Error(fmt.Errorf("Buffer %d existiert nicht", index))
+	}
+	return m.switchBuffer(index - 1)
+}
+
+func (m *Model) cmdBuffers(_ string, _ bool) tea.Cmd {
+	var cmds []tea.Cmd
+	for i, b := range m.buffers {
+		marker := " "
+		if i == m.currentIndex() {
+			marker = "%"
+		}
+		cmds = append(cmds, messages.Info("%s%d: %s", marker, i+1, b.Name()))
+	}
+	m.openLog()
+	return tea.Sequence(cmds...)
+}
+
+func (m *Model) cmdSplit(dir splitDir, args string) tea.Cmd {
+	if args != "" {
+		if _, err := os.Stat(args); err != nil {
+			return messages.Error(err)
+		}
+	}
+
+	m.splitPane(dir)
+	if args != "" {
+		return m.openBuffer(args)
+	}
+	return nil
+}
+
+func (m *Model) cmdResize(args string, _ bool) tea.Cmd {
+	percent, err := strconv.Atoi(args)
+	if err != nil {
+		return messages.Error(fmt.Errorf("Ungültige Größenänderung: %s", args))
+	}
+	m.resizePane(float64(percent) / 100)
+	return nil
+}
+
+// completeCommandLine vervollständigt Befehlsnamen und deren Argumente
+func (m *Model) completeCommandLine(input string) []string {
+	name, arg, hasArg := strings.Cut(input, " ")
+	if !hasArg {
+		var names []string
+		for _, cmd := range commands {
+			for _, candidate := range append([]string{cmd.name}, cmd.aliases...) {
+				if strings.HasPrefix(candidate, name) {
+					names = append(names, candidate)
+				}
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	cmd, ok := findCommand(strings.TrimSuffix(name, "!"))
+	if !ok || cmd.complete == nil {
+		return nil
+	}
+
+	// Nur das letzte Argument vervollständigen
+	prefix := name + " "
+	if i := strings.LastIndex(arg, " "); i >= 0 {
+		prefix += arg[:i+1]
+		arg = arg[i+1:]
+	}
+
+	var lines []string
+	for _, candidate := range cmd.complete(m, arg) {
+		lines = append(lines, prefix+candidate)
+	}
+	return lines
+}
+
+func (m *Model) completeOption(arg string) []string {
+	return filterPrefix(optionCandidates(), arg)
+}
+
+func (m *Model) completeTheme(arg string) []string {
+	return filterPrefix(themeNames(), arg)
+}
+
+// completePath vervollständigt Datei- und Verzeichnisnamen
+func (m *Model) completePath(arg string) []string {
+	dir, base := filepath.Split(arg)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		// Versteckte Dateien nur auf ausdrücklichen Wunsch
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		if entry.IsDir() {
+			name += "/"
+		}
+		paths = append(paths, dir+name)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func filterPrefix(candidates []string, prefix string) []string {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
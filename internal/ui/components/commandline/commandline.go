@@ -0,0 +1,222 @@
+package commandline
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxHistory begrenzt die Anzahl gemerkter Befehle
+const maxHistory = 100
+
+// Completer liefert zu einer Eingabe die vollständigen Ersetzungen
+type Completer func(input string) []string
+
+// Result beschreibt, wie eine Taste die Eingabe beeinflusst hat
+type Result int
+
+const (
+	ResultNone Result = iota
+	ResultSubmit
+	ResultCancel
+)
+
+type CommandLine struct {
+	input     textinput.Model
+	prompt    string
+	history   []string
+	histIdx   int    // Position in history, len(history) = aktuelle Eingabe
+	draft     string // Eingabe vor dem Blättern in der Historie
+	completer Completer
+	matches   []string
+	matchIdx  int
+	width     int
+	style     Style
+}
+
+func New(prompt string, width int, style Style, completer Completer) CommandLine {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.TextStyle = style.Text
+	input.Cursor.TextStyle = style.Text
+
+	return CommandLine{
+		input:     input,
+		prompt:    prompt,
+		completer: completer,
+		width:     width,
+		style:     style,
+	}
+}
+
+// Open leert die Eingabe und aktiviert die Kommandozeile
+func (c *CommandLine) Open() {
+	c.input.Reset()
+	c.input.Focus()
+	c.histIdx = len(c.history)
+	c.draft = ""
+	c.matches = nil
+}
+
+func (c *CommandLine) Close() {
+	c.input.Blur()
+	c.matches = nil
+}
+
+func (c *CommandLine) Value() string {
+	return c.input.Value()
+}
+
+func (c *CommandLine) SetWidth(width int) {
+	c.width = width
+}
+
+func (c *CommandLine) SetStyle(style Style) {
+	c.style = style
+	c.input.TextStyle = style.Text
+	c.input.Cursor.TextStyle = style.Text
+}
+
+// History liefert die bisher ausgeführten Befehle, ältester zuerst
+func (c *CommandLine) History() []string {
+	return c.history
+}
+
+func (c *CommandLine) addHistory(entry string) {
+	if entry == "" {
+		return
+	}
+	// Doppelte Einträge ans Ende verschieben
+	for i, h := range c.history {
+		if h == entry {
+			c.history = append(c.history[:i], c.history[i+1:]...)
+			break
+		}
+	}
+	c.history = append(c.history, entry)
+	if len(c.history) > maxHistory {
+		c.history = c.history[len(c.history)-maxHistory:]
+	}
+}
+
+// Update verarbeitet eine Taste und meldet, ob die Eingabe abgeschlossen wurde
+func (c *CommandLine) Update(msg tea.KeyMsg) (Result, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		c.addHistory(strings.TrimSpace(c.input.Value()))
+		return ResultSubmit, nil
+
+	case tea.KeyEsc, tea.KeyCtrlC:
+		return ResultCancel, nil
+
+	case tea.KeyBackspace:
+		// Backspace auf leerer Zeile verlässt den Modus wie in vim
+		if c.input.Value() == "" {
+			return ResultCancel, nil
+		}
+
+	case tea.KeyTab:
+		c.complete(1)
+		return ResultNone, nil
+
+	case tea.KeyShiftTab:
+		c.complete(-1)
+		return ResultNone, nil
+
+	case tea.KeyUp:
+		c.browseHistory(-1)
+		return ResultNone, nil
+
+	case tea.KeyDown:
+		c.browseHistory(1)
+		return ResultNone, nil
+	}
+
+	c.matches = nil
+	var cmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	return ResultNone, cmd
+}
+
+// complete ersetzt die Eingabe durch den nächsten Vervollständigungsvorschlag
+func (c *CommandLine) complete(step int) {
+	if c.completer == nil {
+		return
+	}
+
+	if c.matches == nil {
+		c.matches = c.completer(c.input.Value())
+		if len(c.matches) == 0 {
+			c.matches = nil
+			return
+		}
+		c.matchIdx = 0
+		if step < 0 {
+			c.matchIdx = len(c.matches) - 1
+		}
+	} else {
+		c.matchIdx = (c.matchIdx + step + len(c.matches)) % len(c.matches)
+	}
+
+	c.input.SetValue(c.matches[c.matchIdx])
+	c.input.CursorEnd()
+}
+
+func (c *CommandLine) browseHistory(step int) {
+	if len(c.history) == 0 {
+		return
+	}
+	if c.histIdx == len(c.history) {
+		c.draft = c.input.Value()
+	}
+
+	c.histIdx += step
+	if c.histIdx < 0 {
+		c.histIdx = 0
+	}
+	if c.histIdx >= len(c.history) {
+		c.histIdx = len(c.history)
+		c.input.SetValue(c.draft)
+	} else {
+		c.input.SetValue(c.history[c.histIdx])
+	}
+	c.input.CursorEnd()
+	c.matches = nil
+}
+
+func (c CommandLine) Render() string {
+	line := c.style.Prompt.Render(c.prompt) + c.input.View()
+
+	// Vervollständigungen hinter der Eingabe auflisten
+	if len(c.matches) > 1 {
+		var candidates []string
+		for i, match := range c.matches {
+			label := lastWord(match)
+			if i == c.matchIdx {
+				candidates = append(candidates, c.style.Selected.Render(label))
+			} else {
+				candidates = append(candidates, c.style.Candidate.Render(label))
+			}
+		}
+		line += c.style.Candidate.Render("  ") + strings.Join(candidates, c.style.Candidate.Render(" "))
+	}
+
+	if c.width > 0 {
+		if lipgloss.Width(line) < c.width {
+			line += c.style.Base.Render(strings.Repeat(" ", c.width-lipgloss.Width(line)))
+		}
+		line = c.style.Base.MaxWidth(c.width).Render(line)
+	}
+	return line
+}
+
+func lastWord(s string) string {
+	if i := strings.LastIndex(s, " "); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
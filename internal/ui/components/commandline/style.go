@@ -0,0 +1,41 @@
+package commandline
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Base      lipgloss.Style
+	Prompt    lipgloss.Style
+	Text      lipgloss.Style
+	Candidate lipgloss.Style
+	Selected  lipgloss.Style
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Base: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.Selection)),
+
+		Prompt: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Background(lipgloss.Color(theme.Selection)).
+			Bold(true),
+
+		Text: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Foreground)).
+			Background(lipgloss.Color(theme.Selection)),
+
+		Candidate: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Background(lipgloss.Color(theme.Selection)),
+
+		Selected: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Accent)).
+			Bold(true),
+	}
+}
@@ -0,0 +1,243 @@
+package diffview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/diff"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+type rowKind int
+
+const (
+	rowEqual rowKind = iota
+	rowAdded
+	rowRemoved
+	rowChanged
+	rowFiller
+)
+
+// row ist eine angezeigte Zeile des Vergleichs
+type row struct {
+	kind    rowKind
+	oldLine int // 1-basiert, 0 wenn die Zeile links fehlt
+	newLine int
+	sign    byte
+	text    string
+	changes []diff.Range // Geänderte Zeichen bei geänderten Zeilen
+	added   bool         // Zeichenänderungen als Einfügung darstellen
+}
+
+// View ist eine Seite eines Vergleichs oder die vereinheitlichte Ansicht.
+// Sie liefert den Text für die Textansicht und dekoriert ihn als
+// textview.Decorator mit Zeilennummern, +/- Spalte und Farben.
+type View struct {
+	rows     []row
+	hunks    []int // Zeilen (0-basiert), an denen eine Änderung beginnt
+	style    Style
+	unified  bool
+	numWidth int
+}
+
+// Unified erzeugt eine Ansicht mit beiden Dateien untereinander
+func Unified(edits []diff.Edit, style Style) *View {
+	v := &View{style: style, unified: true}
+
+	changes := intraLine(edits)
+	for i, e := range edits {
+		r := row{oldLine: e.OldLine + 1, newLine: e.NewLine + 1, text: e.Text, sign: ' '}
+		switch e.Kind {
+		case diff.Delete:
+			r.kind, r.sign = rowRemoved, '-'
+		case diff.Insert:
+			r.kind, r.sign, r.added = rowAdded, '+', true
+		}
+		if ranges, ok := changes[i]; ok {
+			r.kind, r.changes = rowChanged, ranges
+		}
+		v.rows = append(v.rows, r)
+	}
+
+	v.finish()
+	return v
+}
+
+// SideBySide erzeugt zwei Ansichten, deren Zeilen einander entsprechen.
+// Fehlende Zeilen werden auf der anderen Seite mit Leerzeilen aufgefüllt.
+func SideBySide(edits []diff.Edit, style Style) (left, right *View) {
+	left, right = &View{style: style}, &View{style: style}
+	changes := intraLine(edits)
+
+	for i := 0; i < len(edits); {
+		if edits[i].Kind == diff.Equal {
+			e := edits[i]
+			left.rows = append(left.rows, row{kind: rowEqual, oldLine: e.OldLine + 1, sign: ' ', text: e.Text})
+			right.rows = append(right.rows, row{kind: rowEqual, newLine: e.NewLine + 1, sign: ' ', text: e.Text})
+			i++
+			continue
+		}
+
+		// Block aus Löschungen und Einfügungen nebeneinanderstellen
+		var deleted, inserted []int
+		for i < len(edits) && edits[i].Kind == diff.Delete {
+			deleted = append(deleted, i)
+			i++
+		}
+		for i < len(edits) && edits[i].Kind == diff.Insert {
+			inserted = append(inserted, i)
+			i++
+		}
+
+		for j := 0; j < max(len(deleted), len(inserted)); j++ {
+			l, r := row{kind: rowFiller}, row{kind: rowFiller}
+			if j < len(deleted) {
+				e := edits[deleted[j]]
+				l = row{kind: rowRemoved, oldLine: e.OldLine + 1, sign: '-', text: e.Text}
+			}
+			if j < len(inserted) {
+				e := edits[inserted[j]]
+				r = row{kind: rowAdded, newLine: e.NewLine + 1, sign: '+', text: e.Text, added: true}
+			}
+			if j < len(deleted) && j < len(inserted) {
+				l.kind, l.sign, l.changes = rowChanged, '~', changes[deleted[j]]
+				r.kind, r.sign, r.changes = rowChanged, '~', changes[inserted[j]]
+			}
+			left.rows = append(left.rows, l)
+			right.rows = append(right.rows, r)
+		}
+	}
+
+	left.finish()
+	right.finish()
+	return left, right
+}
+
+// intraLine berechnet die Zeichenänderungen aller geänderten Zeilenpaare,
+// geordnet nach dem Index in der Edit-Liste
+func intraLine(edits []diff.Edit) map[int][]diff.Range {
+	changes := make(map[int][]diff.Range)
+	for _, pair := range diff.Pairs(edits) {
+		oldRanges, newRanges := diff.Chars(edits[pair.Delete].Text, edits[pair.Insert].Text)
+		changes[pair.Delete] = oldRanges
+		changes[pair.Insert] = newRanges
+	}
+	return changes
+}
+
+// finish berechnet Änderungsanfänge und Breite der Zeilennummern
+func (v *View) finish() {
+	highest := 0
+	for i, r := range v.rows {
+		highest = max(highest, r.oldLine, r.newLine)
+		if r.kind != rowEqual && (i == 0 || v.rows[i-1].kind == rowEqual) {
+			v.hunks = append(v.hunks, i)
+		}
+	}
+	v.numWidth = max(len(fmt.Sprint(highest)), 2)
+}
+
+// Content liefert den Text der Ansicht
+func (v *View) Content() string {
+	lines := make([]string, len(v.rows))
+	for i, r := range v.rows {
+		lines[i] = r.text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Hunks liefert die Zeilen (1-basiert), an denen eine Änderung beginnt
+func (v *View) Hunks() []int {
+	lines := make([]int, len(v.hunks))
+	for i, h := range v.hunks {
+		lines[i] = h + 1
+	}
+	return lines
+}
+
+// Stats zählt hinzugefügte und entfernte Zeilen
+func (v *View) Stats() (added, removed int) {
+	for _, r := range v.rows {
+		switch {
+		case r.kind == rowAdded, r.kind == rowChanged && r.added:
+			added++
+		case r.kind == rowRemoved, r.kind == rowChanged && !r.added:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// SetStyle tauscht den Style aus, z. B. nach einem Themewechsel
+func (v *View) SetStyle(style Style) {
+	v.style = style
+}
+
+// GutterWidth erfüllt textview.Decorator
+func (v *View) GutterWidth() int {
+	if v.unified {
+		return 2*v.numWidth + 4
+	}
+	return v.numWidth + 3
+}
+
+// Gutter erfüllt textview.Decorator und zeigt Zeilennummern und Vorzeichen
+func (v *View) Gutter(line int) string {
+	if line < 0 || line >= len(v.rows) {
+		return ""
+	}
+	r := v.rows[line]
+
+	var numbers string
+	if v.unified {
+		numbers = v.number(r.oldLine) + " " + v.number(r.newLine)
+	} else {
+		numbers = v.number(r.oldLine + r.newLine)
+	}
+
+	sign := string(r.sign)
+	if r.kind == rowFiller {
+		sign = " "
+	}
+	return v.style.LineNumber.Render(numbers+" ") + v.lineStyle(r).Render(sign) + " "
+}
+
+func (v *View) number(n int) string {
+	if n == 0 {
+		return strings.Repeat(" ", v.numWidth)
+	}
+	return fmt.Sprintf("%*d", v.numWidth, n)
+}
+
+// Decorate erfüllt textview.Decorator und färbt geänderte Zeilen
+func (v *View) Decorate(line int) (lipgloss.Style, []textview.Span, bool) {
+	if line < 0 || line >= len(v.rows) || v.rows[line].kind == rowEqual {
+		return lipgloss.Style{}, nil, false
+	}
+	r := v.rows[line]
+
+	textStyle := v.style.RemovedText
+	if r.added {
+		textStyle = v.style.AddedText
+	}
+	spans := make([]textview.Span, len(r.changes))
+	for i, c := range r.changes {
+		spans[i] = textview.Span{Start: c.Start, End: c.End, Style: textStyle}
+	}
+	return v.lineStyle(r), spans, true
+}
+
+func (v *View) lineStyle(r row) lipgloss.Style {
+	switch r.kind {
+	case rowAdded:
+		return v.style.Added
+	case rowRemoved:
+		return v.style.Removed
+	case rowChanged:
+		return v.style.Changed
+	case rowFiller:
+		return v.style.Filler
+	}
+	return lipgloss.NewStyle()
+}
@@ -0,0 +1,55 @@
+package diffview
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/diff"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// PatchView färbt einen eingelesenen Unified Diff als textview.Decorator
+type PatchView struct {
+	patch *diff.Patch
+	style Style
+}
+
+func NewPatchView(patch *diff.Patch, style Style) *PatchView {
+	return &PatchView{patch: patch, style: style}
+}
+
+// Patch liefert den zugrunde liegenden Patch
+func (v *PatchView) Patch() *diff.Patch {
+	return v.patch
+}
+
+// SetStyle tauscht den Style aus, z. B. nach einem Themewechsel
+func (v *PatchView) SetStyle(style Style) {
+	v.style = style
+}
+
+// GutterWidth erfüllt textview.Decorator, Patches haben keine Randspalte
+func (v *PatchView) GutterWidth() int {
+	return 0
+}
+
+func (v *PatchView) Gutter(int) string {
+	return ""
+}
+
+// Decorate erfüllt textview.Decorator und färbt Zeilen nach ihrer Rolle
+func (v *PatchView) Decorate(line int) (lipgloss.Style, []textview.Span, bool) {
+	if line < 0 || line >= len(v.patch.Kinds) {
+		return lipgloss.Style{}, nil, false
+	}
+
+	switch v.patch.Kinds[line] {
+	case diff.PatchFileHeader:
+		return v.style.FileHeader, nil, true
+	case diff.PatchHunkHeader:
+		return v.style.HunkHeader, nil, true
+	case diff.PatchAdded:
+		return v.style.Added, nil, true
+	case diff.PatchRemoved:
+		return v.style.Removed, nil, true
+	}
+	return lipgloss.Style{}, nil, false
+}
@@ -0,0 +1,56 @@
+package diffview
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Added       lipgloss.Style
+	Removed     lipgloss.Style
+	Changed     lipgloss.Style
+	AddedText   lipgloss.Style // Geänderte Zeichen innerhalb einer Zeile
+	RemovedText lipgloss.Style
+	Filler      lipgloss.Style // Leerzeilen zum Ausrichten der Seiten
+	LineNumber  lipgloss.Style
+	FileHeader  lipgloss.Style // Patch: diff --git, ---, +++
+	HunkHeader  lipgloss.Style // Patch: @@ -a,b +c,d @@
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Added: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Added)),
+
+		Removed: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Removed)),
+
+		Changed: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)),
+
+		AddedText: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Added)).
+			Bold(true),
+
+		RemovedText: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Removed)).
+			Bold(true),
+
+		Filler: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.Selection)),
+
+		LineNumber: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		FileHeader: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Bold(true),
+
+		HunkHeader: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)),
+	}
+}
@@ -0,0 +1,218 @@
+package helpview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Group fasst die Belegungen eines Modus zusammen
+type Group struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// row ist eine Zeile der Hilfe: Gruppentitel oder Belegung
+type row struct {
+	group   string
+	binding key.Binding
+	title   bool
+}
+
+// HelpView zeigt alle Belegungen scroll- und filterbar an
+type HelpView struct {
+	groups    []Group
+	rows      []row
+	help      help.Model
+	footer    []key.Binding
+	filter    string
+	filtering bool
+	offset    int
+	width     int
+	height    int
+	style     Style
+}
+
+// New erstellt die Hilfeansicht. footer enthält die Belegungen, die in der
+// Kurzhilfe am unteren Rand erscheinen.
+func New(groups []Group, footer []key.Binding, style Style) HelpView {
+	h := help.New()
+	h.Styles = style.Help
+
+	hv := HelpView{
+		groups: groups,
+		help:   h,
+		footer: footer,
+		style:  style,
+	}
+	hv.buildRows()
+	return hv
+}
+
+// SetGroups ersetzt die angezeigten Belegungen, z. B. nach einer Neubelegung
+func (h *HelpView) SetGroups(groups []Group, footer []key.Binding) {
+	h.groups = groups
+	h.footer = footer
+	h.buildRows()
+}
+
+func (h *HelpView) SetStyle(style Style) {
+	h.style = style
+	h.help.Styles = style.Help
+}
+
+func (h *HelpView) Resize(width, height int) {
+	h.width = width
+	h.height = height
+	h.help.Width = width
+	h.clampOffset()
+}
+
+// Reset setzt Filter und Scrollposition zurück
+func (h *HelpView) Reset() {
+	h.filter = ""
+	h.filtering = false
+	h.offset = 0
+	h.buildRows()
+}
+
+// Filtering meldet, ob gerade ein Filterbegriff eingegeben wird
+func (h *HelpView) Filtering() bool {
+	return h.filtering
+}
+
+// StartFilter beginnt die Eingabe eines Filterbegriffs
+func (h *HelpView) StartFilter() {
+	h.filtering = true
+	h.filter = ""
+	h.buildRows()
+}
+
+// UpdateFilter verarbeitet Tasten während der Filtereingabe
+func (h *HelpView) UpdateFilter(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		h.filtering = false
+	case tea.KeyEsc:
+		h.filtering = false
+		h.filter = ""
+	case tea.KeyBackspace:
+		if len(h.filter) > 0 {
+			runes := []rune(h.filter)
+			h.filter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		h.filter += string(msg.Runes)
+	}
+	h.offset = 0
+	h.buildRows()
+}
+
+func (h *HelpView) ScrollUp(lines int) {
+	h.offset -= lines
+	h.clampOffset()
+}
+
+func (h *HelpView) ScrollDown(lines int) {
+	h.offset += lines
+	h.clampOffset()
+}
+
+// buildRows erzeugt die Zeilen unter Berücksichtigung des Filters
+func (h *HelpView) buildRows() {
+	h.rows = h.rows[:0]
+	query := strings.ToLower(h.filter)
+
+	for _, group := range h.groups {
+		var matched []row
+		for _, b := range group.Bindings {
+			if !b.Enabled() {
+				continue
+			}
+			text := strings.ToLower(b.Help().Key + " " + b.Help().Desc + " " + group.Title)
+			if query != "" && !strings.Contains(text, query) {
+				continue
+			}
+			matched = append(matched, row{group: group.Title, binding: b})
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		h.rows = append(h.rows, row{group: group.Title, title: true})
+		h.rows = append(h.rows, matched...)
+	}
+	h.clampOffset()
+}
+
+// visibleRows liefert die Anzahl der Zeilen zwischen Kopf- und Fußzeile
+func (h *HelpView) visibleRows() int {
+	rows := h.height - 3
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+func (h *HelpView) clampOffset() {
+	maxOffset := len(h.rows) - h.visibleRows()
+	if h.offset > maxOffset {
+		h.offset = maxOffset
+	}
+	if h.offset < 0 {
+		h.offset = 0
+	}
+}
+
+func (h HelpView) Render() string {
+	var b strings.Builder
+
+	title := "Tastenbelegung"
+	if h.filter != "" || h.filtering {
+		title += h.style.Filter.Render(fmt.Sprintf("  /%s", h.filter))
+	}
+	b.WriteString(h.style.Title.Render(title))
+	b.WriteString("\n\n")
+
+	// Breite der Tastenspalte bestimmen
+	keyWidth := 0
+	for _, r := range h.rows {
+		if !r.title && lipgloss.Width(r.binding.Help().Key) > keyWidth {
+			keyWidth = lipgloss.Width(r.binding.Help().Key)
+		}
+	}
+
+	end := h.offset + h.visibleRows()
+	if end > len(h.rows) {
+		end = len(h.rows)
+	}
+
+	if len(h.rows) == 0 {
+		b.WriteString(h.style.Empty.Render("Keine passenden Belegungen"))
+		b.WriteString("\n")
+	}
+	for _, r := range h.rows[h.offset:end] {
+		if r.title {
+			b.WriteString(h.style.Group.Render(r.group))
+		} else {
+			keys := r.binding.Help().Key
+			padding := strings.Repeat(" ", keyWidth-lipgloss.Width(keys))
+			b.WriteString("  ")
+			b.WriteString(h.help.Styles.FullKey.Render(keys + padding))
+			b.WriteString(h.help.Styles.FullSeparator.Render(h.help.FullSeparator))
+			b.WriteString(h.help.Styles.FullDesc.Render(r.binding.Help().Desc))
+		}
+		b.WriteString("\n")
+	}
+
+	// Fehlende Zeilen auffüllen, damit die Kurzhilfe unten steht
+	for i := end - h.offset; i < h.visibleRows(); i++ {
+		b.WriteString("\n")
+	}
+	b.WriteString(h.help.ShortHelpView(h.footer))
+
+	return b.String()
+}
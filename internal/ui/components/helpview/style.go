@@ -0,0 +1,45 @@
+package helpview
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Title  lipgloss.Style
+	Group  lipgloss.Style
+	Filter lipgloss.Style
+	Empty  lipgloss.Style
+	Help   help.Styles
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Title: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Bold(true),
+
+		Group: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Underline(true),
+
+		Filter: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)),
+
+		Empty: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		Help: help.Styles{
+			Ellipsis:       lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LineNumbers)),
+			ShortKey:       lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Foreground)).Bold(true),
+			ShortDesc:      lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LineNumbers)),
+			ShortSeparator: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LineNumbers)),
+			FullKey:        lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Foreground)).Bold(true),
+			FullDesc:       lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Foreground)),
+			FullSeparator:  lipgloss.NewStyle().Foreground(lipgloss.Color(theme.LineNumbers)),
+		},
+	}
+}
@@ -0,0 +1,219 @@
+package hexview
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	wideRow   = 16 // Bytes pro Zeile wie bei xxd
+	narrowRow = 8  // Bytes pro Zeile bei schmalen Fenstern
+)
+
+// HexView zeigt Daten wie xxd mit Offset-, Hex- und ASCII-Spalte an. Gelesen
+// werden nur die sichtbaren Zeilen, daher eignet sie sich zusammen mit einem
+// seitenweisen Backend auch für sehr große Dateien.
+type HexView struct {
+	src        io.ReaderAt
+	size       int64
+	top        int64 // Erste sichtbare Zeile
+	cursor     int64 // Offset des aktuellen Bytes
+	matchStart int64
+	matchLen   int64
+	width      int
+	height     int
+	style      Style
+}
+
+func New(width, height int, style Style) HexView {
+	return HexView{
+		width:  width,
+		height: height,
+		style:  style,
+	}
+}
+
+// SetSource setzt die anzuzeigenden Daten
+func (h *HexView) SetSource(src io.ReaderAt, size int64) {
+	h.src = src
+	h.size = size
+	h.clamp()
+}
+
+func (h *HexView) SetStyle(style Style) {
+	h.style = style
+}
+
+func (h *HexView) Resize(width, height int) {
+	// Die Zeile des Cursors bleibt bei geänderter Zeilenbreite erhalten
+	h.width = width
+	h.height = height
+	h.top = min(h.top, h.row(h.cursor))
+	h.clamp()
+}
+
+// bytesPerRow richtet sich nach der verfügbaren Breite
+func (h *HexView) bytesPerRow() int64 {
+	if h.width >= rowWidth(wideRow) {
+		return wideRow
+	}
+	return narrowRow
+}
+
+// rowWidth ist die Breite einer Zeile: Offset, Hex-Gruppen zu zwei Bytes, ASCII
+func rowWidth(n int) int {
+	return 10 + n*2 + n/2 - 1 + 2 + n
+}
+
+func (h *HexView) row(offset int64) int64 {
+	return offset / h.bytesPerRow()
+}
+
+// Rows liefert die Anzahl der Zeilen
+func (h *HexView) Rows() int {
+	perRow := h.bytesPerRow()
+	return int((h.size + perRow - 1) / perRow)
+}
+
+// Top liefert die erste sichtbare Zeile
+func (h *HexView) Top() int {
+	return int(h.top)
+}
+
+// Height liefert die Anzahl sichtbarer Zeilen
+func (h *HexView) Height() int {
+	return h.height
+}
+
+// Offset liefert den Offset des aktuellen Bytes
+func (h *HexView) Offset() int64 {
+	return h.cursor
+}
+
+// Size liefert die Größe der Daten in Bytes
+func (h *HexView) Size() int64 {
+	return h.size
+}
+
+func (h *HexView) ScrollUp(rows int) {
+	h.moveRows(-int64(rows))
+}
+
+func (h *HexView) ScrollDown(rows int) {
+	h.moveRows(int64(rows))
+}
+
+func (h *HexView) moveRows(rows int64) {
+	perRow := h.bytesPerRow()
+	h.top += rows
+	h.cursor += rows * perRow
+	h.clamp()
+	// Der Cursor bleibt in der ersten sichtbaren Zeile wie bei der Textansicht
+	h.cursor = h.top * perRow
+}
+
+func (h *HexView) ScrollToTop() {
+	h.top = 0
+	h.cursor = 0
+}
+
+func (h *HexView) ScrollToBottom() {
+	h.top = int64(h.Rows())
+	h.clamp()
+	h.cursor = max(h.size-1, 0)
+}
+
+// GotoOffset springt zu einem Offset und zentriert dessen Zeile
+func (h *HexView) GotoOffset(offset int64) {
+	h.cursor = min(max(offset, 0), max(h.size-1, 0))
+	h.top = h.row(h.cursor) - int64(h.height/2)
+	h.clamp()
+}
+
+// SetMatch markiert einen Suchtreffer, length 0 hebt die Markierung auf
+func (h *HexView) SetMatch(offset, length int64) {
+	h.matchStart = offset
+	h.matchLen = length
+}
+
+func (h *HexView) clamp() {
+	maxTop := max(int64(h.Rows()-h.height), 0)
+	h.top = min(max(h.top, 0), maxTop)
+	h.cursor = min(max(h.cursor, 0), max(h.size-1, 0))
+}
+
+func (h HexView) inMatch(offset int64) bool {
+	return h.matchLen > 0 && offset >= h.matchStart && offset < h.matchStart+h.matchLen
+}
+
+// Render zeichnet die sichtbaren Zeilen
+func (h HexView) Render() string {
+	if h.src == nil {
+		return h.style.EmptyText.Render("Keine Datei geladen")
+	}
+
+	perRow := h.bytesPerRow()
+	buf := make([]byte, perRow*int64(h.height))
+	start := h.top * perRow
+	n, err := h.src.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return h.style.EmptyText.Render(err.Error())
+	}
+	buf = buf[:n]
+
+	lines := make([]string, 0, h.height)
+	for row := 0; row < h.height; row++ {
+		from := int64(row) * perRow
+		if from >= int64(len(buf)) {
+			break
+		}
+		to := min(from+perRow, int64(len(buf)))
+		lines = append(lines, h.renderRow(start+from, buf[from:to], perRow))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (h HexView) renderRow(offset int64, data []byte, perRow int64) string {
+	current := h.row(h.cursor) == h.row(offset)
+	styled := func(style lipgloss.Style, s string) string {
+		if current {
+			style = style.Inherit(h.style.CurrentLine)
+		}
+		return style.Render(s)
+	}
+
+	var b strings.Builder
+	b.WriteString(styled(h.style.Offset, fmt.Sprintf("%08x: ", offset)))
+
+	for i := int64(0); i < perRow; i++ {
+		cell := "  "
+		style := h.style.Hex
+		if i < int64(len(data)) {
+			cell = fmt.Sprintf("%02x", data[i])
+			if h.inMatch(offset + i) {
+				style = h.style.Match
+			}
+		}
+		b.WriteString(styled(style, cell))
+		if i%2 == 1 && i < perRow-1 {
+			b.WriteString(styled(h.style.Hex, " "))
+		}
+	}
+	b.WriteString(styled(h.style.Hex, "  "))
+
+	for i, c := range data {
+		style := h.style.ASCII
+		char := string(rune(c))
+		if c < 0x20 || c >= 0x7f {
+			style, char = h.style.NonPrint, "."
+		}
+		if h.inMatch(offset + int64(i)) {
+			style = h.style.Match
+		}
+		b.WriteString(styled(style, char))
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+package hexview
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Offset      lipgloss.Style
+	Hex         lipgloss.Style
+	ASCII       lipgloss.Style
+	NonPrint    lipgloss.Style // Nicht druckbare Bytes in der ASCII-Spalte
+	CurrentLine lipgloss.Style
+	Match       lipgloss.Style
+	EmptyText   lipgloss.Style
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Offset: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		Hex: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Foreground)),
+
+		ASCII: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)),
+
+		NonPrint: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		CurrentLine: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.Selection)).
+			Bold(true),
+
+		Match: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.Accent)).
+			Foreground(lipgloss.Color(theme.Background)).
+			Bold(true),
+
+		EmptyText: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Align(lipgloss.Center),
+	}
+}
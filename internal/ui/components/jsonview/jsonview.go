@@ -0,0 +1,291 @@
+package jsonview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+const indentWidth = 2
+
+// token ist ein hervorgehobener Bereich einer Zeile
+type token struct {
+	start, end int
+	kind       tokenKind
+}
+
+type tokenKind int
+
+const (
+	tokenKey tokenKind = iota
+	tokenValue
+	tokenCount
+)
+
+// line ist eine angezeigte Zeile des Baums
+type line struct {
+	node    *Node
+	closing bool // Schließende Klammer eines aufgeklappten Knotens
+	text    string
+	tokens  []token
+}
+
+// View zeigt einen JSON-Baum eingerückt mit auf- und zuklappbaren Objekten
+// und Arrays an. Als textview.Decorator färbt sie Schlüssel und Werte und
+// zeigt Klappmarken in der Randspalte.
+type View struct {
+	root  *Node
+	lines []line
+	style Style
+}
+
+func New(root *Node, style Style) *View {
+	v := &View{root: root, style: style}
+	v.render()
+	return v
+}
+
+// Content liefert den Text der aktuell sichtbaren Zeilen
+func (v *View) Content() string {
+	texts := make([]string, len(v.lines))
+	for i, l := range v.lines {
+		texts[i] = l.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// SetStyle tauscht den Style aus, z. B. nach einem Themewechsel
+func (v *View) SetStyle(style Style) {
+	v.style = style
+}
+
+// Root liefert den Wurzelknoten
+func (v *View) Root() *Node {
+	return v.root
+}
+
+// NodeAt liefert den Knoten einer Zeile (1-basiert)
+func (v *View) NodeAt(line int) *Node {
+	if line < 1 || line > len(v.lines) {
+		return nil
+	}
+	return v.lines[line-1].node
+}
+
+// Line liefert die Zeile (1-basiert), in der ein Knoten beginnt. Liegt er in
+// einem eingeklappten Knoten, ist es dessen Zeile.
+func (v *View) Line(n *Node) int {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Collapsed {
+			n = p
+		}
+	}
+	for i, l := range v.lines {
+		if l.node == n && !l.closing {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// foldTarget liefert den Knoten, den Klappbefehle in einer Zeile betreffen:
+// den Knoten selbst oder bei Werten das umgebende Objekt bzw. Array
+func (v *View) foldTarget(line int) *Node {
+	n := v.NodeAt(line)
+	if n != nil && !n.container() {
+		n = n.Parent
+	}
+	return n
+}
+
+// Toggle klappt den Knoten einer Zeile auf oder zu und liefert die Zeile,
+// in der er danach steht
+func (v *View) Toggle(line int) int {
+	if n := v.foldTarget(line); n != nil {
+		return v.setCollapsed(n, !n.Collapsed)
+	}
+	return line
+}
+
+// Collapse klappt den Knoten einer Zeile zu
+func (v *View) Collapse(line int) int {
+	if n := v.foldTarget(line); n != nil {
+		return v.setCollapsed(n, true)
+	}
+	return line
+}
+
+// Expand klappt den Knoten einer Zeile auf
+func (v *View) Expand(line int) int {
+	if n := v.foldTarget(line); n != nil {
+		return v.setCollapsed(n, false)
+	}
+	return line
+}
+
+func (v *View) setCollapsed(n *Node, collapsed bool) int {
+	n.Collapsed = collapsed
+	v.render()
+	return v.Line(n)
+}
+
+// CollapseAll klappt alle Knoten unterhalb der Wurzel zu und liefert die
+// neue Zeile des Knotens, der in line stand
+func (v *View) CollapseAll(line int) int {
+	n := v.NodeAt(line)
+	walk(v.root, func(c *Node) {
+		c.Collapsed = c != v.root && c.container()
+	})
+	v.render()
+	if n == nil {
+		return 1
+	}
+	return v.Line(n)
+}
+
+// ExpandAll klappt alle Knoten auf
+func (v *View) ExpandAll(line int) int {
+	n := v.NodeAt(line)
+	walk(v.root, func(c *Node) {
+		c.Collapsed = false
+	})
+	v.render()
+	if n == nil {
+		return 1
+	}
+	return v.Line(n)
+}
+
+func walk(n *Node, fn func(*Node)) {
+	fn(n)
+	for _, c := range n.Children {
+		walk(c, fn)
+	}
+}
+
+// render berechnet die sichtbaren Zeilen neu
+func (v *View) render() {
+	v.lines = v.lines[:0]
+	v.renderNode(v.root, 0, true)
+}
+
+func (v *View) renderNode(n *Node, depth int, last bool) {
+	var (
+		b      strings.Builder
+		tokens []token
+	)
+	b.WriteString(strings.Repeat(" ", depth*indentWidth))
+	if n.Parent != nil && n.Parent.Kind == Object {
+		start := b.Len()
+		b.WriteString(quote(n.Key))
+		tokens = append(tokens, token{start, b.Len(), tokenKey})
+		b.WriteString(": ")
+	}
+
+	comma := ","
+	if last {
+		comma = ""
+	}
+	openBr, closeBr := "{", "}"
+	if n.Kind == Array {
+		openBr, closeBr = "[", "]"
+	}
+
+	switch {
+	case n.Kind != Object && n.Kind != Array:
+		start := b.Len()
+		b.WriteString(n.Value)
+		tokens = append(tokens, token{start, b.Len(), tokenValue})
+	case len(n.Children) == 0:
+		b.WriteString(openBr + closeBr)
+	case n.Collapsed:
+		b.WriteString(openBr + "…" + closeBr)
+	default:
+		b.WriteString(openBr)
+		v.lines = append(v.lines, line{node: n, text: b.String(), tokens: tokens})
+		for i, c := range n.Children {
+			v.renderNode(c, depth+1, i == len(n.Children)-1)
+		}
+		indent := strings.Repeat(" ", depth*indentWidth)
+		v.lines = append(v.lines, line{node: n, closing: true, text: indent + closeBr + comma})
+		return
+	}
+
+	b.WriteString(comma)
+	if n.Collapsed && n.container() {
+		b.WriteString("  ")
+		start := b.Len()
+		b.WriteString(countText(n))
+		tokens = append(tokens, token{start, b.Len(), tokenCount})
+	}
+	v.lines = append(v.lines, line{node: n, text: b.String(), tokens: tokens})
+}
+
+// countText beschreibt die Anzahl der Kinder eines eingeklappten Knotens
+func countText(n *Node) string {
+	count := len(n.Children)
+	if n.Kind == Array {
+		if count == 1 {
+			return "1 Element"
+		}
+		return fmt.Sprintf("%d Elemente", count)
+	}
+	if count == 1 {
+		return "1 Schlüssel"
+	}
+	return fmt.Sprintf("%d Schlüssel", count)
+}
+
+// GutterWidth erfüllt textview.Decorator, die Randspalte zeigt Klappmarken
+func (v *View) GutterWidth() int {
+	return 2
+}
+
+func (v *View) Gutter(line int) string {
+	if line < 0 || line >= len(v.lines) {
+		return ""
+	}
+	l := v.lines[line]
+	if l.closing || !l.node.container() {
+		return ""
+	}
+	if l.node.Collapsed {
+		return v.style.Marker.Render("▸")
+	}
+	return v.style.Marker.Render("▾")
+}
+
+// Decorate erfüllt textview.Decorator und färbt Schlüssel und Werte
+func (v *View) Decorate(line int) (lipgloss.Style, []textview.Span, bool) {
+	if line < 0 || line >= len(v.lines) || len(v.lines[line].tokens) == 0 {
+		return lipgloss.Style{}, nil, false
+	}
+
+	l := v.lines[line]
+	spans := make([]textview.Span, len(l.tokens))
+	for i, t := range l.tokens {
+		spans[i] = textview.Span{Start: t.start, End: t.end, Style: v.tokenStyle(l.node, t.kind)}
+	}
+	return lipgloss.NewStyle(), spans, true
+}
+
+func (v *View) tokenStyle(n *Node, kind tokenKind) lipgloss.Style {
+	switch kind {
+	case tokenKey:
+		return v.style.Key
+	case tokenCount:
+		return v.style.Count
+	}
+	switch n.Kind {
+	case String:
+		return v.style.String
+	case Number:
+		return v.style.Number
+	case Bool:
+		return v.style.Bool
+	default:
+		return v.style.Null
+	}
+}
@@ -0,0 +1,219 @@
+package jsonview
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kind ist die Art eines JSON-Werts
+type Kind int
+
+const (
+	Object Kind = iota
+	Array
+	String
+	Number
+	Bool
+	Null
+)
+
+// Node ist ein Wert im JSON-Baum. Die Reihenfolge der Schlüssel bleibt
+// erhalten.
+type Node struct {
+	Key       string // Schlüssel im übergeordneten Objekt
+	Index     int    // Position im übergeordneten Array, sonst -1
+	Kind      Kind
+	Value     string // JSON-Text skalarer Werte
+	Children  []*Node
+	Parent    *Node
+	Collapsed bool
+}
+
+// container meldet Objekte und Arrays mit mindestens einem Kind
+func (n *Node) container() bool {
+	return (n.Kind == Object || n.Kind == Array) && len(n.Children) > 0
+}
+
+// Parse liest genau einen JSON-Wert
+func Parse(content string) (*Node, error) {
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+
+	root, err := parseNode(dec, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Ungültiges JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("Ungültiges JSON: Daten nach dem Ende des Werts")
+	}
+	return root, nil
+}
+
+func parseNode(dec *json.Decoder, parent *Node) (*Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Node{Parent: parent, Index: -1}
+	switch t := tok.(type) {
+	case json.Delim:
+		n.Kind = Object
+		if t == '[' {
+			n.Kind = Array
+		}
+		for dec.More() {
+			key := ""
+			if n.Kind == Object {
+				tok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ = tok.(string)
+			}
+			child, err := parseNode(dec, n)
+			if err != nil {
+				return nil, err
+			}
+			child.Key = key
+			if n.Kind == Array {
+				child.Index = len(n.Children)
+			}
+			n.Children = append(n.Children, child)
+		}
+		// Schließende Klammer
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		n.Kind = String
+		n.Value = quote(t)
+	case json.Number:
+		n.Kind = Number
+		n.Value = t.String()
+	case bool:
+		n.Kind = Bool
+		n.Value = strconv.FormatBool(t)
+	case nil:
+		n.Kind = Null
+		n.Value = "null"
+	}
+	return n, nil
+}
+
+// quote schreibt einen String als JSON, ohne HTML-Zeichen zu maskieren
+func quote(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Path liefert den Pfad eines Knotens, z. B. $.users[3].name
+func Path(n *Node) string {
+	var parts []string
+	for ; n.Parent != nil; n = n.Parent {
+		switch {
+		case n.Parent.Kind == Array:
+			parts = append(parts, fmt.Sprintf("[%d]", n.Index))
+		case identifier(n.Key):
+			parts = append(parts, "."+n.Key)
+		default:
+			parts = append(parts, "["+quote(n.Key)+"]")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("$")
+	for i := len(parts) - 1; i >= 0; i-- {
+		b.WriteString(parts[i])
+	}
+	return b.String()
+}
+
+func identifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Text liefert einen Wert als einzeiligen Text: Strings ohne
+// Anführungszeichen, Objekte und Arrays als kompaktes JSON
+func (n *Node) Text() string {
+	if n.Kind == String {
+		var s string
+		if err := json.Unmarshal([]byte(n.Value), &s); err == nil {
+			return s
+		}
+	}
+	return n.Compact()
+}
+
+// Compact liefert den Wert als kompaktes JSON
+func (n *Node) Compact() string {
+	var b strings.Builder
+	n.writeCompact(&b)
+	return b.String()
+}
+
+func (n *Node) writeCompact(b *strings.Builder) {
+	switch n.Kind {
+	case Object, Array:
+		openBr, closeBr := "{", "}"
+		if n.Kind == Array {
+			openBr, closeBr = "[", "]"
+		}
+		b.WriteString(openBr)
+		for i, c := range n.Children {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if n.Kind == Object {
+				b.WriteString(quote(c.Key))
+				b.WriteByte(':')
+			}
+			c.writeCompact(b)
+		}
+		b.WriteString(closeBr)
+	default:
+		b.WriteString(n.Value)
+	}
+}
+
+// Field sucht ein Feld über einen Pfad mit Punkten, z. B. "http.status".
+// Ein Schlüssel, der selbst Punkte enthält, hat Vorrang.
+func (n *Node) Field(path string) *Node {
+	if c := n.child(path); c != nil {
+		return c
+	}
+	for _, key := range strings.Split(path, ".") {
+		if n = n.child(key); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+func (n *Node) child(key string) *Node {
+	if n.Kind != Object {
+		return nil
+	}
+	for _, c := range n.Children {
+		if c.Key == key {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package jsonview
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Key    lipgloss.Style
+	String lipgloss.Style
+	Number lipgloss.Style
+	Bool   lipgloss.Style
+	Null   lipgloss.Style
+	Count  lipgloss.Style // Anzahl der Kinder eingeklappter Knoten
+	Marker lipgloss.Style // ▾ / ▸ in der Randspalte
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Key: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)),
+
+		String: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Added)),
+
+		Number: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)),
+
+		Bool: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Removed)),
+
+		Null: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Italic(true),
+
+		Count: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Italic(true),
+
+		Marker: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+	}
+}
@@ -0,0 +1,186 @@
+package listview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Item ist ein Eintrag der Liste
+type Item struct {
+	Title  string
+	Detail string // Rechtsbündige Zusatzinfo, z. B. +3 -1
+}
+
+// ListView ist eine scrollbare Liste mit Auswahl, etwa für die Dateien
+// eines Patches. Neben der Auswahl kann ein Eintrag als aktuell markiert
+// werden, z. B. die Datei unter dem Cursor.
+type ListView struct {
+	title    string
+	items    []Item
+	selected int
+	current  int
+	offset   int
+	width    int
+	height   int
+	focused  bool
+	style    Style
+}
+
+func New(title string, style Style) ListView {
+	return ListView{
+		title:   title,
+		current: -1,
+		style:   style,
+	}
+}
+
+// SetItems ersetzt die Einträge und setzt die Auswahl zurück
+func (l *ListView) SetItems(items []Item) {
+	l.items = items
+	l.selected = 0
+	l.offset = 0
+	l.current = -1
+}
+
+// SetTitle ändert die Titelzeile, z. B. um einen Filter anzuzeigen
+func (l *ListView) SetTitle(title string) {
+	l.title = title
+}
+
+func (l *ListView) Items() []Item {
+	return l.items
+}
+
+func (l *ListView) SetSize(width, height int) {
+	l.width = width
+	l.height = height
+	l.ensureVisible()
+}
+
+func (l *ListView) SetStyle(style Style) {
+	l.style = style
+}
+
+func (l *ListView) Focus() {
+	l.focused = true
+	// Die Auswahl beginnt beim aktuellen Eintrag
+	if l.current >= 0 {
+		l.Select(l.current)
+	}
+}
+
+func (l *ListView) Blur() {
+	l.focused = false
+}
+
+func (l *ListView) Focused() bool {
+	return l.focused
+}
+
+// Selected liefert den Index des ausgewählten Eintrags, -1 bei leerer Liste
+func (l *ListView) Selected() int {
+	if len(l.items) == 0 {
+		return -1
+	}
+	return l.selected
+}
+
+func (l *ListView) Select(index int) {
+	if len(l.items) == 0 {
+		return
+	}
+	l.selected = min(max(index, 0), len(l.items)-1)
+	l.ensureVisible()
+}
+
+func (l *ListView) MoveUp(n int) {
+	l.Select(l.selected - n)
+}
+
+func (l *ListView) MoveDown(n int) {
+	l.Select(l.selected + n)
+}
+
+// SetCurrent markiert den Eintrag, der zur Position im Text gehört
+func (l *ListView) SetCurrent(index int) {
+	l.current = index
+	if !l.focused && index >= 0 {
+		l.Select(index)
+	}
+}
+
+// visibleRows ist die Anzahl der Zeilen ohne Titel
+func (l *ListView) visibleRows() int {
+	return max(l.height-1, 1)
+}
+
+func (l *ListView) ensureVisible() {
+	rows := l.visibleRows()
+	if l.selected < l.offset {
+		l.offset = l.selected
+	}
+	if l.selected >= l.offset+rows {
+		l.offset = l.selected - rows + 1
+	}
+}
+
+// Render zeichnet die Liste mit Titelzeile und rechtem Rand
+func (l ListView) Render() string {
+	width := max(l.width-1, 1) // Rand rechts
+	lines := make([]string, 0, l.height)
+
+	titleStyle := l.style.Title
+	if l.focused {
+		titleStyle = l.style.FocusedTitle
+	}
+	lines = append(lines, titleStyle.Render(fit(" "+l.title, width)))
+
+	for i := l.offset; i < len(l.items) && len(lines) < l.height; i++ {
+		lines = append(lines, l.renderItem(i, width))
+	}
+	for len(lines) < l.height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+
+	border := l.style.Border.Render("│")
+	for i := range lines {
+		lines[i] += border
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (l ListView) renderItem(i, width int) string {
+	item := l.items[i]
+
+	detail := ""
+	if item.Detail != "" {
+		detail = " " + item.Detail
+	}
+	titleWidth := max(width-1-lipgloss.Width(detail), 1)
+	title := " " + fit(item.Title, titleWidth)
+
+	switch {
+	case l.focused && i == l.selected:
+		return l.style.Selected.Render(title + detail)
+	case i == l.current:
+		return l.style.Current.Render(title) + l.style.Detail.Render(detail)
+	default:
+		return l.style.Item.Render(title) + l.style.Detail.Render(detail)
+	}
+}
+
+// fit kürzt oder füllt einen Text auf die Breite. Lange Namen werden vorne
+// gekürzt, damit der Dateiname sichtbar bleibt.
+func fit(s string, width int) string {
+	w := lipgloss.Width(s)
+	if w > width {
+		runes := []rune(s)
+		for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+			runes = runes[1:]
+		}
+		return ansi.Truncate("…"+string(runes), width, "")
+	}
+	return s + strings.Repeat(" ", width-w)
+}
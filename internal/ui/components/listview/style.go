@@ -0,0 +1,48 @@
+package listview
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Title        lipgloss.Style
+	FocusedTitle lipgloss.Style
+	Item         lipgloss.Style
+	Detail       lipgloss.Style
+	Selected     lipgloss.Style
+	Current      lipgloss.Style // Eintrag an der Position im Text
+	Border       lipgloss.Style
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Title: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Background(lipgloss.Color(theme.Selection)),
+
+		FocusedTitle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Accent)).
+			Bold(true),
+
+		Item: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Foreground)),
+
+		Detail: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		Selected: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Accent)),
+
+		Current: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Bold(true),
+
+		Border: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Selection)),
+	}
+}
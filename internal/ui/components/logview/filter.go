@@ -0,0 +1,109 @@
+package logview
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Condition ist eine Bedingung auf ein Feld, z. B. level=error
+type Condition struct {
+	Field string
+	Op    string // "=", "!=" oder "~" (enthält)
+	Value string
+}
+
+func (c Condition) String() string {
+	if c.Value == "" || strings.ContainsAny(c.Value, " \t\"") {
+		return c.Field + c.Op + strconv.Quote(c.Value)
+	}
+	return c.Field + c.Op + c.Value
+}
+
+// ParseFilter liest Bedingungen wie "level=error service=api msg~timeout".
+// Werte mit Leerzeichen stehen in Anführungszeichen, z. B.
+// msg~"connection refused". Alle Bedingungen müssen zutreffen.
+func ParseFilter(expr string) ([]Condition, error) {
+	parts, err := splitFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	var conds []Condition
+	for _, part := range parts {
+		cond, ok := parseCondition(part)
+		if !ok {
+			return nil, fmt.Errorf("Ungültige Bedingung: %s (erwartet feld=wert, feld!=wert oder feld~text)", part)
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// splitFilter trennt die Bedingungen an Leerzeichen außerhalb von
+// Anführungszeichen. Die Anführungszeichen bleiben erhalten.
+func splitFilter(expr string) ([]string, error) {
+	var parts []string
+	var part strings.Builder
+	quoted, escaped := false, false
+	for _, r := range expr {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(r):
+			if part.Len() > 0 {
+				parts = append(parts, part.String())
+				part.Reset()
+			}
+			continue
+		}
+		part.WriteRune(r)
+	}
+	if quoted {
+		return nil, fmt.Errorf("Anführungszeichen nicht geschlossen: %s", part.String())
+	}
+	if part.Len() > 0 {
+		parts = append(parts, part.String())
+	}
+	return parts, nil
+}
+
+// parseCondition teilt am ersten Operator. Ein Wert in Anführungszeichen
+// wird wie ein Go-String gelesen, darin zählen Operatoren nicht.
+func parseCondition(part string) (Condition, bool) {
+	i, op := -1, ""
+	for _, o := range []string{"!=", "=", "~"} {
+		if j := strings.Index(part, o); j >= 0 && (i < 0 || j < i) {
+			i, op = j, o
+		}
+	}
+	if i <= 0 || strings.ContainsRune(part[:i], '"') {
+		return Condition{}, false
+	}
+
+	value := part[i+len(op):]
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return Condition{}, false
+		}
+		value = unquoted
+	}
+	return Condition{Field: part[:i], Op: op, Value: value}, true
+}
+
+// match prüft eine Bedingung, Groß- und Kleinschreibung zählen nicht
+func (c Condition) match(value string, present bool) bool {
+	switch c.Op {
+	case "=":
+		return present && strings.EqualFold(value, c.Value)
+	case "!=":
+		return !present || !strings.EqualFold(value, c.Value)
+	default:
+		return present && strings.Contains(strings.ToLower(value), strings.ToLower(c.Value))
+	}
+}
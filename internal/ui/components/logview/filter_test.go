@@ -0,0 +1,51 @@
+package logview
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []Condition
+	}{
+		{"", nil},
+		{"level=error service=api", []Condition{{"level", "=", "error"}, {"service", "=", "api"}}},
+		{"level!=debug", []Condition{{"level", "!=", "debug"}}},
+		{`msg~"connection refused"`, []Condition{{"msg", "~", "connection refused"}}},
+		{`level=error  msg~"connection refused" host!="a b"`, []Condition{
+			{"level", "=", "error"}, {"msg", "~", "connection refused"}, {"host", "!=", "a b"},
+		}},
+		{`msg="a=b ~c"`, []Condition{{"msg", "=", "a=b ~c"}}},
+		{`msg~a=b`, []Condition{{"msg", "~", "a=b"}}},
+		{`msg~"sagt \"nein\""`, []Condition{{"msg", "~", `sagt "nein"`}}},
+		{`msg=""`, []Condition{{"msg", "=", ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ParseFilter(%q) = %q, erwartet %q", tt.expr, got, tt.want)
+			}
+			// Die Darstellung lässt sich wieder lesen
+			for _, cond := range got {
+				again, err := ParseFilter(cond.String())
+				if err != nil || len(again) != 1 || again[0] != cond {
+					t.Errorf("%s gelesen als %q, %v", cond, again, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFilterInvalid(t *testing.T) {
+	for _, expr := range []string{"level", "=error", `msg~"offen`, `"msg"=a`, `msg="a"b`} {
+		if conds, err := ParseFilter(expr); err == nil {
+			t.Errorf("ParseFilter(%q) = %q, erwartet einen Fehler", expr, conds)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package logview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/ui/components/jsonview"
+	"github.com/fase22/tui/internal/ui/components/textview"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// maxColumnWidth begrenzt alle Spalten außer der letzten
+	maxColumnWidth = 40
+	columnGap      = "  "
+	sniffLines     = 10
+)
+
+var (
+	timeFields    = []string{"time", "timestamp", "ts", "@timestamp", "date"}
+	levelFields   = []string{"level", "lvl", "severity", "loglevel"}
+	messageFields = []string{"msg", "message"}
+)
+
+// Record ist eine Zeile der Eingabe. Node ist nil, wenn die Zeile kein
+// JSON-Objekt ist.
+type Record struct {
+	Line int // Quellzeile (0-basiert)
+	Raw  string
+	Node *jsonview.Node
+}
+
+// row ist eine angezeigte Zeile
+type row struct {
+	record     int
+	levelStart int // Byte-Bereich der Level-Spalte, -1 ohne
+	levelEnd   int
+}
+
+// View zeigt JSON Lines als ausgerichtete Spalten an. Als
+// textview.Decorator färbt sie nach dem Level.
+type View struct {
+	records []Record
+	columns []string
+	filter  []Condition
+	rows    []row
+	texts   []string
+	style   Style
+}
+
+// Sniff meldet, ob die ersten Zeilen überwiegend JSON-Objekte sind
+func Sniff(content string) bool {
+	objects, lines := 0, 0
+	for _, line := range strings.SplitN(content, "\n", sniffLines*2) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if lines++; lines > sniffLines {
+			break
+		}
+		if node, err := jsonview.Parse(line); err == nil && node.Kind == jsonview.Object {
+			objects++
+		}
+	}
+	return objects >= 2 && objects*3 >= lines*2
+}
+
+func New(content string, style Style) *View {
+	v := &View{style: style}
+	for i, line := range strings.Split(content, "\n") {
+		r := Record{Line: i, Raw: line}
+		if node, err := jsonview.Parse(line); err == nil && node.Kind == jsonview.Object {
+			r.Node = node
+		}
+		v.records = append(v.records, r)
+	}
+	// Eine abschließende Leerzeile ist kein Eintrag
+	if n := len(v.records); n > 1 && v.records[n-1].Raw == "" {
+		v.records = v.records[:n-1]
+	}
+	v.columns = v.defaultColumns()
+	v.render()
+	return v
+}
+
+// defaultColumns wählt Zeit, Level und Meldung, sonst die ersten Felder
+func (v *View) defaultColumns() []string {
+	var first *jsonview.Node
+	for _, r := range v.records {
+		if r.Node != nil {
+			first = r.Node
+			break
+		}
+	}
+	if first == nil {
+		return nil
+	}
+
+	var columns []string
+	for _, candidates := range [][]string{timeFields, levelFields, messageFields} {
+		for _, field := range candidates {
+			if first.Field(field) != nil {
+				columns = append(columns, field)
+				break
+			}
+		}
+	}
+	for _, c := range first.Children {
+		if len(columns) >= 3 {
+			break
+		}
+		if !contains(columns, c.Key) {
+			columns = append(columns, c.Key)
+		}
+	}
+	return columns
+}
+
+// Fields liefert alle Felder der obersten Ebene in der Reihenfolge ihres
+// ersten Auftretens
+func (v *View) Fields() []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, r := range v.records {
+		if r.Node == nil {
+			continue
+		}
+		for _, c := range r.Node.Children {
+			if !seen[c.Key] {
+				seen[c.Key] = true
+				fields = append(fields, c.Key)
+			}
+		}
+	}
+	return fields
+}
+
+// Columns liefert die angezeigten Felder
+func (v *View) Columns() []string {
+	return v.columns
+}
+
+// SetColumns wählt die angezeigten Felder, leer stellt die Vorgabe her
+func (v *View) SetColumns(columns []string) {
+	if len(columns) == 0 {
+		columns = v.defaultColumns()
+	}
+	v.columns = columns
+	v.render()
+}
+
+// Filter liefert die aktiven Bedingungen
+func (v *View) Filter() []Condition {
+	return v.filter
+}
+
+// SetFilter zeigt nur Einträge, die alle Bedingungen erfüllen. Zeilen, die
+// kein JSON sind, werden dabei ausgeblendet.
+func (v *View) SetFilter(conds []Condition) {
+	v.filter = conds
+	v.render()
+}
+
+// SetStyle tauscht den Style aus, z. B. nach einem Themewechsel
+func (v *View) SetStyle(style Style) {
+	v.style = style
+}
+
+// Content liefert den Text der angezeigten Zeilen
+func (v *View) Content() string {
+	return strings.Join(v.texts, "\n")
+}
+
+// Rows liefert die Anzahl der angezeigten und aller Einträge
+func (v *View) Rows() (shown, total int) {
+	return len(v.rows), len(v.records)
+}
+
+// RecordAt liefert den Eintrag einer angezeigten Zeile (1-basiert)
+func (v *View) RecordAt(line int) *Record {
+	if line < 1 || line > len(v.rows) {
+		return nil
+	}
+	return &v.records[v.rows[line-1].record]
+}
+
+// Line liefert die angezeigte Zeile (1-basiert) eines Eintrags oder 0
+func (v *View) Line(record *Record) int {
+	for i, r := range v.rows {
+		if &v.records[r.record] == record {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (v *View) matches(r Record) bool {
+	if len(v.filter) == 0 {
+		return true
+	}
+	if r.Node == nil {
+		return false
+	}
+	for _, cond := range v.filter {
+		value, present := "", false
+		if field := r.Node.Field(cond.Field); field != nil {
+			value, present = field.Text(), true
+		}
+		if !cond.match(value, present) {
+			return false
+		}
+	}
+	return true
+}
+
+// render berechnet Spaltenbreiten und Zeilen neu
+func (v *View) render() {
+	v.rows = v.rows[:0]
+	for i, r := range v.records {
+		if v.matches(r) {
+			v.rows = append(v.rows, row{record: i})
+		}
+	}
+
+	// Zellen und Spaltenbreiten über alle angezeigten Einträge
+	cells := make([][]string, len(v.rows))
+	widths := make([]int, len(v.columns))
+	for i, rw := range v.rows {
+		node := v.records[rw.record].Node
+		if node == nil {
+			continue
+		}
+		cells[i] = make([]string, len(v.columns))
+		for c, column := range v.columns {
+			if field := node.Field(column); field != nil {
+				cells[i][c] = singleLine(field.Text())
+			}
+			widths[c] = max(widths[c], min(runewidth.StringWidth(cells[i][c]), maxColumnWidth))
+		}
+	}
+
+	level := v.levelColumn()
+	v.texts = make([]string, len(v.rows))
+	for i := range v.rows {
+		rw := &v.rows[i]
+		rw.levelStart, rw.levelEnd = -1, -1
+		if cells[i] == nil {
+			v.texts[i] = v.records[rw.record].Raw
+			continue
+		}
+
+		var b strings.Builder
+		for c, cell := range cells[i] {
+			if c > 0 {
+				b.WriteString(columnGap)
+			}
+			if c < len(cells[i])-1 {
+				cell = runewidth.FillRight(runewidth.Truncate(cell, widths[c], "…"), widths[c])
+			}
+			if c == level {
+				rw.levelStart = b.Len()
+				rw.levelEnd = b.Len() + len(strings.TrimRight(cell, " "))
+			}
+			b.WriteString(cell)
+		}
+		v.texts[i] = strings.TrimRight(b.String(), " ")
+	}
+}
+
+// levelColumn liefert den Index der Level-Spalte oder -1
+func (v *View) levelColumn() int {
+	for i, column := range v.columns {
+		if contains(levelFields, strings.ToLower(column)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// levelStyle wählt den Stil für ein Level, ok ist false bei unbekannten
+func (v *View) levelStyle(r Record) (lipgloss.Style, bool) {
+	var level string
+	for _, field := range levelFields {
+		if node := r.Node.Field(field); node != nil {
+			level = strings.ToLower(node.Text())
+			break
+		}
+	}
+
+	switch level {
+	case "error", "err", "fatal", "panic", "critical", "crit", "alert", "emerg":
+		return v.style.Error, true
+	case "warn", "warning":
+		return v.style.Warn, true
+	case "info", "notice":
+		return v.style.Info, true
+	case "debug", "trace":
+		return v.style.Debug, true
+	}
+	return lipgloss.Style{}, false
+}
+
+// GutterWidth erfüllt textview.Decorator, es gibt keine Randspalte
+func (v *View) GutterWidth() int {
+	return 0
+}
+
+func (v *View) Gutter(int) string {
+	return ""
+}
+
+// Decorate erfüllt textview.Decorator. Gefärbt wird die Level-Spalte oder,
+// wenn sie nicht angezeigt wird, die ganze Zeile.
+func (v *View) Decorate(line int) (lipgloss.Style, []textview.Span, bool) {
+	if line < 0 || line >= len(v.rows) {
+		return lipgloss.Style{}, nil, false
+	}
+
+	rw := v.rows[line]
+	r := v.records[rw.record]
+	if r.Node == nil {
+		return v.style.Raw, nil, true
+	}
+	style, ok := v.levelStyle(r)
+	if !ok {
+		return lipgloss.Style{}, nil, false
+	}
+	if rw.levelStart < 0 {
+		return style, nil, true
+	}
+	return lipgloss.NewStyle(), []textview.Span{{Start: rw.levelStart, End: rw.levelEnd, Style: style}}, true
+}
+
+// singleLine ersetzt Zeilenumbrüche, damit ein Wert in eine Zelle passt
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", "↵", "\n", "↵", "\t", " ").Replace(s)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
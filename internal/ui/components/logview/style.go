@@ -0,0 +1,37 @@
+package logview
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Error lipgloss.Style // error, fatal, panic, critical
+	Warn  lipgloss.Style
+	Info  lipgloss.Style
+	Debug lipgloss.Style // debug, trace
+	Raw   lipgloss.Style // Zeilen, die kein JSON-Objekt sind
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Error: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Removed)).
+			Bold(true),
+
+		Warn: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)),
+
+		Info: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)),
+
+		Debug: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		Raw: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Italic(true),
+	}
+}
@@ -0,0 +1,208 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// language beschreibt, was der Highlighter in einer Sprache erkennt
+type language struct {
+	keywords     map[string]bool
+	lineComments []string
+	blockComment [2]string
+	quotes       string // Zeichen, die Zeichenketten einschließen
+	ignoreCase   bool
+}
+
+func words(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+var (
+	langGo = &language{
+		keywords: words(`break case chan const continue default defer else fallthrough for func go goto if
+			import interface map package range return select struct switch type var
+			true false nil iota bool byte rune string error int int8 int16 int32 int64
+			uint uint8 uint16 uint32 uint64 uintptr float32 float64 any
+			append cap close copy delete len make new panic print println recover min max`),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}
+	langJS = &language{
+		keywords: words(`break case catch class const continue debugger default delete do else export
+			extends finally for function if import in instanceof let new return super switch this
+			throw try typeof var void while with yield async await of static get set
+			true false null undefined interface type enum implements readonly public private protected`),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}
+	langC = &language{
+		keywords: words(`auto break case char const continue default do double else enum extern float for
+			goto if inline int long register return short signed sizeof static struct switch typedef
+			union unsigned void volatile while class namespace template typename public private
+			protected virtual override new delete this throw try catch using bool true false nullptr
+			final import package extends implements interface abstract boolean byte null super
+			synchronized throws var record string fn let mut impl trait pub use mod match loop
+			crate self Self where async await dyn move ref unsafe`),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'",
+	}
+	langPython = &language{
+		keywords: words(`and as assert async await break class continue def del elif else except finally
+			for from global if import in is lambda nonlocal not or pass raise return try while with
+			yield True False None self print len range`),
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	}
+	langShell = &language{
+		keywords: words(`if then else elif fi case esac for while until do done in function return
+			export local readonly set unset echo cd exit source alias`),
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	}
+	langSQL = &language{
+		keywords: words(`select from where insert into values update set delete create table drop alter
+			index join left right inner outer on as and or not null is in like group by order having
+			limit offset distinct union all primary key foreign references default case when then
+			else end begin commit rollback`),
+		lineComments: []string{"--"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "'\"",
+		ignoreCase:   true,
+	}
+	langData = &language{
+		keywords:     words(`true false null yes no on off`),
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	}
+)
+
+var languages = map[string]*language{
+	"go": langGo, "golang": langGo,
+	"js": langJS, "javascript": langJS, "jsx": langJS, "ts": langJS, "typescript": langJS, "tsx": langJS,
+	"c": langC, "h": langC, "cpp": langC, "c++": langC, "cc": langC, "java": langC, "kotlin": langC,
+	"cs": langC, "csharp": langC, "rust": langC, "rs": langC, "swift": langC,
+	"py": langPython, "python": langPython,
+	"sh": langShell, "bash": langShell, "shell": langShell, "zsh": langShell, "console": langShell,
+	"sql":  langSQL,
+	"json": langData, "yaml": langData, "yml": langData, "toml": langData, "ini": langData,
+}
+
+// highlighter hebt Codezeilen einer Sprache hervor. Blockkommentare über
+// mehrere Zeilen werden verfolgt.
+type highlighter struct {
+	lang    *language
+	comment bool // Innerhalb eines Blockkommentars
+}
+
+func newHighlighter(lang string) *highlighter {
+	return &highlighter{lang: languages[strings.ToLower(lang)]}
+}
+
+// line liefert die hervorgehobenen Bereiche einer Codezeile. offset wird
+// auf alle Bereiche addiert.
+func (h *highlighter) line(text string, offset int) []token {
+	lang := h.lang
+	if lang == nil {
+		return nil
+	}
+
+	var tokens []token
+	add := func(start, end int, m mark) {
+		if end > start {
+			tokens = append(tokens, token{start: offset + start, end: offset + end, mark: m})
+		}
+	}
+
+	i := 0
+	if h.comment {
+		end := strings.Index(text, lang.blockComment[1])
+		if end < 0 {
+			add(0, len(text), markComment)
+			return tokens
+		}
+		i = end + len(lang.blockComment[1])
+		add(0, i, markComment)
+		h.comment = false
+	}
+
+	for i < len(text) {
+		rest := text[i:]
+		switch c := text[i]; {
+		case lang.blockComment[0] != "" && strings.HasPrefix(rest, lang.blockComment[0]):
+			end := strings.Index(rest[len(lang.blockComment[0]):], lang.blockComment[1])
+			if end < 0 {
+				add(i, len(text), markComment)
+				h.comment = true
+				return tokens
+			}
+			end += len(lang.blockComment[0]) + len(lang.blockComment[1])
+			add(i, i+end, markComment)
+			i += end
+
+		case hasAnyPrefix(rest, lang.lineComments) && (i == 0 || !isWord(text[i-1])):
+			add(i, len(text), markComment)
+			return tokens
+
+		case strings.IndexByte(lang.quotes, c) >= 0:
+			end := i + 1
+			for end < len(text) && text[end] != c {
+				if text[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(text))
+			add(i, end, markString)
+			i = end
+
+		case c >= '0' && c <= '9' && (i == 0 || !isWord(text[i-1])):
+			end := i
+			for end < len(text) && (isWord(text[end]) || text[end] == '.') {
+				end++
+			}
+			add(i, end, markNumber)
+			i = end
+
+		case isWord(c):
+			end := i
+			for end < len(text) && isWord(text[end]) {
+				end++
+			}
+			word := text[i:end]
+			if lang.ignoreCase {
+				word = strings.ToLower(word)
+			}
+			if lang.keywords[word] {
+				add(i, end, markKeyword)
+			}
+			i = end
+
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			i += size
+		}
+	}
+	return tokens
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWord(c byte) bool {
+	return c == '_' || c < utf8.RuneSelf && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
@@ -0,0 +1,206 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// builder sammelt den Text einer Zeile und ihre hervorgehobenen Bereiche
+type builder struct {
+	b      strings.Builder
+	tokens []token
+}
+
+func (w *builder) write(s string, m mark) {
+	if s == "" {
+		return
+	}
+	if m != 0 {
+		w.tokens = append(w.tokens, token{start: w.b.Len(), end: w.b.Len() + len(s), mark: m})
+	}
+	w.b.WriteString(s)
+}
+
+func (w *builder) Len() int {
+	return w.b.Len()
+}
+
+func (w *builder) String() string {
+	return w.b.String()
+}
+
+// inline rendert Hervorhebungen, Code und Links innerhalb einer Zeile. m
+// sind die Markierungen des umgebenden Texts.
+func inline(w *builder, s string, m mark) {
+	plain := 0
+	flush := func(i int) {
+		w.write(s[plain:i], m)
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			flush(i)
+			w.write(s[i+1:i+2], m)
+			i += 2
+			plain = i
+			continue
+
+		case c == '`':
+			n := run(s, i, '`')
+			if end := strings.Index(s[i+n:], strings.Repeat("`", n)); end >= 0 {
+				flush(i)
+				code := s[i+n : i+n+end]
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				w.write(code, m|markCode)
+				i += n + end + n
+				plain = i
+				continue
+			}
+			i += n
+			continue
+
+		case c == '*' || c == '_' || c == '~':
+			n := run(s, i, c)
+			if end, ok := closing(s, i, n, c); ok {
+				inner := s[i+n : end]
+				flush(i)
+				inline(w, inner, m|emphasis(c, n))
+				i = end + n
+				plain = i
+				continue
+			}
+			i += n
+			continue
+
+		case c == '!' && strings.HasPrefix(s[i+1:], "["):
+			if text, url, end, ok := link(s, i+1); ok {
+				flush(i)
+				w.write("[Bild: ", m|markURL)
+				inline(w, text, m|markLink)
+				w.write("] ("+url+")", m|markURL)
+				i = end
+				plain = i
+				continue
+			}
+
+		case c == '[':
+			if text, url, end, ok := link(s, i); ok {
+				flush(i)
+				inline(w, text, m|markLink)
+				if url != "" && url != text {
+					w.write(" ("+url+")", m|markURL)
+				}
+				i = end
+				plain = i
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				url := s[i+1 : i+end]
+				if strings.Contains(url, "://") || strings.HasPrefix(url, "mailto:") {
+					flush(i)
+					w.write(url, m|markLink)
+					i += end + 1
+					plain = i
+					continue
+				}
+			}
+		}
+		i++
+	}
+	flush(len(s))
+}
+
+// emphasis liefert die Markierung für * und _ (einfach kursiv, doppelt
+// fett, dreifach beides) sowie ~~ (durchgestrichen)
+func emphasis(c byte, n int) mark {
+	switch {
+	case c == '~':
+		return markStrike
+	case n == 1:
+		return markEmphasis
+	case n == 2:
+		return markStrong
+	default:
+		return markStrong | markEmphasis
+	}
+}
+
+// closing sucht das Ende einer Hervorhebung, die bei start mit n Zeichen c
+// beginnt. Der Inhalt darf nicht mit Leerraum beginnen oder enden, _
+// innerhalb von Wörtern zählt nicht.
+func closing(s string, start, n int, c byte) (int, bool) {
+	if n > 3 || (c == '~' && n != 2) {
+		return 0, false
+	}
+	open := start + n
+	if open >= len(s) || s[open] == ' ' || (c == '_' && start > 0 && isWord(s[start-1])) {
+		return 0, false
+	}
+	for i := open + 1; i < len(s); i++ {
+		if s[i] == '`' {
+			// Code hat Vorrang vor Hervorhebungen
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				i += end + 1
+			}
+			continue
+		}
+		if s[i] != c || run(s, i, c) != n || s[i-1] == ' ' {
+			if s[i] == c {
+				i += run(s, i, c) - 1
+			}
+			continue
+		}
+		if c == '_' && i+n < len(s) && isWord(s[i+n]) {
+			continue
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// link liest [text](url) ab start. end ist die Position nach dem Link.
+func link(s string, start int) (text, url string, end int, ok bool) {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if i+1 >= len(s) || s[i+1] != '(' {
+				return "", "", 0, false
+			}
+			paren := strings.IndexByte(s[i+2:], ')')
+			if paren < 0 {
+				return "", "", 0, false
+			}
+			url = s[i+2 : i+2+paren]
+			// Titel wie in [a](url "Titel") weglassen
+			if space := strings.IndexByte(url, ' '); space >= 0 {
+				url = url[:space]
+			}
+			return s[start+1 : i], url, i + 2 + paren + 1, true
+		}
+	}
+	return "", "", 0, false
+}
+
+func run(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("\\`*_{}[]()#+-.!|<>~", c) >= 0
+}
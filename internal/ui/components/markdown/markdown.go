@@ -0,0 +1,432 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/ui/components/textview"
+	"github.com/mattn/go-runewidth"
+)
+
+const ruleWidth = 40
+
+// mark ist eine Menge von Auszeichnungen. Ein Bereich kann mehrere tragen,
+// z. B. fett und kursiv in einem Link.
+type mark uint32
+
+const (
+	markStrong mark = 1 << iota
+	markEmphasis
+	markStrike
+	markCode
+	markLink
+	markURL
+	markHeading1
+	markHeading2
+	markHeading3
+	markQuote
+	markMarker
+	markBorder
+	markCodeBlock
+	markKeyword
+	markString
+	markNumber
+	markComment
+)
+
+// token ist ein ausgezeichneter Bereich einer Zeile
+type token struct {
+	start, end int
+	mark       mark
+}
+
+// line ist eine gerenderte Zeile
+type line struct {
+	text   string
+	src    int  // Quellzeile (0-basiert)
+	base   mark // Auszeichnung der ganzen Zeile
+	tokens []token
+}
+
+var (
+	headingRe = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	fenceRe   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})\\s*([^`\\s]*)")
+	ruleRe    = regexp.MustCompile(`^ {0,3}((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	listRe    = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])(\s+|$)(\[[ xX]\]\s+)?`)
+	quoteRe   = regexp.MustCompile(`^ {0,3}>\s?`)
+	setextRe  = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	tableRe   = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// View zeigt Markdown gerendert an: Überschriften, Hervorhebungen,
+// Codeblöcke mit Syntax-Hervorhebung, Listen, Zitate, Tabellen und Links.
+// Als textview.Decorator färbt sie den Text nach dem Theme.
+type View struct {
+	lines []line
+	style Style
+}
+
+func New(source string, style Style) *View {
+	v := &View{style: style}
+	v.render(strings.Split(source, "\n"))
+	return v
+}
+
+// Content liefert den gerenderten Text
+func (v *View) Content() string {
+	texts := make([]string, len(v.lines))
+	for i, l := range v.lines {
+		texts[i] = l.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// SetStyle tauscht den Style aus, z. B. nach einem Themewechsel
+func (v *View) SetStyle(style Style) {
+	v.style = style
+}
+
+// SourceLine liefert die Quellzeile (1-basiert) einer gerenderten Zeile
+// (1-basiert)
+func (v *View) SourceLine(line int) int {
+	if line < 1 || line > len(v.lines) {
+		return 0
+	}
+	return v.lines[line-1].src + 1
+}
+
+// Line liefert die erste gerenderte Zeile (1-basiert) zu einer Quellzeile
+// (1-basiert). Fehlt die Quellzeile, z. B. ein Codezaun, ist es die nächste.
+func (v *View) Line(src int) int {
+	for i, l := range v.lines {
+		if l.src+1 >= src {
+			return i + 1
+		}
+	}
+	return len(v.lines)
+}
+
+func (v *View) add(text string, src int, base mark, tokens []token) {
+	v.lines = append(v.lines, line{text: text, src: src, base: base, tokens: tokens})
+}
+
+// addInline rendert eine Zeile mit Hervorhebungen hinter einem Präfix
+func (v *View) addInline(prefix string, prefixMark mark, text string, src int, base mark) {
+	var w builder
+	w.write(prefix, prefixMark)
+	inline(&w, text, 0)
+	v.add(w.String(), src, base, w.tokens)
+}
+
+// render wandelt die Quellzeilen blockweise um
+func (v *View) render(src []string) {
+	for i := 0; i < len(src); i++ {
+		text := src[i]
+
+		// Codeblock mit Zaun
+		if m := fenceRe.FindStringSubmatch(text); m != nil {
+			i = v.renderFence(src, i, m[2], m[3])
+			continue
+		}
+
+		// Eingerückter Codeblock nach einer Leerzeile
+		if strings.HasPrefix(text, "    ") && (i == 0 || strings.TrimSpace(src[i-1]) == "") && !listRe.MatchString(text) {
+			i = v.renderIndented(src, i)
+			continue
+		}
+
+		// Tabelle: Kopfzeile und Trennzeile
+		if strings.Contains(text, "|") && i+1 < len(src) && tableRe.MatchString(src[i+1]) && strings.Contains(src[i+1], "-") {
+			i = v.renderTable(src, i)
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(text) == "":
+			v.add("", i, 0, nil)
+
+		case headingRe.MatchString(text):
+			m := headingRe.FindStringSubmatch(text)
+			v.renderHeading(m[2], len(m[1]), i)
+
+		case i+1 < len(src) && setextRe.MatchString(src[i+1]) && !listRe.MatchString(text) && !quoteRe.MatchString(text):
+			level := 1
+			if strings.Contains(src[i+1], "-") {
+				level = 2
+			}
+			v.renderHeading(strings.TrimSpace(text), level, i)
+			i++ // Die Unterstreichung gehört zur Überschrift
+
+		case ruleRe.MatchString(text):
+			v.add(strings.Repeat("─", ruleWidth), i, markBorder, nil)
+
+		case quoteRe.MatchString(text):
+			v.renderQuote(text, i)
+
+		case listRe.MatchString(text):
+			v.renderListItem(text, i)
+
+		default:
+			v.addInline("", 0, text, i, 0)
+		}
+	}
+}
+
+func (v *View) renderHeading(text string, level int, src int) {
+	base := markHeading3
+	switch level {
+	case 1:
+		base = markHeading1
+	case 2:
+		base = markHeading2
+	}
+	v.addInline("", 0, text, src, base)
+
+	// Die obersten Ebenen werden unterstrichen
+	var w builder
+	inline(&w, text, 0)
+	width := runewidth.StringWidth(w.String())
+	switch level {
+	case 1:
+		v.add(strings.Repeat("═", width), src, markBorder, nil)
+	case 2:
+		v.add(strings.Repeat("─", width), src, markBorder, nil)
+	}
+}
+
+// renderFence rendert einen Codeblock ab der Zaunzeile und liefert die
+// Zeile des schließenden Zauns
+func (v *View) renderFence(src []string, start int, fence, lang string) int {
+	if lang != "" {
+		v.add("  "+lang, start, markMarker, nil)
+	}
+
+	h := newHighlighter(lang)
+	i := start + 1
+	for ; i < len(src); i++ {
+		text := src[i]
+		trimmed := strings.TrimSpace(text)
+		if strings.HasPrefix(trimmed, fence[:1]) && strings.Trim(trimmed, fence[:1]) == "" && len(trimmed) >= len(fence) {
+			break
+		}
+		v.add("  "+text, i, markCodeBlock, h.line(text, 2))
+	}
+	return i
+}
+
+// renderIndented rendert einen um vier Leerzeichen eingerückten Codeblock
+func (v *View) renderIndented(src []string, start int) int {
+	i := start
+	for ; i < len(src); i++ {
+		text := src[i]
+		if strings.TrimSpace(text) != "" && !strings.HasPrefix(text, "    ") {
+			break
+		}
+		if strings.TrimSpace(text) == "" && (i+1 >= len(src) || !strings.HasPrefix(src[i+1], "    ")) {
+			break
+		}
+		v.add("  "+strings.TrimPrefix(text, "    "), i, markCodeBlock, nil)
+	}
+	return i - 1
+}
+
+// renderQuote rendert ein Zitat, verschachtelte Zitate erhalten je einen
+// eigenen Balken
+func (v *View) renderQuote(text string, src int) {
+	depth := 0
+	for quoteRe.MatchString(text) {
+		text = quoteRe.ReplaceAllString(text, "")
+		depth++
+	}
+
+	if m := headingRe.FindStringSubmatch(text); m != nil {
+		text = m[2]
+	}
+	v.addInline(strings.Repeat("│ ", depth), markMarker, text, src, markQuote)
+}
+
+// renderListItem rendert einen Listenpunkt mit Aufzählungszeichen,
+// nummerierte Listen behalten ihre Nummer, Aufgaben erhalten ein Kästchen
+func (v *View) renderListItem(text string, src int) {
+	m := listRe.FindStringSubmatch(text)
+	indent, marker, task := m[1], m[2], strings.TrimSpace(m[4])
+	rest := text[len(m[0]):]
+
+	bullet := marker + " "
+	if strings.ContainsAny(marker, "-*+") {
+		bullets := []string{"•", "◦", "▪"}
+		bullet = bullets[(len(indent)/2)%len(bullets)] + " "
+	}
+	switch task {
+	case "[ ]":
+		bullet += "☐ "
+	case "[x]", "[X]":
+		bullet += "☑ "
+	}
+	v.addInline(indent+bullet, markMarker, rest, src, 0)
+}
+
+// cell ist eine gerenderte Tabellenzelle
+type cell struct {
+	text   string
+	tokens []token
+}
+
+// renderTable rendert eine Tabelle ab der Kopfzeile und liefert ihre
+// letzte Zeile
+func (v *View) renderTable(src []string, start int) int {
+	aligns := splitRow(src[start+1])
+	var rows [][]cell
+	end := start
+	for i := start; i < len(src); i++ {
+		if i == start+1 {
+			continue
+		}
+		if i > start+1 && (strings.TrimSpace(src[i]) == "" || !strings.Contains(src[i], "|")) {
+			break
+		}
+		var row []cell
+		for _, text := range splitRow(src[i]) {
+			var w builder
+			inline(&w, text, 0)
+			row = append(row, cell{text: w.String(), tokens: w.tokens})
+		}
+		rows = append(rows, row)
+		end = i
+	}
+
+	columns := len(aligns)
+	widths := make([]int, columns)
+	for _, row := range rows {
+		for c := 0; c < min(len(row), columns); c++ {
+			widths[c] = max(widths[c], runewidth.StringWidth(row[c].text))
+		}
+	}
+
+	for r, row := range rows {
+		srcLine := start + r
+		if r > 0 {
+			srcLine++ // Trennzeile überspringen
+		}
+		var w builder
+		for c := 0; c < columns; c++ {
+			if c > 0 {
+				w.write(" │ ", markBorder)
+			}
+			var cl cell
+			if c < len(row) {
+				cl = row[c]
+			}
+			left, right := padding(runewidth.StringWidth(cl.text), widths[c], aligns[c])
+			w.write(strings.Repeat(" ", left), 0)
+			offset := w.Len()
+			for _, t := range cl.tokens {
+				w.tokens = append(w.tokens, token{start: offset + t.start, end: offset + t.end, mark: t.mark})
+			}
+			if r == 0 {
+				w.tokens = append(w.tokens, token{start: offset, end: offset + len(cl.text), mark: markStrong})
+			}
+			w.write(cl.text, 0)
+			w.write(strings.Repeat(" ", right), 0)
+		}
+		v.add(strings.TrimRight(w.String(), " "), srcLine, 0, w.tokens)
+
+		if r == 0 {
+			parts := make([]string, columns)
+			for c, width := range widths {
+				parts[c] = strings.Repeat("─", width)
+			}
+			v.add(strings.Join(parts, "─┼─"), start+1, markBorder, nil)
+		}
+	}
+	return end
+}
+
+// splitRow zerlegt eine Tabellenzeile in Zellen, \| gehört zur Zelle
+func splitRow(text string) []string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "|")
+	if strings.HasSuffix(text, "|") && !strings.HasSuffix(text, "\\|") {
+		text = text[:len(text)-1]
+	}
+
+	var cells []string
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text) && text[i+1] == '|':
+			b.WriteByte('|')
+			i++
+		case text[i] == '|':
+			cells = append(cells, strings.TrimSpace(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(b.String()))
+}
+
+// padding verteilt den Leerraum einer Zelle nach der Ausrichtung aus der
+// Trennzeile (:--, :-:, --:)
+func padding(width, column int, align string) (left, right int) {
+	space := column - width
+	switch {
+	case strings.HasPrefix(align, ":") && strings.HasSuffix(align, ":"):
+		return space / 2, space - space/2
+	case strings.HasSuffix(align, ":"):
+		return space, 0
+	}
+	return 0, space
+}
+
+// GutterWidth erfüllt textview.Decorator, es gibt keine Randspalte
+func (v *View) GutterWidth() int {
+	return 0
+}
+
+func (v *View) Gutter(int) string {
+	return ""
+}
+
+// Decorate erfüllt textview.Decorator
+func (v *View) Decorate(n int) (lipgloss.Style, []textview.Span, bool) {
+	if n < 0 || n >= len(v.lines) {
+		return lipgloss.Style{}, nil, false
+	}
+	l := v.lines[n]
+	if l.base == 0 && len(l.tokens) == 0 {
+		return lipgloss.Style{}, nil, false
+	}
+
+	spans := make([]textview.Span, len(l.tokens))
+	for i, t := range l.tokens {
+		spans[i] = textview.Span{Start: t.start, End: t.end, Style: v.markStyle(t.mark)}
+	}
+	return v.markStyle(l.base), spans, true
+}
+
+// markStyle setzt den Stil aus allen Auszeichnungen zusammen. Die
+// speziellere Auszeichnung gewinnt, z. B. Code vor Link vor Überschrift.
+func (v *View) markStyle(m mark) lipgloss.Style {
+	s := v.style
+	order := []struct {
+		mark  mark
+		style lipgloss.Style
+	}{
+		{markKeyword, s.Keyword}, {markString, s.String}, {markNumber, s.Number}, {markComment, s.Comment},
+		{markCode, s.Code}, {markURL, s.URL}, {markLink, s.Link}, {markMarker, s.Marker}, {markBorder, s.Border},
+		{markStrong, s.Strong}, {markEmphasis, s.Emphasis}, {markStrike, s.Strike},
+		{markHeading1, s.Heading1}, {markHeading2, s.Heading2}, {markHeading3, s.Heading3},
+		{markQuote, s.Quote}, {markCodeBlock, s.CodeBlock},
+	}
+
+	style := lipgloss.NewStyle()
+	for _, o := range order {
+		if m&o.mark != 0 {
+			style = style.Inherit(o.style)
+		}
+	}
+	return style
+}
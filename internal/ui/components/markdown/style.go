@@ -0,0 +1,94 @@
+package markdown
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Heading1  lipgloss.Style
+	Heading2  lipgloss.Style
+	Heading3  lipgloss.Style // Auch für alle tieferen Ebenen
+	Strong    lipgloss.Style
+	Emphasis  lipgloss.Style
+	Strike    lipgloss.Style
+	Code      lipgloss.Style // Code innerhalb einer Zeile
+	CodeBlock lipgloss.Style
+	Link      lipgloss.Style
+	URL       lipgloss.Style
+	Quote     lipgloss.Style
+	Marker    lipgloss.Style // Aufzählungszeichen, Zitatbalken, Sprache von Codeblöcken
+	Border    lipgloss.Style // Linien von Tabellen und Trennlinien
+
+	// Syntax-Hervorhebung in Codeblöcken
+	Keyword lipgloss.Style
+	String  lipgloss.Style
+	Number  lipgloss.Style
+	Comment lipgloss.Style
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Heading1: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Bold(true).
+			Underline(true),
+
+		Heading2: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Bold(true),
+
+		Heading3: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Foreground)).
+			Bold(true),
+
+		Strong: lipgloss.NewStyle().
+			Bold(true),
+
+		Emphasis: lipgloss.NewStyle().
+			Italic(true),
+
+		Strike: lipgloss.NewStyle().
+			Strikethrough(true),
+
+		Code: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)).
+			Background(lipgloss.Color(theme.Selection)),
+
+		CodeBlock: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Foreground)).
+			Background(lipgloss.Color(theme.Selection)),
+
+		Link: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Underline(true),
+
+		URL: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		Quote: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Italic(true),
+
+		Marker: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)),
+
+		Border: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		Keyword: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Removed)),
+
+		String: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Added)),
+
+		Number: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)),
+
+		Comment: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Italic(true),
+	}
+}
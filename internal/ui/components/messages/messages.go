@@ -0,0 +1,215 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultTimeout bestimmt, wie lange eine Meldung in der Statusleiste bleibt
+const DefaultTimeout = 4 * time.Second
+
+// maxEntries begrenzt die Anzahl der Einträge im Meldungsprotokoll
+const maxEntries = 200
+
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "WARNUNG"
+	case LevelError:
+		return "FEHLER"
+	default:
+		return "INFO"
+	}
+}
+
+// Message ist ein Eintrag im Meldungsprotokoll
+type Message struct {
+	ID    int
+	Level Level
+	Text  string
+	Time  time.Time
+}
+
+// Msg wird von Komponenten verschickt, um eine Meldung anzuzeigen
+type Msg struct {
+	Level Level
+	Text  string
+}
+
+// ExpiredMsg signalisiert, dass die Anzeigedauer einer Meldung abgelaufen ist
+type ExpiredMsg struct {
+	ID int
+}
+
+// Info erzeugt ein Kommando für eine Hinweismeldung
+func Info(format string, args ...any) tea.Cmd {
+	return send(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Warn erzeugt ein Kommando für eine Warnung
+func Warn(format string, args ...any) tea.Cmd {
+	return send(LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// Error erzeugt ein Kommando für eine Fehlermeldung
+func Error(err error) tea.Cmd {
+	if err == nil {
+		return nil
+	}
+	return send(LevelError, err.Error())
+}
+
+func send(level Level, text string) tea.Cmd {
+	return func() tea.Msg {
+		return Msg{Level: level, Text: text}
+	}
+}
+
+// Log speichert alle Meldungen und verwaltet die aktuell sichtbare
+type Log struct {
+	entries []Message
+	nextID  int
+	current int // ID der sichtbaren Meldung, 0 = keine
+	timeout time.Duration
+	offset  int
+	width   int
+	height  int
+	style   Style
+}
+
+func NewLog(timeout time.Duration, style Style) Log {
+	return Log{
+		timeout: timeout,
+		style:   style,
+	}
+}
+
+// Add nimmt eine Meldung auf und liefert das Kommando für ihr Ablaufen
+func (l *Log) Add(msg Msg) tea.Cmd {
+	l.nextID++
+	entry := Message{
+		ID:    l.nextID,
+		Level: msg.Level,
+		Text:  msg.Text,
+		Time:  time.Now(),
+	}
+
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > maxEntries {
+		l.entries = l.entries[len(l.entries)-maxEntries:]
+	}
+	l.current = entry.ID
+
+	id := entry.ID
+	return tea.Tick(l.timeout, func(time.Time) tea.Msg {
+		return ExpiredMsg{ID: id}
+	})
+}
+
+// Expire blendet die Meldung aus, sofern sie noch die aktuelle ist
+func (l *Log) Expire(id int) {
+	if l.current == id {
+		l.current = 0
+	}
+}
+
+// Current liefert die aktuell sichtbare Meldung
+func (l *Log) Current() (Message, bool) {
+	if l.current == 0 || len(l.entries) == 0 {
+		return Message{}, false
+	}
+	last := l.entries[len(l.entries)-1]
+	if last.ID != l.current {
+		return Message{}, false
+	}
+	return last, true
+}
+
+// Dismiss blendet die aktuelle Meldung sofort aus
+func (l *Log) Dismiss() {
+	l.current = 0
+}
+
+func (l *Log) SetStyle(style Style) {
+	l.style = style
+}
+
+func (l *Log) Len() int {
+	return len(l.entries)
+}
+
+func (l *Log) Resize(width, height int) {
+	l.width = width
+	l.height = height
+	l.clampOffset()
+}
+
+func (l *Log) ScrollUp(lines int) {
+	l.offset -= lines
+	l.clampOffset()
+}
+
+func (l *Log) ScrollDown(lines int) {
+	l.offset += lines
+	l.clampOffset()
+}
+
+// ScrollToEnd zeigt die neuesten Meldungen an
+func (l *Log) ScrollToEnd() {
+	l.offset = len(l.entries)
+	l.clampOffset()
+}
+
+func (l *Log) visibleRows() int {
+	// Titelzeile und Abstand abziehen
+	rows := l.height - 2
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+func (l *Log) clampOffset() {
+	maxOffset := len(l.entries) - l.visibleRows()
+	if l.offset > maxOffset {
+		l.offset = maxOffset
+	}
+	if l.offset < 0 {
+		l.offset = 0
+	}
+}
+
+func (l Log) Render() string {
+	title := l.style.Title.Render(fmt.Sprintf("Meldungen (%d)", len(l.entries)))
+	if len(l.entries) == 0 {
+		return title + "\n" + l.style.Empty.Render("Keine Meldungen")
+	}
+
+	end := l.offset + l.visibleRows()
+	if end > len(l.entries) {
+		end = len(l.entries)
+	}
+
+	var b strings.Builder
+	b.WriteString(title)
+	b.WriteString("\n")
+	for _, entry := range l.entries[l.offset:end] {
+		b.WriteString(l.style.Time.Render(entry.Time.Format("15:04:05")))
+		b.WriteString(" ")
+		b.WriteString(l.style.ForLevel(entry.Level).Render(fmt.Sprintf("%-8s %s", entry.Level, entry.Text)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
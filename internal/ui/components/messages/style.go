@@ -0,0 +1,55 @@
+package messages
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Title lipgloss.Style
+	Time  lipgloss.Style
+	Info  lipgloss.Style
+	Warn  lipgloss.Style
+	Error lipgloss.Style
+	Empty lipgloss.Style
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Title: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Bold(true).
+			MarginBottom(1),
+
+		Time: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+
+		Info: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Foreground)),
+
+		Warn: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)).
+			Bold(true),
+
+		Error: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Removed)).
+			Bold(true),
+
+		Empty: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)),
+	}
+}
+
+// ForLevel liefert den Style für eine Meldungsstufe
+func (s Style) ForLevel(level Level) lipgloss.Style {
+	switch level {
+	case LevelWarn:
+		return s.Warn
+	case LevelError:
+		return s.Error
+	default:
+		return s.Info
+	}
+}
@@ -22,7 +22,7 @@ func New(height int, contentHeight int, offset int, style Style) Scrollbar {
 
 func (s Scrollbar) Render() string {
 	if s.contentHeight <= s.height {
-		return strings.Repeat(s.style.Track.Render("│"), s.height)
+		return strings.Repeat(s.style.Track.Render(s.style.Symbols.Track)+"\n", s.height)
 	}
 
 	// Scrollbar-Komponenten aus dem Style
@@ -31,12 +31,27 @@ func NewStyleFromConfig(cfg *config.Config) Style {
             Foreground(lipgloss.Color(theme.Selection)).
             Background(lipgloss.Color(theme.Background)),
 
-        Symbols: ScrollbarSymbols{
+        Symbols: symbolsFor(cfg.UI.ScrollStyle),
+    }
+}
+
+// symbolsFor wählt die Zeichen passend zu UI.ScrollStyle ("bar" oder "block")
+func symbolsFor(scrollStyle string) ScrollbarSymbols {
+    if scrollStyle == "block" {
+        return ScrollbarSymbols{
             Single: "█",
-            Top:    "▀",
-            Bottom: "▄",
+            Top:    "█",
+            Bottom: "█",
             Body:   "█",
-            Track:  "│",
-        },
+            Track:  "░",
+        }
+    }
+
+    return ScrollbarSymbols{
+        Single: "█",
+        Top:    "▀",
+        Bottom: "▄",
+        Body:   "█",
+        Track:  "│",
     }
 }
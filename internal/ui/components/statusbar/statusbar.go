@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/mattn/go-runewidth"
 )
 
 type StatusBar struct {
@@ -17,6 +19,20 @@ type StatusBar struct {
 	searchMode    bool
 	searchQuery   string
 	searchResults string
+	message       string
+	messageLevel  messages.Level
+	shortcuts     string
+	context       string
+	compression   string
+	size          int64 // Komprimierte Größe
+	rawSize       int64 // Entpackte Größe
+	encoding      string
+	lineEnding    string
+	folds         int    // Eingeklappte Bereiche
+	mode          string // Modus im mittleren Teil, "" für NORMAL
+	modified      bool   // Ungespeicherte Änderungen
+	progress      string // Fortschritt einer laufenden Arbeit, z. B. "Suche 42%"
+	match         string // Position des aktuellen Suchtreffers
 }
 
 func New(filename string, viewportWidth int, style Style) StatusBar {
@@ -43,6 +59,75 @@ func (s *StatusBar) SetSearchInfo(active bool, query string, current, total int)
 	}
 }
 
+// SetShortcuts setzt die Tastenhinweise im mittleren Teil
+func (s *StatusBar) SetShortcuts(shortcuts string) {
+	s.shortcuts = shortcuts
+}
+
+// SetContext ergänzt den Dateinamen um die Position im Inhalt, z. B. die
+// Datei und den Hunk eines Patches. "" blendet sie aus.
+func (s *StatusBar) SetContext(context string) {
+	s.context = context
+}
+
+// SetCompression zeigt bei komprimierten Dateien das Format und beide
+// Größen anstelle der Dateigröße an. "" blendet sie aus.
+func (s *StatusBar) SetCompression(compression string, size, rawSize int64) {
+	s.compression = compression
+	s.size = size
+	s.rawSize = rawSize
+}
+
+// SetEncoding zeigt die Kodierung der Datei vor der Zeilenposition an,
+// "" blendet sie aus
+func (s *StatusBar) SetEncoding(encoding string, bom bool) {
+	s.encoding = encoding
+	if bom {
+		s.encoding += " BOM"
+	}
+}
+
+// SetLineEnding zeigt die Zeilenenden der Datei neben der Kodierung an,
+// "" blendet sie aus
+func (s *StatusBar) SetLineEnding(lineEnding string) {
+	s.lineEnding = lineEnding
+}
+
+// SetFolds zeigt die Anzahl eingeklappter Bereiche vor der Zeilenposition
+// an, 0 blendet sie aus
+func (s *StatusBar) SetFolds(folds int) {
+	s.folds = folds
+}
+
+// SetProgress zeigt den Fortschritt einer laufenden Arbeit vor der
+// Zeilenposition an, "" blendet ihn aus
+func (s *StatusBar) SetProgress(progress string) {
+	s.progress = progress
+}
+
+// SetMatch zeigt die Position des aktuellen Suchtreffers anstelle der
+// Zeilenposition an, "" blendet sie aus
+func (s *StatusBar) SetMatch(match string) {
+	s.match = match
+}
+
+// SetMode setzt den Modus im mittleren Teil, "" steht für NORMAL
+func (s *StatusBar) SetMode(mode string) {
+	s.mode = mode
+}
+
+// SetModified markiert den Dateinamen mit [+], solange es ungespeicherte
+// Änderungen gibt
+func (s *StatusBar) SetModified(modified bool) {
+	s.modified = modified
+}
+
+// SetMessage zeigt eine Meldung anstelle der Shortcuts an, "" blendet sie aus
+func (s *StatusBar) SetMessage(level messages.Level, text string) {
+	s.messageLevel = level
+	s.message = text
+}
+
 func (s StatusBar) Render() string {
 	if s.searchMode {
 		searchPrompt := fmt.Sprintf("/%s", s.searchQuery)
@@ -59,14 +144,36 @@ func (s StatusBar) Render() string {
 	}
 
 	// Linke Seite
+	fileName := s.fileName
+	if s.modified {
+		fileName += " [+]"
+	}
 	leftStatus := fmt.Sprintf(
 		"%s - %s",
-		s.fileName,
+		fileName,
 		s.formatFileSize(),
 	)
+	if s.compression != "" {
+		leftStatus = fmt.Sprintf(
+			"%s - %s %s → %s",
+			fileName,
+			s.compression,
+			formatSize(s.size),
+			formatSize(s.rawSize),
+		)
+	}
+	if s.context != "" {
+		leftStatus += " | " + s.context
+	}
 
 	// Mittlerer Teil (Shortcuts)
-	middleStatus := "NORMAL | ^F: Suche | ^S: Speichern | q: Beenden"
+	middleStatus := "NORMAL"
+	if s.mode != "" {
+		middleStatus = s.mode
+	}
+	if s.shortcuts != "" {
+		middleStatus += " | " + s.shortcuts
+	}
 
 	// Rechte Seite
 	rightStatus := fmt.Sprintf(
@@ -75,12 +182,31 @@ func (s StatusBar) Render() string {
 		s.totalLines,
 		percentage,
 	)
+	if s.match != "" {
+		rightStatus = s.match
+	}
+	if s.folds > 0 {
+		rightStatus = fmt.Sprintf("%d eingeklappt | %s", s.folds, rightStatus)
+	}
+	if s.progress != "" {
+		rightStatus = s.progress + " | " + rightStatus
+	}
+	if format := strings.TrimSpace(s.encoding + " " + s.lineEnding); format != "" {
+		rightStatus = format + " | " + rightStatus
+	}
 
 	// Layout berechnen
 	leftWidth := lipgloss.Width(leftStatus)
 	rightWidth := lipgloss.Width(rightStatus)
 	middleWidth := s.viewportWidth - leftWidth - rightWidth - 2
 
+	// Meldungen haben Vorrang vor den Shortcuts
+	middleStyle := s.style.MiddleSection
+	if s.message != "" {
+		middleStatus = s.message
+		middleStyle = s.style.messageStyle(s.messageLevel)
+	}
+
 	// Zentrieren des mittleren Teils
 	middleStatus = s.centerText(middleStatus, middleWidth)
 
@@ -88,14 +214,18 @@ func (s StatusBar) Render() string {
 		lipgloss.JoinHorizontal(
 			lipgloss.Left,
 			s.style.LeftSection.Render(leftStatus),
-			s.style.MiddleSection.Render(middleStatus),
+			middleStyle.Render(middleStatus),
 			s.style.RightSection.Render(rightStatus),
 		),
 	)
 }
 
 func (s StatusBar) formatFileSize() string {
-	size := float64(s.fileSize)
+	return formatSize(int64(s.fileSize))
+}
+
+func formatSize(n int64) string {
+	size := float64(n)
 	switch {
 	case size < 1024:
 		return fmt.Sprintf("%d B", int(size))
@@ -112,7 +242,7 @@ func (s StatusBar) centerText(text string, width int) string {
 	}
 	textWidth := lipgloss.Width(text)
 	if textWidth >= width {
-		return text[:width]
+		return runewidth.Truncate(text, width, "…")
 	}
 	leftPad := (width - textWidth) / 2
 	rightPad := width - textWidth - leftPad
@@ -3,6 +3,7 @@ package statusbar
 import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/ui/components/messages"
 )
 
 type Style struct {
@@ -11,6 +12,7 @@ type Style struct {
 	MiddleSection lipgloss.Style
 	RightSection  lipgloss.Style
 	SearchMode    lipgloss.Style
+	Messages      messages.Style
 }
 
 func NewStyleFromConfig(cfg *config.Config) Style {
@@ -35,5 +37,12 @@ func NewStyleFromConfig(cfg *config.Config) Style {
 							Foreground(lipgloss.Color(theme.Accent)).
 							Background(lipgloss.Color(theme.Selection)).
 							Bold(true),
+		Messages: messages.NewStyleFromConfig(cfg),
 	}
 }
+
+// messageStyle liefert den Style für eine Meldung in der Statusleiste
+func (s Style) messageStyle(level messages.Level) lipgloss.Style {
+	return s.Messages.ForLevel(level).
+		Background(s.Base.GetBackground())
+}
@@ -0,0 +1,37 @@
+package tabbar
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Base     lipgloss.Style
+	Active   lipgloss.Style
+	Inactive lipgloss.Style
+	Overflow lipgloss.Style
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Base: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.Background)),
+
+		Active: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Accent)).
+			Bold(true).
+			Padding(0, 1),
+
+		Inactive: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Foreground)).
+			Background(lipgloss.Color(theme.Selection)).
+			Padding(0, 1),
+
+		Overflow: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Background(lipgloss.Color(theme.Background)),
+	}
+}
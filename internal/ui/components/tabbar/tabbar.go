@@ -0,0 +1,96 @@
+package tabbar
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Tab ist ein Eintrag der Tableiste
+type Tab struct {
+	Title    string
+	Modified bool
+}
+
+type TabBar struct {
+	tabs   []Tab
+	active int
+	width  int
+	style  Style
+}
+
+func New(width int, style Style) TabBar {
+	return TabBar{
+		width: width,
+		style: style,
+	}
+}
+
+// SetTabs setzt die Einträge und den aktiven Tab
+func (t *TabBar) SetTabs(tabs []Tab, active int) {
+	t.tabs = tabs
+	t.active = active
+}
+
+func (t *TabBar) SetWidth(width int) {
+	t.width = width
+}
+
+func (t *TabBar) SetStyle(style Style) {
+	t.style = style
+}
+
+func (t TabBar) Render() string {
+	if len(t.tabs) == 0 {
+		return ""
+	}
+
+	rendered := make([]string, len(t.tabs))
+	for i, tab := range t.tabs {
+		label := fmt.Sprintf("%d:%s", i+1, tab.Title)
+		if tab.Modified {
+			label += " +"
+		}
+		if i == t.active {
+			rendered[i] = t.style.Active.Render(label)
+		} else {
+			rendered[i] = t.style.Inactive.Render(label)
+		}
+	}
+
+	// Sichtbaren Ausschnitt so wählen, dass der aktive Tab enthalten ist
+	start, end := t.active, t.active+1
+	used := lipgloss.Width(rendered[t.active])
+	for {
+		grown := false
+		if end < len(rendered) && used+lipgloss.Width(rendered[end])+1 <= t.width-2 {
+			used += lipgloss.Width(rendered[end]) + 1
+			end++
+			grown = true
+		}
+		if start > 0 && used+lipgloss.Width(rendered[start-1])+1 <= t.width-2 {
+			start--
+			used += lipgloss.Width(rendered[start]) + 1
+			grown = true
+		}
+		if !grown {
+			break
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(t.style.Overflow.Render("<"))
+	}
+	b.WriteString(strings.Join(rendered[start:end], t.style.Base.Render(" ")))
+	if end < len(rendered) {
+		b.WriteString(t.style.Overflow.Render(">"))
+	}
+
+	line := b.String()
+	if pad := t.width - lipgloss.Width(line); pad > 0 {
+		line += t.style.Base.Render(strings.Repeat(" ", pad))
+	}
+	return line
+}
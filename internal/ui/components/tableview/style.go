@@ -0,0 +1,28 @@
+package tableview
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+)
+
+type Style struct {
+	Current       lipgloss.Style // Zellen der aktuellen Spalte
+	CurrentHeader lipgloss.Style // Kopf der aktuellen Spalte
+	Separator     lipgloss.Style // │ zwischen den Spalten
+}
+
+func NewStyleFromConfig(cfg *config.Config) Style {
+	theme := cfg.Theme
+
+	return Style{
+		Current: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)),
+
+		CurrentHeader: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Accent)),
+
+		Separator: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Selection)),
+	}
+}
@@ -0,0 +1,448 @@
+package tableview
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/ui/components/textview"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// maxColumnWidth begrenzt die Breite einer Spalte, längere Werte werden
+	// mit "…" gekürzt
+	maxColumnWidth = 32
+	separator      = " │ "
+)
+
+// View zeigt CSV- und TSV-Dateien als Tabelle. Die erste Zeile ist der
+// Kopf, er wird über textview.TextView.SetHeader fest angezeigt. Der Inhalt
+// beginnt bei der ersten sichtbaren Spalte, so lässt sich spaltenweise
+// waagerecht blättern. Als textview.Decorator hebt View die aktuelle Spalte
+// hervor.
+type View struct {
+	header  []string
+	records [][]string
+	lines   []int // Quellzeile (1-basiert), in der ein Datensatz beginnt
+	widths  []int // Breite je Spalte, begrenzt auf maxColumnWidth
+
+	order   []int // Angezeigte Spalten in Anzeigereihenfolge
+	rows    []int // Datensätze in Anzeigereihenfolge
+	sortBy  int   // Sortierspalte, -1 für die Reihenfolge der Datei
+	desc    bool
+	current int // Index in order
+	first   int // Erste sichtbare Spalte, Index in order
+	width   int // Verfügbare Breite, 0 = unbegrenzt
+
+	texts []string
+	spans [][2]int // Byte-Bereich der aktuellen Spalte je Zeile
+	style Style
+}
+
+// Delimiter errät das Trennzeichen an der ersten Zeile
+func Delimiter(content string) rune {
+	first, _, _ := strings.Cut(content, "\n")
+	best, count := ',', 0
+	for _, r := range []rune{',', '\t', ';', '|'} {
+		if n := strings.Count(first, string(r)); n > count {
+			best, count = r, n
+		}
+	}
+	return best
+}
+
+// Parse liest eine Tabelle. Felder in Anführungszeichen dürfen Trennzeichen
+// und Zeilenumbrüche enthalten.
+func Parse(content string, comma rune, style Style) (*View, error) {
+	r := csv.NewReader(strings.NewReader(content))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	v := &View{sortBy: -1, style: style}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		if v.header == nil {
+			v.header = record
+			continue
+		}
+		v.records = append(v.records, record)
+		v.lines = append(v.lines, line)
+	}
+	if v.header == nil {
+		return nil, fmt.Errorf("Keine Tabelle gefunden")
+	}
+
+	columns := len(v.header)
+	for _, record := range v.records {
+		columns = max(columns, len(record))
+	}
+	for len(v.header) < columns {
+		v.header = append(v.header, strconv.Itoa(len(v.header)+1))
+	}
+
+	v.widths = make([]int, columns)
+	for c, name := range v.header {
+		v.widths[c] = cellWidth(name)
+	}
+	for _, record := range v.records {
+		for c, cell := range record {
+			v.widths[c] = max(v.widths[c], cellWidth(cell))
+		}
+	}
+
+	v.ShowAll()
+	return v, nil
+}
+
+// cellWidth liefert die begrenzte Anzeigebreite eines Werts
+func cellWidth(cell string) int {
+	return min(runewidth.StringWidth(singleLine(cell)), maxColumnWidth)
+}
+
+// ShowAll zeigt alle Spalten in der Reihenfolge der Datei
+func (v *View) ShowAll() {
+	v.order = v.order[:0]
+	for c := range v.header {
+		v.order = append(v.order, c)
+	}
+	v.current, v.first = 0, 0
+	v.sort()
+}
+
+// Columns liefert die Namen aller Spalten der Datei
+func (v *View) Columns() []string {
+	return v.header
+}
+
+// Shown liefert die Namen der angezeigten Spalten
+func (v *View) Shown() []string {
+	names := make([]string, len(v.order))
+	for i, c := range v.order {
+		names[i] = v.header[c]
+	}
+	return names
+}
+
+// column sucht eine Spalte über ihren Namen oder ihre Nummer (1-basiert)
+func (v *View) column(name string) (int, error) {
+	for c, h := range v.header {
+		if h == name {
+			return c, nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= 1 && n <= len(v.header) {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("Unbekannte Spalte: %s", name)
+}
+
+// SetColumns zeigt nur die genannten Spalten in dieser Reihenfolge
+func (v *View) SetColumns(names []string) error {
+	order := make([]int, 0, len(names))
+	for _, name := range names {
+		c, err := v.column(name)
+		if err != nil {
+			return err
+		}
+		order = append(order, c)
+	}
+	v.order = order
+	v.current, v.first = 0, 0
+	v.render()
+	return nil
+}
+
+// Hide blendet die aktuelle Spalte aus. Die letzte Spalte bleibt stehen.
+func (v *View) Hide() error {
+	if len(v.order) <= 1 {
+		return fmt.Errorf("Die letzte Spalte kann nicht ausgeblendet werden")
+	}
+	v.order = append(v.order[:v.current], v.order[v.current+1:]...)
+	v.current = min(v.current, len(v.order)-1)
+	v.first = min(v.first, v.current)
+	v.render()
+	return nil
+}
+
+// Move verschiebt die aktuelle Spalte um delta Stellen nach rechts
+// (negativ nach links)
+func (v *View) Move(delta int) {
+	target := min(max(v.current+delta, 0), len(v.order)-1)
+	for v.current != target {
+		step := 1
+		if target < v.current {
+			step = -1
+		}
+		v.order[v.current], v.order[v.current+step] = v.order[v.current+step], v.order[v.current]
+		v.current += step
+	}
+	v.scrollToCurrent()
+	v.render()
+}
+
+// Step wählt die Spalte delta Stellen rechts (negativ links) als aktuelle
+func (v *View) Step(delta int) {
+	v.current = min(max(v.current+delta, 0), len(v.order)-1)
+	v.scrollToCurrent()
+	v.render()
+}
+
+// SetWidth setzt die verfügbare Breite für das waagerechte Blättern
+func (v *View) SetWidth(width int) {
+	if width != v.width {
+		v.width = width
+		v.scrollToCurrent()
+		v.render()
+	}
+}
+
+// scrollToCurrent wählt die erste sichtbare Spalte so, dass die aktuelle
+// vollständig in die Breite passt
+func (v *View) scrollToCurrent() {
+	if v.current < v.first {
+		v.first = v.current
+	}
+	if v.width <= 0 {
+		return
+	}
+	for v.first < v.current && v.span(v.first, v.current) > v.width {
+		v.first++
+	}
+}
+
+// span liefert die Breite der Spalten from bis to (Indizes in order)
+func (v *View) span(from, to int) int {
+	width := 0
+	for i := from; i <= to; i++ {
+		width += v.columnWidth(v.order[i])
+		if i > from {
+			width += runewidth.StringWidth(separator)
+		}
+	}
+	return width
+}
+
+// columnWidth berücksichtigt die Sortiermarke im Kopf
+func (v *View) columnWidth(c int) int {
+	if c == v.sortBy {
+		return max(v.widths[c], cellWidth(v.header[c])+2)
+	}
+	return v.widths[c]
+}
+
+// Current liefert Nummer (1-basiert) und Namen der aktuellen Spalte sowie
+// die Anzahl der angezeigten Spalten
+func (v *View) Current() (index int, name string, count int) {
+	return v.current + 1, v.header[v.order[v.current]], len(v.order)
+}
+
+// Sort sortiert nach der aktuellen Spalte. Erneutes Sortieren nach derselben
+// Spalte kehrt die Richtung um.
+func (v *View) Sort() {
+	c := v.order[v.current]
+	if c == v.sortBy {
+		v.desc = !v.desc
+	} else {
+		v.sortBy, v.desc = c, false
+	}
+	v.scrollToCurrent()
+	v.sort()
+}
+
+// SortBy sortiert nach einer Spalte, leer stellt die Reihenfolge der Datei
+// wieder her
+func (v *View) SortBy(name string, desc bool) error {
+	if name == "" {
+		v.sortBy, v.desc = -1, false
+		v.sort()
+		return nil
+	}
+	c, err := v.column(name)
+	if err != nil {
+		return err
+	}
+	v.sortBy, v.desc = c, desc
+	v.sort()
+	return nil
+}
+
+// Sorting liefert den Namen der Sortierspalte, leer ohne Sortierung
+func (v *View) Sorting() (name string, desc bool) {
+	if v.sortBy < 0 {
+		return "", false
+	}
+	return v.header[v.sortBy], v.desc
+}
+
+// sort ordnet die Datensätze, Zahlen werden als Zahlen verglichen
+func (v *View) sort() {
+	v.rows = v.rows[:0]
+	for i := range v.records {
+		v.rows = append(v.rows, i)
+	}
+	if v.sortBy >= 0 {
+		c := v.sortBy
+		sort.SliceStable(v.rows, func(i, j int) bool {
+			a, b := cell(v.records[v.rows[i]], c), cell(v.records[v.rows[j]], c)
+			if v.desc {
+				return less(b, a)
+			}
+			return less(a, b)
+		})
+	}
+	v.render()
+}
+
+func less(a, b string) bool {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil:
+		return true // Zahlen vor Text
+	case errB == nil:
+		return false
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+func cell(record []string, c int) string {
+	if c < len(record) {
+		return record[c]
+	}
+	return ""
+}
+
+// Line liefert die Quellzeile (1-basiert), in der eine angezeigte Zeile
+// (1-basiert) beginnt
+func (v *View) Line(row int) int {
+	if row < 1 || row > len(v.rows) {
+		return 0
+	}
+	return v.lines[v.rows[row-1]]
+}
+
+// Rows liefert die Anzahl der Datensätze ohne Kopf
+func (v *View) Rows() int {
+	return len(v.records)
+}
+
+// Content liefert die Zeilen ab der ersten sichtbaren Spalte
+func (v *View) Content() string {
+	return strings.Join(v.texts, "\n")
+}
+
+// Header liefert die Kopfzeile passend zum Inhalt und den Bereich der
+// aktuellen Spalte
+func (v *View) Header() (string, []textview.Span) {
+	var b strings.Builder
+	var spans []textview.Span
+	for i := v.first; i < len(v.order); i++ {
+		c := v.order[i]
+		if i > v.first {
+			spans = append(spans, textview.Span{Start: b.Len(), End: b.Len() + len(separator), Style: v.style.Separator})
+			b.WriteString(separator)
+		}
+		name := singleLine(v.header[c])
+		if c == v.sortBy {
+			marker := " ▲"
+			if v.desc {
+				marker = " ▼"
+			}
+			name += marker
+		}
+		start := b.Len()
+		b.WriteString(pad(name, v.columnWidth(c), i == len(v.order)-1))
+		if i == v.current {
+			spans = append(spans, textview.Span{Start: start, End: b.Len(), Style: v.style.CurrentHeader})
+		}
+	}
+	return b.String(), spans
+}
+
+// render baut die Zeilen ab der ersten sichtbaren Spalte neu auf
+func (v *View) render() {
+	v.texts = make([]string, len(v.rows))
+	v.spans = make([][2]int, len(v.rows))
+	for r, index := range v.rows {
+		record := v.records[index]
+		var b strings.Builder
+		for i := v.first; i < len(v.order); i++ {
+			c := v.order[i]
+			if i > v.first {
+				b.WriteString(separator)
+			}
+			start := b.Len()
+			b.WriteString(pad(singleLine(cell(record, c)), v.columnWidth(c), i == len(v.order)-1))
+			if i == v.current {
+				v.spans[r] = [2]int{start, b.Len()}
+			}
+		}
+		v.texts[r] = strings.TrimRight(b.String(), " ")
+	}
+}
+
+// pad kürzt einen Wert auf die Spaltenbreite und füllt ihn auf. Die letzte
+// Spalte wird nicht aufgefüllt.
+func pad(s string, width int, last bool) string {
+	s = runewidth.Truncate(s, width, "…")
+	if last {
+		return s
+	}
+	return runewidth.FillRight(s, width)
+}
+
+// SetStyle tauscht den Style aus, z. B. nach einem Themewechsel
+func (v *View) SetStyle(style Style) {
+	v.style = style
+}
+
+// GutterWidth erfüllt textview.Decorator, es gibt keine Randspalte
+func (v *View) GutterWidth() int {
+	return 0
+}
+
+func (v *View) Gutter(int) string {
+	return ""
+}
+
+// Decorate erfüllt textview.Decorator und hebt die aktuelle Spalte und die
+// Trenner hervor
+func (v *View) Decorate(line int) (lipgloss.Style, []textview.Span, bool) {
+	if line < 0 || line >= len(v.texts) {
+		return lipgloss.Style{}, nil, false
+	}
+
+	text := v.texts[line]
+	var spans []textview.Span
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], separator)
+		if i < 0 {
+			break
+		}
+		spans = append(spans, textview.Span{Start: offset + i, End: offset + i + len(separator), Style: v.style.Separator})
+		offset += i + len(separator)
+	}
+	current := v.spans[line]
+	spans = append(spans, textview.Span{Start: current[0], End: current[1], Style: v.style.Current})
+	return lipgloss.NewStyle(), spans, true
+}
+
+// singleLine zeigt mehrzeilige Felder in einer Zeile
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", "↵", "\n", "↵", "\t", " ").Replace(s)
+}
@@ -0,0 +1,94 @@
+package tableview
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDelimiter(t *testing.T) {
+	tests := []struct {
+		content string
+		want    rune
+	}{
+		{"a,b,c\n1,2,3", ','},
+		{"a\tb\tc\n1\t2\t3", '\t'},
+		{"a;b;c\n1,2;3", ';'},
+		{"a|b\n1|2", '|'},
+		{"a,b;c;d\n1,2,3,4,5", ';'}, // Nur die erste Zeile zählt
+		{"spalte\n1", ','},
+		{"", ','},
+	}
+	for _, tt := range tests {
+		if got := Delimiter(tt.content); got != tt.want {
+			t.Errorf("Delimiter(%q) = %q, erwartet %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	content := strings.Join([]string{
+		"name,notiz,wert",
+		`a,"zwei`,
+		`Zeilen",1`,
+		"b,kurz",
+		`c,"x, y",3,extra`,
+		`d,"drei`,
+		``,
+		`Zeilen",2`,
+		`e,,4`,
+	}, "\n")
+	v, err := Parse(content, ',', Style{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Die vierte Spalte gibt es nur in einer Zeile, sie wird nach ihrer
+	// Nummer benannt
+	if got := strings.Join(v.Columns(), ","); got != "name,notiz,wert,4" {
+		t.Errorf("Spalten %s", got)
+	}
+	if v.Rows() != 5 {
+		t.Errorf("%d Datensätze, erwartet 5", v.Rows())
+	}
+
+	// Datensätze beginnen in der Zeile ihres ersten Felds, auch nach
+	// mehrzeiligen Feldern
+	for row, want := range []int{2, 4, 5, 6, 9} {
+		if got := v.Line(row + 1); got != want {
+			t.Errorf("Zeile %d beginnt in Quellzeile %d, erwartet %d", row+1, got, want)
+		}
+	}
+
+	// Zeilenumbrüche in Feldern werden als ↵ in einer Zeile angezeigt,
+	// fehlende Felder bleiben leer
+	want := strings.Join([]string{
+		"a    │ zwei↵Zeilen  │ 1    │",
+		"b    │ kurz         │      │",
+		"c    │ x, y         │ 3    │ extra",
+		"d    │ drei↵↵Zeilen │ 2    │",
+		"e    │              │ 4    │",
+	}, "\n")
+	if got := v.Content(); got != want {
+		t.Errorf("Inhalt\n%s\nerwartet\n%s", got, want)
+	}
+	if header, _ := v.Header(); header != "name │ notiz        │ wert │ 4" {
+		t.Errorf("Kopf %q", header)
+	}
+
+	// Sortiert folgen die Quellzeilen den Datensätzen, das fehlende Feld
+	// steht als Text hinter den Zahlen
+	if err := v.SortBy("wert", false); err != nil {
+		t.Fatal(err)
+	}
+	for row, want := range []int{2, 6, 5, 9, 4} {
+		if got := v.Line(row + 1); got != want {
+			t.Errorf("Sortiert: Zeile %d beginnt in Quellzeile %d, erwartet %d", row+1, got, want)
+		}
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	if _, err := Parse("", ',', Style{}); err == nil {
+		t.Error("Leerer Inhalt ergibt eine Tabelle")
+	}
+}
@@ -0,0 +1,72 @@
+package textview
+
+import "unicode/utf8"
+
+// cursor ist die Schreibmarke beim Bearbeiten: Quellzeile (0-basiert) und
+// Spalte in Zeichen
+type cursor struct {
+	line int
+	col  int
+	on   bool
+}
+
+// SetCursor zeigt eine Schreibmarke in einer Quellzeile (1-basiert) vor dem
+// Zeichen col (0-basiert) und macht die Stelle sichtbar. Eingeklappte
+// Bereiche um die Stelle werden aufgeklappt.
+func (tv *TextView) SetCursor(line, col int) {
+	tv.cursor = cursor{line: line - 1, col: col, on: true}
+	if tv.revealLine(line - 1) {
+		tv.refresh()
+	}
+	tv.currentLine = tv.cursorRow()
+	if tv.currentLine < len(tv.display) {
+		dl := tv.display[tv.currentLine]
+		off := tv.cursorOffset() - dl.start
+		tv.showColumns(dl, off, off+1)
+	}
+	if tv.currentLine < tv.viewport.YOffset {
+		tv.setOffset(tv.currentLine)
+	}
+	if bottom := tv.viewport.YOffset + tv.viewport.Height; tv.currentLine >= bottom {
+		tv.setOffset(tv.currentLine - tv.viewport.Height + 1)
+	}
+	tv.clampOffset()
+}
+
+// ClearCursor blendet die Schreibmarke aus
+func (tv *TextView) ClearCursor() {
+	tv.cursor = cursor{}
+}
+
+// cursorOffset liefert die Position der Schreibmarke als Byte-Offset in der
+// Zeile mit expandierten Tabs
+func (tv *TextView) cursorOffset() int {
+	if tv.cursor.line < 0 || tv.cursor.line >= tv.text.LineCount() {
+		return 0
+	}
+	src := tv.text.Line(tv.cursor.line)
+	runes := []rune(src)
+	col := min(max(tv.cursor.col, 0), len(runes))
+	return tv.expandedOffset(src, len(string(runes[:col])))
+}
+
+// cursorRow liefert die angezeigte Zeile mit der Schreibmarke, bei
+// umbrochenen Zeilen den Abschnitt, in dem sie steht
+func (tv *TextView) cursorRow() int {
+	row := tv.rowAt(tv.cursor.line, tv.cursorOffset())
+	if row < 0 {
+		return tv.displayIndex(tv.cursor.line + 1)
+	}
+	return row
+}
+
+// cursorSpan liefert die Hervorhebung der Schreibmarke an einem Byte-Offset
+// in einem angezeigten Abschnitt. Am Zeilenende steht sie auf dem Leerraum
+// dahinter.
+func (tv *TextView) cursorSpan(text string, pos int) (Span, bool) {
+	if pos < 0 || pos >= len(text) {
+		return Span{}, false
+	}
+	_, size := utf8.DecodeRuneInString(text[pos:])
+	return Span{Start: pos, End: pos + size, Style: tv.style.Cursor}, true
+}
@@ -0,0 +1,177 @@
+package textview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Span hebt einen Byte-Bereich einer Quellzeile hervor
+type Span struct {
+	Start int
+	End   int
+	Style lipgloss.Style
+}
+
+// Decorator ergänzt Quellzeilen um eine Randspalte und Hervorhebungen,
+// z. B. die +/- Spalte und Farben einer Diff-Ansicht
+type Decorator interface {
+	// GutterWidth ist die Breite der Randspalte, 0 für keine
+	GutterWidth() int
+	// Gutter liefert die (ggf. gestylte) Randspalte einer Quellzeile (0-basiert)
+	Gutter(line int) string
+	// Decorate liefert Grundstil und Hervorhebungen einer Quellzeile.
+	// ok ist false, wenn die Zeile normal dargestellt wird.
+	Decorate(line int) (base lipgloss.Style, spans []Span, ok bool)
+}
+
+type namedDecorator struct {
+	name string
+	dec  Decorator
+}
+
+// SetDecorator setzt oder ersetzt einen Decorator unter seinem Namen.
+// nil entfernt ihn wieder. Die Anzeige wird nur neu aufgebaut, wenn sich
+// mit der Randspalte die Breite für umbrochenen Text ändert.
+func (tv *TextView) SetDecorator(name string, dec Decorator) {
+	tv.cache = nil // Auch derselbe Decorator kann anders aussehen
+	for i, nd := range tv.decorators {
+		if nd.name != name {
+			continue
+		}
+		if dec == nil {
+			tv.decorators = append(tv.decorators[:i], tv.decorators[i+1:]...)
+		} else {
+			tv.decorators[i].dec = dec
+		}
+		tv.relayout()
+		return
+	}
+	if dec != nil {
+		tv.decorators = append(tv.decorators, namedDecorator{name: name, dec: dec})
+		tv.relayout()
+	}
+}
+
+// gutterWidth liefert die Gesamtbreite aller Randspalten
+func (tv *TextView) gutterWidth() int {
+	width := 0
+	for _, nd := range tv.decorators {
+		width += nd.dec.GutterWidth()
+	}
+	return width
+}
+
+// renderGutter setzt die Randspalten für eine angezeigte Zeile zusammen.
+// Fortsetzungszeilen bekommen eine leere Randspalte.
+func (tv *TextView) renderGutter(dl displayLine, present bool) string {
+	var b strings.Builder
+	for _, nd := range tv.decorators {
+		width := nd.dec.GutterWidth()
+		if width == 0 {
+			continue
+		}
+		gutter := ""
+		if present && !dl.cont {
+			gutter = nd.dec.Gutter(dl.src)
+		}
+		b.WriteString(fitWidth(gutter, width))
+	}
+	return b.String()
+}
+
+// decorate sammelt Grundstil und Hervorhebungen aller Decorators.
+// Der Grundstil des zuletzt gesetzten Decorators gewinnt.
+func (tv *TextView) decorate(line int) (lipgloss.Style, []Span, bool) {
+	var (
+		base  lipgloss.Style
+		spans []Span
+		found bool
+	)
+	for _, nd := range tv.decorators {
+		s, sp, ok := nd.dec.Decorate(line)
+		if !ok {
+			continue
+		}
+		base = s
+		spans = append(spans, sp...)
+		found = true
+	}
+	return base, spans, found
+}
+
+// expandedOffset rechnet einen Byte-Offset der Quellzeile in einen Offset
+// der Zeile mit expandierten Tabs um
+func (tv *TextView) expandedOffset(line string, offset int) int {
+	if !strings.Contains(line, "\t") {
+		return offset
+	}
+	return len(tv.expandTabs(line[:min(offset, len(line))]))
+}
+
+// applySpans rendert Text mit Grundstil und Hervorhebungen. Spätere Spans
+// überdecken frühere.
+func applySpans(text string, base lipgloss.Style, hasBase bool, spans []Span) string {
+	if len(spans) == 0 {
+		if hasBase {
+			return base.Render(text)
+		}
+		return text
+	}
+
+	owner := make([]int, len(text))
+	for i := range owner {
+		owner[i] = -1
+	}
+	for i, sp := range spans {
+		for j := max(sp.Start, 0); j < min(sp.End, len(text)); j++ {
+			owner[j] = i
+		}
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(text); {
+		end := start + 1
+		for end < len(text) && owner[end] == owner[start] {
+			end++
+		}
+		part := text[start:end]
+		switch {
+		case owner[start] >= 0:
+			style := spans[owner[start]].Style
+			if hasBase {
+				style = style.Inherit(base)
+			}
+			b.WriteString(style.Render(part))
+		case hasBase:
+			b.WriteString(base.Render(part))
+		default:
+			b.WriteString(part)
+		}
+		start = end
+	}
+	return b.String()
+}
+
+// clipSpans verschiebt Spans in den Bereich eines Zeilenabschnitts
+func clipSpans(spans []Span, from, to int) []Span {
+	var clipped []Span
+	for _, sp := range spans {
+		start, end := max(sp.Start, from), min(sp.End, to)
+		if start >= end {
+			continue
+		}
+		clipped = append(clipped, Span{Start: start - from, End: end - from, Style: sp.Style})
+	}
+	return clipped
+}
+
+// fitWidth kürzt oder füllt einen (ggf. gestylten) Text auf die Breite
+func fitWidth(s string, width int) string {
+	w := lipgloss.Width(s)
+	if w > width {
+		return ansi.Truncate(s, width, "")
+	}
+	return s + strings.Repeat(" ", width-w)
+}
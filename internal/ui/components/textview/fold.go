@@ -0,0 +1,307 @@
+package textview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// fold ist ein klappbarer Bereich aus Quellzeilen (0-basiert). Die Kopfzeile
+// start bleibt sichtbar, eingeklappt werden die Zeilen start+1 bis end.
+type fold struct {
+	start int
+	end   int
+}
+
+// findFolds ermittelt die klappbaren Bereiche eines Textes, sortiert nach
+// Kopfzeile. Klammerpaare über mehrere Zeilen gehen vor Bereichen aus der
+// Einrückung, die in derselben Zeile beginnen.
+func findFolds(lines []string, tabWidth int) []fold {
+	ends := make(map[int]int)
+	for _, f := range indentFolds(lines, tabWidth) {
+		ends[f.start] = f.end
+	}
+	for _, f := range bracketFolds(lines) {
+		ends[f.start] = f.end
+	}
+
+	folds := make([]fold, 0, len(ends))
+	for start, end := range ends {
+		folds = append(folds, fold{start: start, end: end})
+	}
+	sort.Slice(folds, func(i, j int) bool { return folds[i].start < folds[j].start })
+	return folds
+}
+
+// indentFolds bildet Bereiche aus Zeilen, die tiefer eingerückt sind als
+// die Zeile davor. Leerzeilen gehören dazu, solange der Bereich weitergeht.
+func indentFolds(lines []string, tabWidth int) []fold {
+	indents := make([]int, len(lines))
+	for i, line := range lines {
+		indents[i] = indentWidth(line, tabWidth)
+	}
+
+	var folds []fold
+	for i := range lines {
+		if indents[i] < 0 {
+			continue
+		}
+		end := i
+		for j := i + 1; j < len(lines); j++ {
+			if indents[j] < 0 {
+				continue
+			}
+			if indents[j] <= indents[i] {
+				break
+			}
+			end = j
+		}
+		if end > i {
+			folds = append(folds, fold{start: i, end: end})
+		}
+	}
+	return folds
+}
+
+// indentWidth liefert die Einrückung einer Zeile in Spalten, -1 für Leerzeilen
+func indentWidth(line string, tabWidth int) int {
+	if tabWidth <= 0 {
+		tabWidth = 4
+	}
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += tabWidth - width%tabWidth
+		default:
+			return width
+		}
+	}
+	return -1
+}
+
+// bracketFolds bildet Bereiche aus Klammerpaaren ({}, [], ()), die über
+// mehrere Zeilen gehen. Klammern in Zeichenketten zählen nicht.
+func bracketFolds(lines []string) []fold {
+	type open struct {
+		char rune
+		line int
+	}
+	var (
+		stack []open
+		folds []fold
+	)
+	for i, line := range lines {
+		var quote rune
+		escaped := false
+		for _, r := range line {
+			switch {
+			case escaped:
+				escaped = false
+			case quote != 0:
+				if r == '\\' && quote != '`' {
+					escaped = true
+				} else if r == quote {
+					quote = 0
+				}
+			case r == '"' || r == '\'' || r == '`':
+				quote = r
+			case r == '{' || r == '[' || r == '(':
+				stack = append(stack, open{char: r, line: i})
+			case r == '}' || r == ']' || r == ')':
+				// Unpassende schließende Klammern werden übergangen
+				if len(stack) == 0 || stack[len(stack)-1].char != opening(r) {
+					continue
+				}
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if i > top.line {
+					folds = append(folds, fold{start: top.line, end: i})
+				}
+			}
+		}
+	}
+	return folds
+}
+
+// opening liefert die öffnende Klammer zu einer schließenden
+func opening(r rune) rune {
+	switch r {
+	case '}':
+		return '{'
+	case ']':
+		return '['
+	}
+	return '('
+}
+
+// ensureFolds ermittelt die Bereiche beim ersten Bedarf
+func (tv *TextView) ensureFolds() {
+	if tv.folds == nil {
+		tv.folds = findFolds(tv.text.Lines(0, tv.text.LineCount()), tv.config.TabWidth)
+		tv.closed = make(map[int]bool)
+	}
+}
+
+// resetFolds verwirft Bereiche und Klappzustand, z. B. bei neuem Inhalt
+func (tv *TextView) resetFolds() {
+	tv.folds = nil
+	tv.closed = nil
+}
+
+// foldAt liefert den innersten ein- bzw. ausgeklappten Bereich, der eine
+// Quellzeile (0-basiert) enthält, sonst -1
+func (tv *TextView) foldAt(line int, closed bool) int {
+	found := -1
+	for i, f := range tv.folds {
+		if f.start > line {
+			break
+		}
+		if line <= f.end && tv.closed[f.start] == closed {
+			found = i
+		}
+	}
+	return found
+}
+
+// hiddenBy liefert die Kopfzeile des äußersten eingeklappten Bereichs, in
+// dem eine Quellzeile (0-basiert) verborgen ist, sonst -1
+func (tv *TextView) hiddenBy(line int) int {
+	for _, f := range tv.folds {
+		if f.start >= line {
+			break
+		}
+		if line <= f.end && tv.closed[f.start] {
+			return f.start
+		}
+	}
+	return -1
+}
+
+// foldEnd liefert die letzte verborgene Zeile, wenn der Bereich mit der
+// Kopfzeile line eingeklappt ist, sonst -1
+func (tv *TextView) foldEnd(line int) int {
+	if !tv.closed[line] {
+		return -1
+	}
+	for _, f := range tv.folds {
+		if f.start == line {
+			return f.end
+		}
+	}
+	return -1
+}
+
+// foldMarker liefert den Hinweis hinter einer eingeklappten Kopfzeile
+func foldMarker(hidden int) string {
+	if hidden == 1 {
+		return " ⋯ 1 Zeile"
+	}
+	return fmt.Sprintf(" ⋯ %d Zeilen", hidden)
+}
+
+// ToggleFold klappt den Bereich unter dem Cursor auf oder zu. Steht der
+// Cursor auf einem eingeklappten Bereich, wird er aufgeklappt, sonst der
+// innerste offene Bereich um den Cursor zugeklappt. false heißt, dass es
+// dort nichts zu klappen gibt.
+func (tv *TextView) ToggleFold() bool {
+	tv.ensureFolds()
+	if line := tv.GetCurrentLine() - 1; tv.closed[line] {
+		return tv.OpenFold()
+	}
+	return tv.CloseFold()
+}
+
+// CloseFold klappt den innersten offenen Bereich um den Cursor zu
+func (tv *TextView) CloseFold() bool {
+	tv.ensureFolds()
+	i := tv.foldAt(tv.GetCurrentLine()-1, false)
+	if i < 0 {
+		return false
+	}
+	start := tv.folds[i].start
+	tv.closed[start] = true
+	tv.setFoldedContent(start + 1)
+	return true
+}
+
+// OpenFold klappt den Bereich unter dem Cursor auf
+func (tv *TextView) OpenFold() bool {
+	tv.ensureFolds()
+	line := tv.GetCurrentLine() - 1
+	if !tv.closed[line] {
+		return false
+	}
+	delete(tv.closed, line)
+	tv.setFoldedContent(line + 1)
+	return true
+}
+
+// CloseAllFolds klappt alle Bereiche zu
+func (tv *TextView) CloseAllFolds() bool {
+	tv.ensureFolds()
+	if len(tv.folds) == 0 {
+		return false
+	}
+	for _, f := range tv.folds {
+		tv.closed[f.start] = true
+	}
+	tv.setFoldedContent(tv.GetCurrentLine())
+	return true
+}
+
+// OpenAllFolds klappt alle Bereiche auf
+func (tv *TextView) OpenAllFolds() bool {
+	if len(tv.closed) == 0 {
+		return false
+	}
+	tv.closed = make(map[int]bool)
+	tv.setFoldedContent(tv.GetCurrentLine())
+	return true
+}
+
+// ClosedFolds liefert die Anzahl der eingeklappten Bereiche
+func (tv *TextView) ClosedFolds() int {
+	return len(tv.closed)
+}
+
+// revealLine klappt alle Bereiche auf, die eine Quellzeile (0-basiert)
+// verbergen, und meldet, ob sich dabei etwas geändert hat
+func (tv *TextView) revealLine(line int) bool {
+	changed := false
+	for start := tv.hiddenBy(line); start >= 0; start = tv.hiddenBy(line) {
+		delete(tv.closed, start)
+		changed = true
+	}
+	return changed
+}
+
+// setFoldedContent baut die Anzeige nach einer Klappänderung neu auf und
+// setzt den Cursor auf die Quellzeile (1-basiert)
+func (tv *TextView) setFoldedContent(line int) {
+	tv.refresh()
+	tv.currentLine = tv.displayIndex(line)
+	if tv.currentLine < tv.viewport.YOffset {
+		tv.setOffset(tv.currentLine)
+	}
+	if bottom := tv.viewport.YOffset + tv.viewport.Height; tv.currentLine >= bottom {
+		tv.setOffset(tv.currentLine - tv.viewport.Height + 1)
+	}
+	tv.clampOffset()
+}
+
+// renderMarker rendert den Hinweis einer eingeklappten Kopfzeile und füllt
+// die Zeile bis zur Breite auf
+func (tv *TextView) renderMarker(marker string, width int, base lipgloss.Style, hasBase bool) string {
+	marker = runewidth.Truncate(marker, width, "")
+	style := tv.style.Fold
+	if hasBase {
+		style = style.Inherit(base)
+	}
+	return style.Render(marker) + applySpans(strings.Repeat(" ", width-runewidth.StringWidth(marker)), base, hasBase, nil)
+}
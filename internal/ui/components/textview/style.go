@@ -6,12 +6,16 @@ import (
 )
 
 type Style struct {
-	Container   lipgloss.Style
-	LineNumber  lipgloss.Style
-	NormalLine  lipgloss.Style
-	CurrentLine lipgloss.Style
-	EmptyText   lipgloss.Style
-	SearchMatch lipgloss.Style
+	Container    lipgloss.Style
+	LineNumber   lipgloss.Style
+	NormalLine   lipgloss.Style
+	CurrentLine  lipgloss.Style
+	EmptyText    lipgloss.Style
+	SearchMatch  lipgloss.Style
+	CurrentMatch lipgloss.Style // Treffer, auf dem die Suche steht
+	Header       lipgloss.Style // Feste Kopfzeile
+	Fold         lipgloss.Style // Hinweis hinter eingeklappten Zeilen
+	Cursor       lipgloss.Style // Schreibmarke beim Bearbeiten
 }
 
 func NewStyleFromConfig(cfg *config.Config) Style {
@@ -44,5 +48,23 @@ func NewStyleFromConfig(cfg *config.Config) Style {
 			Background(lipgloss.Color(theme.Accent)).
 			Foreground(lipgloss.Color(theme.Background)).
 			Bold(true),
+
+		CurrentMatch: lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.Changed)).
+			Foreground(lipgloss.Color(theme.Background)).
+			Bold(true),
+
+		Header: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Background(lipgloss.Color(theme.Background)).
+			Bold(true).
+			Underline(true),
+
+		Fold: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Italic(true),
+
+		Cursor: lipgloss.NewStyle().
+			Reverse(true),
 	}
 }
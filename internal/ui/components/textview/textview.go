@@ -2,10 +2,17 @@ package textview
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/mattn/go-runewidth"
 )
 
 type Config struct {
@@ -15,15 +22,41 @@ type Config struct {
 	Style           Style
 }
 
+// displayLine ist eine angezeigte Zeile und verweist auf ihre Quellzeile
+type displayLine struct {
+	src   int    // Quellzeile, 0-basiert
+	text  string // Angezeigter Text (Tabs expandiert, ggf. umbrochen)
+	start int    // Byte-Offset des Abschnitts in der expandierten Zeile
+	cont  bool   // Fortsetzung einer umbrochenen Zeile
+	fold  int    // Anzahl eingeklappter Zeilen hinter diesem Abschnitt
+}
+
 type TextView struct {
 	viewport    *viewport.Model
-	content     string
+	text        rope.Rope // Quelltext mit Zeilenindex
+	loaded      bool      // Es wurde schon ein Inhalt gesetzt
+	plain       string    // Quelltext am Stück, gültig mit plainOK
+	plainOK     bool
+	display     []displayLine // Angezeigte Zeilen nach Filter und Umbruch
+	layout      layout        // Einstellungen, mit denen display berechnet wurde
 	width       int
 	height      int
-	currentLine int
+	currentLine int // Index in display
+	left        int // Erste sichtbare Spalte ohne Umbruch
 	config      Config
 	style       Style
 	searchTerm  string
+	filter      string
+	decorators  []namedDecorator
+	header      string // Feste Kopfzeile über dem Text, z. B. bei Tabellen
+	headerSpans []Span
+	folds       []fold               // Klappbare Bereiche, nil bis zum ersten Klappen
+	closed      map[int]bool         // Eingeklappte Bereiche nach Kopfzeile
+	cursor      cursor               // Schreibmarke beim Bearbeiten
+	match       match                // Aktueller Suchtreffer
+	cache       map[renderKey]string // Zuletzt gerenderte Zeilen
+	numberStyle lipgloss.Style       // LineNumber mit Breite numberWidth
+	numberWidth int
 }
 
 func New(width, height int, cfg Config) TextView {
@@ -37,21 +70,237 @@ func New(width, height int, cfg Config) TextView {
 	}
 }
 
+// SetContent setzt den Inhalt. Ist er unverändert, wird nur die Anzeige
+// neu aufgebaut.
 func (tv *TextView) SetContent(content string) {
-	tv.content = content
+	if tv.loaded && tv.plainOK && content == tv.plain {
+		tv.redisplay()
+		return
+	}
+	tv.setText(rope.New(content))
+	tv.plain, tv.plainOK = content, true
+}
+
+// SetText setzt den Inhalt als Rope, z. B. den aktuellen Stand eines
+// bearbeiteten Dokuments. Nur die Zeilen, die sich gegenüber dem bisherigen
+// Inhalt geändert haben, werden neu aufbereitet.
+func (tv *TextView) SetText(text rope.Rope) {
+	tv.setText(text)
+	tv.plainOK = false
+}
+
+// Text liefert den Inhalt als Rope
+func (tv *TextView) Text() rope.Rope {
+	return tv.text
+}
+
+func (tv *TextView) setText(text rope.Rope) {
+	// Aktuelle Quellzeile merken, damit sie nach dem Neuaufbau sichtbar bleibt
+	srcLine := tv.GetCurrentLine()
+
+	change, changed := rope.Diff(tv.text, text)
+	first := !tv.loaded
+	tv.text, tv.loaded = text, true
+	if changed {
+		tv.cache = nil
+	}
+	switch {
+	case first || changed && len(tv.closed) > 0:
+		tv.resetFolds()
+		tv.rebuildDisplay()
+	case changed:
+		tv.resetFolds()
+		tv.spliceDisplay(change)
+	default:
+		tv.relayout()
+	}
+	tv.placeCursor(srcLine)
+}
+
+// redisplay baut die Anzeige aus dem unveränderten Inhalt neu auf
+func (tv *TextView) redisplay() {
+	srcLine := tv.GetCurrentLine()
+	tv.rebuildDisplay()
+	tv.placeCursor(srcLine)
+}
+
+// placeCursor setzt den Cursor nach einem Neuaufbau auf die Quellzeile
+// (1-basiert)
+func (tv *TextView) placeCursor(srcLine int) {
+	tv.currentLine = tv.displayIndex(srcLine)
+	tv.clampOffset()
+}
+
+// layout enthält alles, wovon die angezeigten Zeilen abhängen. Styles und
+// Decorators gehören nicht dazu, außer über die Textbreite beim Umbruch.
+type layout struct {
+	wrap     bool
+	tabWidth int
+	filter   string
+	width    int // Textbreite beim Umbruch, sonst 0
+}
+
+// currentLayout liefert das Layout der aktuellen Einstellungen
+func (tv *TextView) currentLayout() layout {
+	l := layout{wrap: tv.config.WordWrap, tabWidth: tv.config.TabWidth, filter: tv.filter}
+	if l.wrap {
+		l.width = tv.textWidth()
+	}
+	return l
+}
+
+// rebuildDisplay berechnet die angezeigten Zeilen aus Filter, Tabs und Umbruch
+func (tv *TextView) rebuildDisplay() {
+	tv.display = tv.display[:0]
+	tv.layout = tv.currentLayout()
+
+	lowFilter := strings.ToLower(tv.filter)
+	next := 0
+	tv.text.EachLine(0, func(i int, line string) bool {
+		if i < next || !matchesFilter(line, lowFilter) {
+			return true
+		}
+
+		// Eingeklappte Zeilen überspringen, der Hinweis steht am Ende der Kopfzeile
+		hidden := 0
+		if end := tv.foldEnd(i); end > i {
+			hidden = end - i
+		}
+		tv.display = tv.appendLine(tv.display, i, line, hidden)
+		next = i + hidden + 1
+		return true
+	})
+}
+
+// spliceDisplay ersetzt nur die angezeigten Zeilen der geänderten
+// Quellzeilen. Eingeklappte Bereiche darf es dabei nicht geben.
+func (tv *TextView) spliceDisplay(c rope.Change) {
+	// Mit der Zeilenzahl kann sich die Breite der Zeilennummern ändern
+	if tv.layout != tv.currentLayout() {
+		tv.rebuildDisplay()
+		return
+	}
+
+	bySrc := func(line int) int {
+		return sort.Search(len(tv.display), func(i int) bool { return tv.display[i].src >= line })
+	}
+	from, to := bySrc(c.Line), bySrc(c.Line+c.Old)
+
+	lowFilter := strings.ToLower(tv.filter)
+	var lines []displayLine
+	tv.text.EachLine(c.Line, func(i int, line string) bool {
+		if i >= c.Line+c.New {
+			return false
+		}
+		if matchesFilter(line, lowFilter) {
+			lines = tv.appendLine(lines, i, line, 0)
+		}
+		return true
+	})
+
+	if delta := c.New - c.Old; delta != 0 {
+		for i := to; i < len(tv.display); i++ {
+			tv.display[i].src += delta
+		}
+	}
+	tv.display = slices.Replace(tv.display, from, to, lines...)
+}
 
-	// Wenn es einen Suchbegriff gibt, wende Highlighting an
-	if tv.searchTerm != "" {
-		lines := strings.Split(content, "\n")
-		var highlightedLines []string
+// appendLine hängt die angezeigten Abschnitte einer Quellzeile an. hidden
+// ist die Anzahl der dahinter eingeklappten Zeilen.
+func (tv *TextView) appendLine(dst []displayLine, i int, line string, hidden int) []displayLine {
+	line = tv.expandTabs(line)
+	if !tv.config.WordWrap {
+		return append(dst, displayLine{src: i, text: line, fold: hidden})
+	}
 
-		for _, line := range lines {
-			highlightedLines = append(highlightedLines, tv.highlightSearchTerm(line))
+	width := tv.layout.width
+	if hidden > 0 {
+		width -= runewidth.StringWidth(foldMarker(hidden))
+	}
+	parts, starts := wrapLine(line, width)
+	for j, part := range parts {
+		dl := displayLine{src: i, text: part, start: starts[j], cont: j > 0}
+		if j == len(parts)-1 {
+			dl.fold = hidden
 		}
+		dst = append(dst, dl)
+	}
+	return dst
+}
+
+// matchesFilter meldet, ob eine Zeile den klein geschriebenen Filter enthält
+func matchesFilter(line, lowFilter string) bool {
+	return lowFilter == "" || strings.Contains(strings.ToLower(line), lowFilter)
+}
 
-		tv.viewport.SetContent(strings.Join(highlightedLines, "\n"))
-	} else {
-		tv.viewport.SetContent(content)
+// displayIndex sucht die erste angezeigte Zeile zu einer Quellzeile (1-basiert).
+// Ist die Zeile ausgefiltert, wird die nächste sichtbare gewählt.
+// Eine eingeklappte Zeile wird auf die Kopfzeile ihres Bereichs abgebildet.
+func (tv *TextView) displayIndex(line int) int {
+	target := line - 1
+	if start := tv.hiddenBy(target); start >= 0 {
+		target = start
+	}
+	i := sort.Search(len(tv.display), func(i int) bool { return tv.display[i].src >= target })
+	if i == len(tv.display) {
+		return max(i-1, 0)
+	}
+	return i
+}
+
+// textWidth liefert die für Text verfügbare Breite ohne Zeilennummern
+func (tv *TextView) textWidth() int {
+	width := tv.width
+	if tv.config.ShowLineNumbers {
+		// Zeilennummer mit Padding und ein Leerzeichen Abstand
+		width -= tv.calculateLineNumberWidth() + 2
+	}
+	width -= tv.gutterWidth()
+	if width < 1 {
+		width = 1
+	}
+	return width
+}
+
+func (tv *TextView) expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+
+	tabWidth := tv.config.TabWidth
+	if tabWidth <= 0 {
+		tabWidth = 4
+	}
+
+	var b strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", spaces))
+			col += spaces
+			continue
+		}
+		b.WriteRune(r)
+		col += runewidth.RuneWidth(r)
+	}
+	return b.String()
+}
+
+func (tv *TextView) clampOffset() {
+	maxOffset := len(tv.display) - tv.viewport.Height
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if tv.viewport.YOffset > maxOffset {
+		tv.viewport.YOffset = maxOffset
+	}
+	if tv.currentLine >= len(tv.display) {
+		tv.currentLine = len(tv.display) - 1
+	}
+	if tv.currentLine < 0 {
+		tv.currentLine = 0
 	}
 }
 
@@ -68,200 +317,615 @@ func (tv *TextView) calculateLineNumberWidth() int {
 	return digits + 1 // +1 für zusätzliches Padding
 }
 
-// SetSearchTerm setzt den Suchbegriff und aktualisiert die Anzeige
+// SetSearchTerm setzt den Suchbegriff. Hervorgehoben wird beim Rendern
+// nur in den sichtbaren Zeilen.
 func (tv *TextView) SetSearchTerm(term string) {
 	tv.searchTerm = term
-	// Aktualisiere den Inhalt mit dem neuen Suchbegriff
-	if tv.content != "" {
-		tv.SetContent(tv.content)
-	}
 }
 
-func (tv *TextView) highlightSearchTerm(line string) string {
-	if tv.searchTerm == "" {
-		return line
+// match ist ein Suchtreffer: Quellzeile (1-basiert, 0 für keinen) und
+// Byte-Bereich in der Zeile
+type match struct {
+	line  int
+	start int
+	end   int
+}
+
+// SetCurrentMatch hebt den Treffer zwischen den Byte-Offsets start und end
+// einer Zeile (1-basiert) anders hervor als die übrigen. Zeile 0 entfernt
+// die Hervorhebung.
+func (tv *TextView) SetCurrentMatch(line, start, end int) {
+	m := match{line: line, start: start, end: end}
+	if m != tv.match {
+		tv.match = m
+		tv.cache = nil
 	}
+}
 
+// EachMatch ruft fn für jedes Vorkommen des kleingeschriebenen Begriffs in
+// einer Zeile auf. Treffer überlappen sich nicht, die Byte-Offsets beziehen
+// sich auf die Zeile, auch wenn sich Zeichen beim Kleinschreiben in der
+// Länge ändern.
+func EachMatch(line, lowTerm string, fn func(start, end int)) {
 	lowLine := strings.ToLower(line)
-	lowTerm := strings.ToLower(tv.searchTerm)
+	if lowTerm == "" || !strings.Contains(lowLine, lowTerm) {
+		return
+	}
 
-	var result string
-	lastIdx := 0
+	// Offsets in der kleingeschriebenen Zeile auf die Zeile umrechnen
+	offset := func(low int) int { return low }
+	if len(lowLine) != len(line) {
+		offsets := make([]int, 0, len(lowLine)+1)
+		for i, r := range line {
+			for n := utf8.RuneLen(unicode.ToLower(r)); n > 0; n-- {
+				offsets = append(offsets, i)
+			}
+		}
+		offsets = append(offsets, len(line))
+		offset = func(low int) int { return offsets[min(low, len(offsets)-1)] }
+	}
 
-	// Finde alle Vorkommen und highlighte sie
-	for {
-		idx := strings.Index(lowLine[lastIdx:], lowTerm)
-		if idx == -1 {
-			result += line[lastIdx:]
-			break
+	for from := 0; ; {
+		idx := strings.Index(lowLine[from:], lowTerm)
+		if idx < 0 {
+			return
 		}
+		start := from + idx
+		from = start + len(lowTerm)
+		fn(offset(start), offset(from))
+	}
+}
 
-		idx += lastIdx
-		match := line[idx : idx+len(tv.searchTerm)]
-		result += line[lastIdx:idx] + tv.style.SearchMatch.Render(match)
-		lastIdx = idx + len(tv.searchTerm)
+// searchSpans markiert alle Vorkommen des Suchbegriffs in einer Quellzeile
+// wie die Suche selbst
+func (tv *TextView) searchSpans(line string) []Span {
+	if tv.searchTerm == "" {
+		return nil
 	}
+	var spans []Span
+	EachMatch(line, strings.ToLower(tv.searchTerm), func(start, end int) {
+		spans = append(spans, Span{Start: start, End: end, Style: tv.style.SearchMatch})
+	})
+	return spans
+}
 
-	return result
+// displaySpans rechnet Spans der Quellzeile src auf einen angezeigten
+// Abschnitt um (Tabs und Umbruch)
+func (tv *TextView) displaySpans(src string, dl displayLine, spans []Span) []Span {
+	if len(spans) == 0 {
+		return nil
+	}
+	mapped := make([]Span, len(spans))
+	for i, sp := range spans {
+		mapped[i] = Span{
+			Start: tv.expandedOffset(src, sp.Start),
+			End:   tv.expandedOffset(src, sp.End),
+			Style: sp.Style,
+		}
+	}
+	return clipSpans(mapped, dl.start, dl.start+len(dl.text))
 }
 
 func (tv *TextView) Render() string {
-	if tv.content == "" {
+	if tv.text.Len() == 0 && !tv.cursor.on {
 		return tv.style.EmptyText.Render("Keine Datei geladen")
 	}
 
-	lines := strings.Split(tv.viewport.View(), "\n")
-	startLine := tv.viewport.YOffset + 1
-
 	var contentBuilder strings.Builder
-	maxWidth := tv.width
-
-	lineNumWidth := tv.calculateLineNumberWidth()
+	if tv.header != "" {
+		contentBuilder.WriteString(tv.renderHeader() + "\n")
+	}
 
+	numbers := 0
 	if tv.config.ShowLineNumbers {
-		tv.style.LineNumber = tv.style.LineNumber.Width(lineNumWidth)
-		maxWidth -= lineNumWidth + 1
+		numbers = tv.calculateLineNumberWidth()
+	}
+	cursorRow, cursorOffset := -1, 0
+	if tv.cursor.on {
+		cursorRow, cursorOffset = tv.cursorRow(), tv.cursorOffset()
 	}
 
-	for i, line := range lines {
-		lineNum := startLine + i
+	// Gestylt werden nur die sichtbaren Zeilen. Was schon im letzten Bild zu
+	// sehen war, kommt aus dem Cache, danach enthält er genau dieses Bild.
+	rendered := make(map[renderKey]string, tv.viewport.Height)
+	for i := 0; i < tv.viewport.Height; i++ {
+		idx := tv.viewport.YOffset + i
+		key := renderKey{
+			search:  tv.searchTerm,
+			width:   tv.width,
+			numbers: numbers,
+			current: idx == tv.currentLine,
+			cursor:  -1,
+		}
+		if !tv.config.WordWrap {
+			key.left = tv.left
+		}
+		if idx >= len(tv.display) {
+			contentBuilder.WriteString(tv.renderLine(key, false) + "\n")
+			continue
+		}
 
-		var linePrefix string
-		if tv.config.ShowLineNumbers {
-			format := fmt.Sprintf("%%%dd", lineNumWidth-1)
-			linePrefix = tv.style.LineNumber.Render(fmt.Sprintf(format, lineNum))
+		key.line = tv.display[idx]
+		if tv.match.line == key.line.src+1 {
+			key.match = tv.match
+		}
+		if idx == cursorRow {
+			key.cursor = cursorOffset - key.line.start
 		}
+		line, ok := tv.cache[key]
+		if !ok {
+			line = tv.renderLine(key, true)
+		}
+		rendered[key] = line
+		contentBuilder.WriteString(line + "\n")
+	}
+	tv.cache = rendered
+
+	return tv.style.Container.Render(contentBuilder.String())
+}
+
+// renderKey enthält alles, wovon das Aussehen einer angezeigten Zeile
+// abhängt. Decorators und Styles gehören nicht dazu, ihre Änderung leert
+// den Cache. Neuer Text, eine neue Größe und ein anderer aktueller Treffer
+// leeren ihn ebenfalls.
+type renderKey struct {
+	line    displayLine
+	search  string
+	width   int
+	numbers int // Breite der Zeilennummern, 0 ohne
+	current bool
+	left    int   // Erste sichtbare Spalte
+	cursor  int   // Byte-Offset der Schreibmarke im Abschnitt, -1 ohne
+	match   match // Aktueller Suchtreffer, falls er in dieser Zeile liegt
+}
 
-		// Textzeile mit Highlighting
-		lineContent := line
-		if len(lineContent) > maxWidth {
-			lineContent = lineContent[:maxWidth-3] + "..."
+// renderLine rendert eine angezeigte Zeile mit Zeilennummer, Randspalten
+// und Hervorhebungen. present ist false für leere Zeilen unter dem Text.
+func (tv *TextView) renderLine(key renderKey, present bool) string {
+	dl := key.line
+	maxWidth := tv.textWidth()
+
+	var linePrefix string
+	if key.numbers > 0 {
+		number := ""
+		if present && !dl.cont {
+			number = strconv.Itoa(dl.src + 1)
 		}
+		linePrefix = tv.lineNumberStyle(key.numbers).Render(fmt.Sprintf("%*s", key.numbers-1, number)) + " "
+	}
 
-		// Suchbegriff highlighten
-		lineContent = tv.highlightSearchTerm(lineContent)
+	// Textzeile mit Highlighting, eingeklappte Zeilen lassen Platz für den Hinweis
+	var marker string
+	textWidth := maxWidth
+	if present && dl.fold > 0 {
+		marker = foldMarker(dl.fold)
+		textWidth = max(maxWidth-runewidth.StringWidth(marker), 0)
+	}
+	// Ohne Umbruch beginnt der sichtbare Teil bei der ersten sichtbaren Spalte
+	text, cut := dl.text, 0
+	if key.left > 0 {
+		cut = columnOffset(dl.text, key.left)
+		text = dl.text[cut:]
+	}
+	lineContent := runewidth.Truncate(text, textWidth, "...")
+	if marker == "" {
+		lineContent = runewidth.FillRight(lineContent, maxWidth)
+	}
+	plainWidth := runewidth.StringWidth(lineContent)
+
+	// Hervorhebungen der Quellzeile im angezeigten Abschnitt
+	var (
+		base    lipgloss.Style
+		spans   []Span
+		hasBase bool
+	)
+	if present {
+		base, spans, hasBase = tv.rowSpans(key)
+		if cut > 0 {
+			spans = clipSpans(spans, cut, len(dl.text))
+		}
+	}
 
-		// Aktuelle Zeile hervorheben
-		if i == (tv.viewport.YPosition) {
-			lineContent = tv.style.CurrentLine.Render(lineContent)
+	// Schreibmarke zuletzt, damit sie alles andere überdeckt
+	if key.cursor >= 0 {
+		if sp, ok := tv.cursorSpan(lineContent, key.cursor-cut); ok {
+			spans = append(spans, sp)
 		}
+	}
 
-		var fullLine string
-		if tv.config.ShowLineNumbers {
-			fullLine = lipgloss.JoinHorizontal(
-				lipgloss.Left,
-				linePrefix,
-				" ",
-				lineContent,
-			)
+	// Aktuelle Zeile hervorheben
+	if key.current {
+		if hasBase {
+			base = base.Inherit(tv.style.CurrentLine)
 		} else {
-			fullLine = lineContent
+			base, hasBase = tv.style.CurrentLine, true
 		}
+	}
+	lineContent = applySpans(lineContent, base, hasBase, spans)
+	if marker != "" {
+		lineContent += tv.renderMarker(marker, maxWidth-plainWidth, base, hasBase)
+	}
 
-		contentBuilder.WriteString(fullLine + "\n")
+	if len(tv.decorators) > 0 {
+		lineContent = tv.renderGutter(dl, present) + lineContent
 	}
+	return linePrefix + lineContent
+}
 
-	return tv.style.Container.Render(contentBuilder.String())
+// rowSpans bestimmt Grundstil und Hervorhebungen der Decorators und die
+// Suchtreffer in der Quellzeile, den aktuellen Treffer darüber, und bildet
+// sie auf den angezeigten Abschnitt ab
+func (tv *TextView) rowSpans(key renderKey) (lipgloss.Style, []Span, bool) {
+	dl := key.line
+	src := tv.text.Line(dl.src)
+	base, spans, hasBase := tv.decorate(dl.src)
+	spans = append(spans, tv.searchSpans(src)...)
+	if key.match.line > 0 {
+		spans = append(spans, Span{Start: key.match.start, End: key.match.end, Style: tv.style.CurrentMatch})
+	}
+	return base, tv.displaySpans(src, dl, spans), hasBase
 }
 
-// Hilfsfunktion für Wortumbruch
-func (tv *TextView) wrapText(text string) string {
-	if !tv.config.WordWrap {
-		return text
+// columnOffset liefert den Byte-Offset des ersten Zeichens ab der Spalte col
+func columnOffset(s string, col int) int {
+	width := 0
+	for i, r := range s {
+		if width >= col {
+			return i
+		}
+		width += runewidth.RuneWidth(r)
+	}
+	return len(s)
+}
+
+// showColumns verschiebt die Ansicht ohne Umbruch waagerecht, bis der
+// Bereich zwischen den Byte-Offsets from und to einer angezeigten Zeile
+// sichtbar ist. Muss verschoben werden, steht der Bereich in der Mitte.
+func (tv *TextView) showColumns(dl displayLine, from, to int) {
+	if tv.config.WordWrap {
+		tv.left = 0
+		return
+	}
+	// Die Schreibmarke am Zeilenende braucht eine Spalte, rechts steht ggf. "..."
+	start := runewidth.StringWidth(dl.text[:min(from, len(dl.text))])
+	end := max(runewidth.StringWidth(dl.text[:min(to, len(dl.text))]), start+1)
+	visible := max(tv.textWidth()-len("..."), 1)
+	if start >= tv.left && end <= tv.left+visible {
+		return
 	}
+	tv.left = max(start-visible/2, 0)
+	if end > tv.left+visible {
+		tv.left = start
+	}
+}
 
-	width := tv.width
+// lineNumberStyle liefert den Style der Zeilennummern für eine Breite. Er
+// wird nur neu erstellt, wenn sich die Breite ändert.
+func (tv *TextView) lineNumberStyle(width int) lipgloss.Style {
+	if width != tv.numberWidth {
+		// Breite inklusive Padding links und rechts
+		tv.numberStyle = tv.style.LineNumber.Width(width + 1)
+		tv.numberWidth = width
+	}
+	return tv.numberStyle
+}
+
+// renderHeader rendert die Kopfzeile bündig mit dem Text darunter
+func (tv *TextView) renderHeader() string {
+	var prefix string
 	if tv.config.ShowLineNumbers {
-		width -= tv.style.LineNumber.GetWidth()
+		prefix = strings.Repeat(" ", tv.calculateLineNumberWidth()+2)
 	}
+	prefix += strings.Repeat(" ", tv.gutterWidth())
 
-	var wrapped strings.Builder
-	lines := strings.Split(text, "\n")
+	// Die Kopfzeile folgt der waagerechten Verschiebung des Textes
+	text, cut := tv.header, 0
+	if !tv.config.WordWrap && tv.left > 0 {
+		cut = columnOffset(text, tv.left)
+		text = text[cut:]
+	}
+	width := tv.textWidth()
+	header := runewidth.FillRight(runewidth.Truncate(text, width, "..."), width)
+	return prefix + applySpans(header, tv.style.Header, true, clipSpans(tv.headerSpans, cut, cut+len(header)))
+}
 
-	for i, line := range lines {
-		if len(line) <= width {
-			wrapped.WriteString(line)
-		} else {
-			// Einfacher Wortumbruch
-			words := strings.Fields(line)
-			lineLen := 0
-			for _, word := range words {
-				wordLen := len(word) + 1 // +1 für Leerzeichen
-				if lineLen+wordLen > width {
-					wrapped.WriteString("\n")
-					lineLen = 0
-				}
-				if lineLen > 0 {
-					wrapped.WriteString(" ")
-				}
-				wrapped.WriteString(word)
-				lineLen += wordLen
+// SetHeader setzt eine Kopfzeile, die beim Scrollen stehen bleibt. Die
+// Spans heben Teile der Kopfzeile hervor. Leer entfernt die Kopfzeile.
+func (tv *TextView) SetHeader(header string, spans []Span) {
+	tv.header = header
+	tv.headerSpans = spans
+	tv.viewport.Height = tv.bodyHeight()
+	tv.clampOffset()
+}
+
+// HeaderHeight liefert die Anzahl der Zeilen der Kopfzeile
+func (tv *TextView) HeaderHeight() int {
+	if tv.header != "" {
+		return 1
+	}
+	return 0
+}
+
+// bodyHeight liefert die Höhe für den Text unterhalb der Kopfzeile
+func (tv *TextView) bodyHeight() int {
+	return max(tv.height-tv.HeaderHeight(), 1)
+}
+
+// TextWidth liefert die Breite, die für den Text selbst bleibt
+func (tv *TextView) TextWidth() int {
+	return tv.textWidth()
+}
+
+// wrapLine bricht eine Zeile an Wortgrenzen auf die angegebene Breite um.
+// Wörter, die länger als die Breite sind, werden hart getrennt.
+// Zusätzlich wird der Byte-Offset jedes Teils in der Zeile geliefert.
+func wrapLine(line string, width int) (parts []string, starts []int) {
+	if width <= 0 || runewidth.StringWidth(line) <= width {
+		return []string{line}, []int{0}
+	}
+
+	offset := 0
+	runes := []rune(line)
+	for len(runes) > 0 {
+		col, cut, lastSpace := 0, 0, -1
+		for cut < len(runes) {
+			w := runewidth.RuneWidth(runes[cut])
+			if col+w > width {
+				break
+			}
+			if runes[cut] == ' ' {
+				lastSpace = cut
 			}
+			col += w
+			cut++
+		}
+
+		if cut == len(runes) {
+			parts = append(parts, string(runes))
+			starts = append(starts, offset)
+			break
 		}
-		if i < len(lines)-1 {
-			wrapped.WriteString("\n")
+		if cut == 0 {
+			cut = 1 // Zeichen breiter als die Zeile
+		} else if lastSpace > 0 {
+			cut = lastSpace + 1
 		}
+
+		parts = append(parts, strings.TrimRight(string(runes[:cut]), " "))
+		starts = append(starts, offset)
+		offset += len(string(runes[:cut]))
+		runes = runes[cut:]
 	}
 
-	return wrapped.String()
+	return parts, starts
 }
 
 // Standard Getter/Setter Methoden bleiben gleich
 func (tv *TextView) ScrollUp(lines int) {
-	tv.viewport.LineUp(lines)
+	tv.setOffset(tv.viewport.YOffset - lines)
 	tv.currentLine = tv.viewport.YOffset
 }
 
 func (tv *TextView) ScrollDown(lines int) {
-	tv.viewport.LineDown(lines)
+	tv.setOffset(tv.viewport.YOffset + lines)
 	tv.currentLine = tv.viewport.YOffset
 }
 
+// CursorDown bewegt die aktuelle Zeile nach unten. Gescrollt wird erst,
+// wenn sie den sichtbaren Bereich verlässt.
+func (tv *TextView) CursorDown(lines int) {
+	tv.currentLine = min(tv.currentLine+lines, len(tv.display)-1)
+	if bottom := tv.viewport.YOffset + tv.viewport.Height; tv.currentLine >= bottom {
+		tv.setOffset(tv.currentLine - tv.viewport.Height + 1)
+	}
+	tv.clampOffset()
+}
+
+// CursorUp bewegt die aktuelle Zeile nach oben, siehe CursorDown
+func (tv *TextView) CursorUp(lines int) {
+	tv.currentLine = max(tv.currentLine-lines, 0)
+	if tv.currentLine < tv.viewport.YOffset {
+		tv.setOffset(tv.currentLine)
+	}
+}
+
+// ScrollToTop springt an den Anfang des Dokuments
+func (tv *TextView) ScrollToTop() {
+	tv.viewport.YOffset = 0
+	tv.currentLine = 0
+}
+
+// ScrollToBottom springt an das Ende des Dokuments
+func (tv *TextView) ScrollToBottom() {
+	tv.setOffset(len(tv.display))
+	tv.currentLine = len(tv.display) - 1
+	tv.clampOffset()
+}
+
+// setOffset setzt die erste sichtbare Zeile, begrenzt auf die angezeigten
+// Zeilen. Der Viewport hält nur Größe und Scrollposition, die Zeilen selbst
+// kennt allein die TextView.
+func (tv *TextView) setOffset(offset int) {
+	tv.viewport.YOffset = max(min(offset, len(tv.display)-tv.viewport.Height), 0)
+}
+
 func (tv *TextView) GetViewport() *viewport.Model {
 	return tv.viewport
 }
 
+// GetCurrentLine liefert die aktuelle Quellzeile (1-basiert)
 func (tv *TextView) GetCurrentLine() int {
+	if tv.currentLine < len(tv.display) {
+		return tv.display[tv.currentLine].src + 1
+	}
 	return tv.currentLine + 1
 }
 
 func (tv *TextView) GetTotalLines() int {
-	return strings.Count(tv.content, "\n") + 1
+	return tv.text.LineCount()
+}
+
+// GetDisplayLines liefert die Anzahl der angezeigten Zeilen nach Filter und Umbruch
+func (tv *TextView) GetDisplayLines() int {
+	return len(tv.display)
+}
+
+// GetDisplayLine liefert den Index der aktuellen angezeigten Zeile
+func (tv *TextView) GetDisplayLine() int {
+	return tv.currentLine
 }
 
 func (tv *TextView) ToggleLineNumbers() {
-	tv.config.ShowLineNumbers = !tv.config.ShowLineNumbers
+	tv.SetShowLineNumbers(!tv.config.ShowLineNumbers)
+}
+
+func (tv *TextView) SetShowLineNumbers(show bool) {
+	tv.config.ShowLineNumbers = show
+	tv.relayout()
+}
+
+func (tv *TextView) SetWordWrap(wrap bool) {
+	tv.config.WordWrap = wrap
+	tv.relayout()
+}
+
+func (tv *TextView) SetTabWidth(width int) {
+	tv.config.TabWidth = width
+	tv.relayout()
+}
+
+// SetStyle tauscht den Style aus, z. B. nach einem Themewechsel. Die
+// angezeigten Zeilen bleiben, nur der Cache wird geleert.
+func (tv *TextView) SetStyle(style Style) {
+	tv.config.Style = style
+	tv.style = style
+	tv.cache = nil
+	tv.numberWidth = 0
+}
+
+// SetConfig übernimmt alle Einstellungen auf einmal. Die Anzeige wird
+// höchstens einmal neu aufgebaut.
+func (tv *TextView) SetConfig(cfg Config) {
+	tv.SetStyle(cfg.Style)
+	tv.config = cfg
+	tv.relayout()
+}
+
+// SetFilter zeigt nur noch Zeilen an, die den Begriff enthalten.
+// Ein leerer Begriff hebt den Filter auf.
+func (tv *TextView) SetFilter(term string) {
+	tv.filter = term
+	tv.relayout()
+}
+
+func (tv *TextView) GetFilter() string {
+	return tv.filter
+}
+
+// VisibleContent liefert den Inhalt unter Berücksichtigung des Filters
+func (tv *TextView) VisibleContent() string {
+	if tv.filter == "" {
+		return tv.GetContent()
+	}
+
+	lowFilter := strings.ToLower(tv.filter)
+	var kept []string
+	tv.text.EachLine(0, func(_ int, line string) bool {
+		if matchesFilter(line, lowFilter) {
+			kept = append(kept, line)
+		}
+		return true
+	})
+	return strings.Join(kept, "\n")
+}
+
+// refresh baut die Anzeige neu auf, z. B. nach dem Auf- oder Zuklappen
+func (tv *TextView) refresh() {
+	if tv.loaded && (tv.text.Len() > 0 || tv.cursor.on) {
+		tv.redisplay()
+	}
+}
+
+// relayout baut die Anzeige nach einer Einstellungsänderung nur neu auf,
+// wenn sich das Layout geändert hat
+func (tv *TextView) relayout() {
+	if tv.layout != tv.currentLayout() {
+		tv.refresh()
+	}
 }
 
 func (tv *TextView) Resize(width, height int) {
+	if width != tv.width || height != tv.height {
+		tv.cache = nil
+	}
 	tv.width = width
 	tv.height = height
 	tv.viewport.Width = width
-	tv.viewport.Height = height
+	tv.viewport.Height = tv.bodyHeight()
 
-	// Bei Größenänderung Wortumbruch neu anwenden
-	if tv.config.WordWrap {
-		tv.SetContent(tv.content)
-	}
+	// Mit Wortumbruch hängen die angezeigten Zeilen von der Breite ab
+	tv.relayout()
 }
 
 func (tv *TextView) ToggleWordWrap() {
-	tv.config.WordWrap = !tv.config.WordWrap
-	if tv.content != "" {
-		tv.SetContent(tv.content) // Neu rendern mit/ohne Wrap
-	}
+	tv.SetWordWrap(!tv.config.WordWrap) // Neu rendern mit/ohne Wrap
 }
+
+// GetContent liefert den Inhalt am Stück
 func (tv *TextView) GetContent() string {
-	return tv.content
+	if !tv.plainOK {
+		tv.plain, tv.plainOK = tv.text.String(), true
+	}
+	return tv.plain
 }
 
 func (tv *TextView) ScrollToLine(line int) {
-	// Berücksichtige, dass Zeilennummern bei 1 beginnen
-	targetLine := line - 1
-	if targetLine < 0 {
-		targetLine = 0
+	// Sprungziele in eingeklappten Bereichen werden aufgeklappt
+	if tv.revealLine(line - 1) {
+		tv.refresh()
 	}
 
+	// Zeilennummern beginnen bei 1, Filter und Umbruch berücksichtigen
+	tv.left = 0
+	tv.centerRow(tv.displayIndex(line))
+}
+
+// ScrollToMatch springt zu einem Treffer zwischen den Byte-Offsets start und
+// end einer Quellzeile (1-basiert). Mit Umbruch wird der Abschnitt gewählt,
+// in dem der Treffer beginnt, ohne Umbruch wird waagerecht verschoben.
+func (tv *TextView) ScrollToMatch(line, start, end int) {
+	tv.ScrollToLine(line)
+	if line < 1 || line > tv.text.LineCount() {
+		return
+	}
+	src := tv.text.Line(line - 1)
+	from, to := tv.expandedOffset(src, start), tv.expandedOffset(src, end)
+	row := tv.rowAt(line-1, from)
+	if row < 0 {
+		return // Ausgefiltert
+	}
+	if row != tv.currentLine {
+		tv.centerRow(row)
+	}
+	dl := tv.display[row]
+	tv.showColumns(dl, from-dl.start, to-dl.start)
+}
+
+// rowAt liefert die angezeigte Zeile, die den Byte-Offset off der Quellzeile
+// line (0-basiert, Tabs expandiert) enthält, -1 wenn sie nicht angezeigt wird
+func (tv *TextView) rowAt(line, off int) int {
+	row := -1
+	first := sort.Search(len(tv.display), func(i int) bool { return tv.display[i].src >= line })
+	for i := first; i < len(tv.display) && tv.display[i].src == line; i++ {
+		if tv.display[i].start <= off {
+			row = i
+		}
+	}
+	return row
+}
+
+// centerRow macht eine angezeigte Zeile zur aktuellen und zentriert sie
+func (tv *TextView) centerRow(targetLine int) {
 	// Berechne die optimale Scrollposition
 	viewportHeight := tv.viewport.Height
 	halfHeight := viewportHeight / 2
@@ -272,6 +936,17 @@ func (tv *TextView) ScrollToLine(line int) {
 		newPosition = 0
 	}
 
-	tv.viewport.YOffset = newPosition
+	tv.setOffset(newPosition)
 	tv.currentLine = targetLine
 }
+
+// SetPosition setzt den Cursor auf eine Quellzeile (1-basiert) und die
+// erste sichtbare Zeile, z. B. um eine gespeicherte Position herzustellen.
+// Der Cursor bleibt dabei sichtbar.
+func (tv *TextView) SetPosition(line, offset int) {
+	tv.ScrollToLine(line)
+	offset = min(offset, tv.currentLine)
+	offset = max(offset, tv.currentLine-tv.viewport.Height+1, 0)
+	tv.setOffset(offset)
+	tv.clampOffset()
+}
@@ -0,0 +1,287 @@
+package textview
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/rope"
+)
+
+func newTestView(width, height int) TextView {
+	cfg := config.DefaultConfig()
+	return New(width, height, Config{
+		ShowLineNumbers: true,
+		TabWidth:        4,
+		Style:           NewStyleFromConfig(&cfg),
+	})
+}
+
+func testText(lines int) rope.Rope {
+	return rope.New(strings.Repeat("func foo() {\treturn 42 } // etwas Text\n", lines))
+}
+
+// BenchmarkRender misst die Kosten eines Bildes. Sie hängen nur von der
+// Höhe der Ansicht ab, nicht von der Länge des Textes.
+func BenchmarkRender(b *testing.B) {
+	for _, lines := range []int{1000, 100000, 1000000} {
+		text := testText(lines)
+
+		b.Run(fmt.Sprintf("scroll/%d", lines), func(b *testing.B) {
+			tv := newTestView(120, 50)
+			tv.SetText(text)
+			tv.SetSearchTerm("foo")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if i%(lines-50) == 0 {
+					tv.ScrollToTop()
+				}
+				tv.ScrollDown(1)
+				_ = tv.Render()
+			}
+		})
+
+		b.Run(fmt.Sprintf("static/%d", lines), func(b *testing.B) {
+			tv := newTestView(120, 50)
+			tv.SetText(text)
+			tv.SetSearchTerm("foo")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = tv.Render()
+			}
+		})
+	}
+}
+
+// BenchmarkSetDecorator misst das Setzen eines Decorators, z. B. einer
+// Marke. Ohne Umbruch hängt es nicht von der Länge des Textes ab.
+func BenchmarkSetDecorator(b *testing.B) {
+	for _, lines := range []int{1000, 1000000} {
+		b.Run(fmt.Sprint(lines), func(b *testing.B) {
+			tv := newTestView(120, 50)
+			tv.SetText(testText(lines))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tv.SetDecorator("rand", marker("+"))
+				_ = tv.Render()
+			}
+		})
+	}
+}
+
+// marker hebt jede Zeile mit einem Rand hervor
+type marker string
+
+func (m marker) GutterWidth() int       { return len(m) }
+func (m marker) Gutter(line int) string { return string(m) }
+func (m marker) Decorate(line int) (lipgloss.Style, []Span, bool) {
+	return lipgloss.Style{}, nil, false
+}
+
+func TestRenderCacheCleared(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(tv *TextView)
+	}{
+		{"SetText", func(tv *TextView) {
+			text := tv.Text()
+			text.ReplaceLines(2, 3, []string{"geändert"})
+			tv.SetText(text)
+		}},
+		{"SetDecorator", func(tv *TextView) { tv.SetDecorator("rand", marker("+")) }},
+		{"Resize", func(tv *TextView) { tv.Resize(60, 10) }},
+		{"SetCurrentMatch", func(tv *TextView) { tv.SetCurrentMatch(3, 5, 8) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tv := newTestView(80, 12)
+			tv.SetText(testText(100))
+			tv.SetSearchTerm("foo")
+			_ = tv.Render()
+			if len(tv.cache) == 0 {
+				t.Fatal("Render füllt den Cache nicht")
+			}
+
+			tt.change(&tv)
+			if len(tv.cache) != 0 {
+				t.Fatalf("Cache nach %s nicht geleert: %d Zeilen", tt.name, len(tv.cache))
+			}
+
+			// Das nächste Bild entspricht dem einer neuen Ansicht
+			fresh := newTestView(tv.width, tv.height)
+			fresh.SetText(tv.Text())
+			fresh.SetSearchTerm("foo")
+			fresh.decorators = tv.decorators
+			fresh.match = tv.match
+			if got, want := tv.Render(), fresh.Render(); got != want {
+				t.Errorf("Bild nach %s weicht ab:\n%s\nerwartet:\n%s", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestRenderCacheKept(t *testing.T) {
+	tv := newTestView(80, 12)
+	tv.SetText(testText(100))
+	_ = tv.Render()
+
+	// Ohne Änderung wird nichts neu gerendert
+	tv.SetCurrentMatch(0, 0, 0)
+	tv.Resize(80, 12)
+	tv.SetText(tv.Text())
+	if len(tv.cache) == 0 {
+		t.Error("Cache ohne Änderung geleert")
+	}
+}
+
+// TestRelayout prüft, dass die angezeigten Zeilen nur neu berechnet
+// werden, wenn sich das Layout ändert
+func TestRelayout(t *testing.T) {
+	cfg := config.DefaultConfig()
+	style := NewStyleFromConfig(&cfg)
+	tests := []struct {
+		name    string
+		wrap    bool
+		change  func(tv *TextView)
+		rebuild bool
+	}{
+		{"gleicher Umbruch", false, func(tv *TextView) { tv.SetWordWrap(false) }, false},
+		{"Umbruch an", false, func(tv *TextView) { tv.SetWordWrap(true) }, true},
+		{"gleiche Tabbreite", false, func(tv *TextView) { tv.SetTabWidth(4) }, false},
+		{"Tabbreite", false, func(tv *TextView) { tv.SetTabWidth(8) }, true},
+		{"Style", true, func(tv *TextView) { tv.SetStyle(style) }, false},
+		{"gleicher Filter", false, func(tv *TextView) { tv.SetFilter("") }, false},
+		{"Filter", false, func(tv *TextView) { tv.SetFilter("foo") }, true},
+		{"Decorator ohne Umbruch", false, func(tv *TextView) { tv.SetDecorator("rand", marker("+")) }, false},
+		{"Decorator ohne Randspalte", true, func(tv *TextView) { tv.SetDecorator("rand", marker("")) }, false},
+		{"Decorator mit Umbruch", true, func(tv *TextView) { tv.SetDecorator("rand", marker("+")) }, true},
+		{"Zeilennummern ohne Umbruch", false, func(tv *TextView) { tv.SetShowLineNumbers(false) }, false},
+		{"Zeilennummern mit Umbruch", true, func(tv *TextView) { tv.SetShowLineNumbers(false) }, true},
+		{"Höhe", true, func(tv *TextView) { tv.Resize(80, 30) }, false},
+		{"Breite ohne Umbruch", false, func(tv *TextView) { tv.Resize(60, 12) }, false},
+		{"Breite mit Umbruch", true, func(tv *TextView) { tv.Resize(60, 12) }, true},
+		{"gleicher Text", false, func(tv *TextView) { tv.SetText(tv.Text()) }, false},
+		{"gleiche Einstellungen", true, func(tv *TextView) { tv.SetConfig(tv.config) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tv := newTestView(80, 12)
+			tv.SetWordWrap(tt.wrap)
+			tv.SetText(testText(100))
+			_ = tv.Render()
+
+			// Eine Markierung in der ersten Zeile überlebt nur ohne Neuaufbau
+			const mark = "nicht neu berechnet"
+			tv.display[0].text = mark
+			tt.change(&tv)
+			if rebuilt := tv.display[0].text != mark; rebuilt != tt.rebuild {
+				t.Errorf("neu aufgebaut: %v, erwartet %v", rebuilt, tt.rebuild)
+			}
+		})
+	}
+}
+
+// TestRowSpans prüft, dass die übrigen Treffer wie der aktuelle aus der
+// Quellzeile bestimmt werden und über Umbrüche hinweg reichen
+func TestRowSpans(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		wrap  bool
+		width int
+		want  []string // Bereiche je angezeigter Zeile
+	}{
+		{"einfach", "ab foo cd foo", false, 20, []string{"[{3 6} {10 13}]"}},
+		{"Länge ändert sich beim Kleinschreiben", "İİİ foo", false, 20, []string{"[{7 10}]"}},
+		{"Tabs", "\tfoo", false, 20, []string{"[{4 7}]"}},
+		{"über den Umbruch", "abcdefghfooxyz", true, 10, []string{"[{8 10}]", "[{0 1}]"}},
+		{"hinter dem Umbruch", "abcdefghij foo", true, 10, []string{"[]", "[{1 4}]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			tv := New(tt.width, 5, Config{TabWidth: 4, WordWrap: tt.wrap, Style: NewStyleFromConfig(&cfg)})
+			tv.SetContent(tt.line)
+			tv.SetSearchTerm("FOO")
+			if len(tv.display) != len(tt.want) {
+				t.Fatalf("%d angezeigte Zeilen, erwartet %d", len(tv.display), len(tt.want))
+			}
+
+			// Der letzte Treffer als aktueller liegt auf einem der Suchtreffer
+			var last [2]int
+			EachMatch(tt.line, "foo", func(start, end int) { last = [2]int{start, end} })
+			tv.SetCurrentMatch(1, last[0], last[1])
+
+			for i, dl := range tv.display {
+				_, spans, _ := tv.rowSpans(renderKey{line: dl})
+				if got := ranges(spans); got != tt.want[i] {
+					t.Errorf("Zeile %d: Treffer %s, erwartet %s", i, got, tt.want[i])
+				}
+				_, spans, _ = tv.rowSpans(renderKey{line: dl, match: tv.match})
+				if n := len(spans); n > 0 && !strings.Contains(tt.want[i], strings.Trim(ranges(spans[n-1:]), "[]")) {
+					t.Errorf("Zeile %d: aktueller Treffer %v liegt neben den Treffern %s", i, spans[n-1], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// ranges liefert die Bereiche der Spans, z. B. "[{3 6} {10 13}]"
+func ranges(spans []Span) string {
+	type rng struct{ start, end int }
+	r := make([]rng, len(spans))
+	for i, sp := range spans {
+		r[i] = rng{sp.Start, sp.End}
+	}
+	return fmt.Sprint(r)
+}
+
+func TestScrollToMatch(t *testing.T) {
+	long := strings.Repeat("wort ", 60) + "TREFFER " + strings.Repeat("wort ", 20)
+	text := strings.Repeat("kurz\n", 50) + long + "\n" + strings.Repeat("kurz\n", 50)
+	start := strings.Index(long, "TREFFER")
+
+	for _, wrap := range []bool{false, true} {
+		t.Run(fmt.Sprint("Umbruch ", wrap), func(t *testing.T) {
+			tv := newTestView(40, 6)
+			tv.SetWordWrap(wrap)
+			tv.SetContent(text)
+			tv.ScrollToLine(51)
+			if strings.Contains(tv.Render(), "TREFFER") {
+				t.Fatal("Treffer schon ohne ScrollToMatch sichtbar")
+			}
+
+			tv.ScrollToMatch(51, start, start+len("TREFFER"))
+			if got := tv.GetCurrentLine(); got != 51 {
+				t.Errorf("aktuelle Zeile %d, erwartet 51", got)
+			}
+			if row := tv.currentLine - tv.viewport.YOffset; row < 0 || row >= tv.viewport.Height {
+				t.Errorf("aktuelle Zeile %d außerhalb des Bildes ab %d", tv.currentLine, tv.viewport.YOffset)
+			}
+			if !strings.Contains(tv.Render(), "TREFFER") {
+				t.Errorf("Treffer nicht sichtbar:\n%s", tv.Render())
+			}
+
+			// Ein Sprung zu einer Zeile beginnt wieder am Zeilenanfang
+			tv.ScrollToLine(51)
+			if tv.left != 0 || strings.Contains(tv.Render(), "TREFFER") {
+				t.Errorf("Spalte %d nach ScrollToLine", tv.left)
+			}
+		})
+	}
+}
+
+func TestCursorVisible(t *testing.T) {
+	tv := newTestView(40, 6)
+	tv.SetContent(strings.Repeat("x", 100) + "ENDE")
+	tv.SetCursor(1, 104)
+	if !strings.Contains(tv.Render(), "ENDE") {
+		t.Errorf("Zeilenende mit Schreibmarke nicht sichtbar:\n%s", tv.Render())
+	}
+	tv.SetCursor(1, 0)
+	if tv.left != 0 {
+		t.Errorf("Spalte %d mit Schreibmarke am Zeilenanfang", tv.left)
+	}
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/diff"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/ui/components/diffview"
+	"github.com/fase22/tui/internal/ui/components/messages"
+)
+
+// diffSession merkt sich die Buffer eines geöffneten Vergleichs
+type diffSession struct {
+	left    *Buffer // Alte Datei, nebeneinander
+	right   *Buffer // Neue Datei, nebeneinander
+	unified *Buffer // Beide Dateien untereinander
+}
+
+// contains meldet, ob der Buffer zum Vergleich gehört
+func (d *diffSession) contains(b *Buffer) bool {
+	return b == d.left || b == d.right || b == d.unified
+}
+
+// diffLoadedMsg liefert den im Hintergrund berechneten Vergleich
+type diffLoadedMsg struct {
+	oldPath string
+	newPath string
+	left    *diffview.View
+	right   *diffview.View
+	merged  *diffview.View // Beide Dateien untereinander
+	unified bool
+}
+
+type diffErrMsg struct {
+	err error
+}
+
+// NewDiffModel erzeugt ein Model, das zwei Dateien vergleicht
+func NewDiffModel(oldPath, newPath string, unified bool, cfg *config.Config) *Model {
+	m := NewModel(nil, cfg)
+	m.Notify(loadDiff(oldPath, newPath, unified, diffview.NewStyleFromConfig(cfg)))
+	return m
+}
+
+// loadDiff liest beide Dateien ein und vergleicht sie außerhalb von Update,
+// damit große Dateien die Anzeige nicht blockieren
+func loadDiff(oldPath, newPath string, unified bool, style diffview.Style) tea.Cmd {
+	return func() tea.Msg {
+		oldContent, err := file.ReadFile(oldPath)
+		if err != nil {
+			return diffErrMsg{err: err}
+		}
+		newContent, err := file.ReadFile(newPath)
+		if err != nil {
+			return diffErrMsg{err: err}
+		}
+
+		edits := diff.Lines(
+			strings.Split(oldContent, "\n"),
+			strings.Split(newContent, "\n"),
+		)
+		left, right := diffview.SideBySide(edits, style)
+		return diffLoadedMsg{
+			oldPath: oldPath,
+			newPath: newPath,
+			left:    left,
+			right:   right,
+			merged:  diffview.Unified(edits, style),
+			unified: unified,
+		}
+	}
+}
+
+// showDiff zeigt einen geladenen Vergleich an. Die Buffer eines vorherigen
+// Vergleichs werden ersetzt, seine Panes zeigen den neuen.
+func (m *Model) showDiff(msg diffLoadedMsg) tea.Cmd {
+	oldName, newName := filepath.Base(msg.oldPath), filepath.Base(msg.newPath)
+	if oldName == newName {
+		oldName, newName = msg.oldPath, msg.newPath
+	}
+
+	session := &diffSession{
+		left:    newDiffBuffer(oldName, msg.left),
+		right:   newDiffBuffer(newName, msg.right),
+		unified: newDiffBuffer(oldName+" ↔ "+newName, msg.merged),
+	}
+	if old := m.diff; old != nil {
+		m.replaceBuffer(old.left, session.left)
+		m.replaceBuffer(old.right, session.right)
+		m.replaceBuffer(old.unified, session.unified)
+	} else {
+		m.buffers = append(m.buffers, session.left, session.right, session.unified)
+	}
+	m.diff = session
+	m.setDiffMode(msg.unified)
+
+	hunks := len(msg.merged.Hunks())
+	if hunks == 0 {
+		return messages.Info("Keine Unterschiede")
+	}
+	added, removed := msg.merged.Stats()
+	return messages.Info("%d Änderungen, +%d -%d Zeilen", hunks, added, removed)
+}
+
+// replaceBuffer setzt b an die Stelle eines Buffers in der Buffer-Liste
+// und in allen Panes. Ist der alte schon geschlossen, wird b angehängt.
+func (m *Model) replaceBuffer(old, b *Buffer) {
+	replaced := false
+	for i, other := range m.buffers {
+		if other == old {
+			m.buffers[i] = b
+			replaced = true
+		}
+	}
+	if !replaced {
+		m.buffers = append(m.buffers, b)
+	}
+
+	old.stopSearch()
+	for _, p := range m.root.panes() {
+		p.forget(old)
+		if p.buf == old {
+			p.setBuffer(b)
+		}
+	}
+}
+
+// diffPanes liefert die Panes, die einen Buffer des Vergleichs zeigen
+func (m *Model) diffPanes() []*Pane {
+	var panes []*Pane
+	if m.root != nil {
+		for _, p := range m.root.panes() {
+			if m.diff.contains(p.buf) {
+				panes = append(panes, p)
+			}
+		}
+	}
+	return panes
+}
+
+// setDiffMode zeigt den Vergleich untereinander oder in zwei gebundenen
+// Panes nebeneinander an. Dafür werden die Panes des Vergleichs
+// umgestellt, gibt es keine, wird das fokussierte Pane geteilt. Die übrigen
+// Panes bleiben unverändert.
+func (m *Model) setDiffMode(unified bool) {
+	var focus *Pane
+	switch panes := m.diffPanes(); {
+	case len(panes) > 0:
+		focus = panes[0]
+		for _, p := range panes[1:] {
+			m.root.find(p).remove()
+		}
+	case m.root == nil:
+		// Ohne Dateien zeigt das Model nur den Vergleich
+		focus = newPane(m.diff.left, m.config)
+		m.root = newLeaf(focus)
+	default:
+		focus = newPane(m.diff.left, m.config)
+		m.root.find(m.focus).split(splitHorizontal, focus)
+	}
+
+	if unified {
+		focus.setBuffer(m.diff.unified)
+		focus.scrollBind = false
+	} else {
+		focus.setBuffer(m.diff.left)
+		right := newPane(m.diff.right, m.config)
+		focus.scrollBind = true
+		right.scrollBind = true
+		m.root.find(focus).split(splitVertical, right)
+	}
+
+	if m.focus != nil {
+		m.focus.Blur()
+	}
+	m.focus = focus
+	m.focus.Focus()
+	m.resize()
+}
+
+// jumpHunk springt zur nächsten oder vorherigen Änderung eines Diffs bzw.
+// zum nächsten oder vorherigen Hunk eines Patches
+func (m *Model) jumpHunk(forward bool) tea.Cmd {
+	b := m.buf()
+	var hunks []int
+	switch {
+	case b.diff != nil:
+		hunks = b.diff.Hunks()
+	case b.patch != nil:
+		hunks = patchLines(b.patch.Patch().HunkLines())
+	default:
+		return messages.Warn("Kein Diff im aktuellen Buffer")
+	}
+	if len(hunks) == 0 {
+		return messages.Info("Keine Unterschiede")
+	}
+
+	if m.jumpNext(hunks, forward) {
+		if forward {
+			return messages.Info("Letzte Änderung erreicht, weiter am Anfang")
+		}
+		return messages.Info("Erste Änderung erreicht, weiter am Ende")
+	}
+	return nil
+}
+
+func (m *Model) cmdDiff(args string, _ bool) tea.Cmd {
+	paths := strings.Fields(args)
+	if len(paths) != 2 {
+		return messages.Error(fmt.Errorf("Aufruf: :diff <alt> <neu>"))
+	}
+	return loadDiff(paths[0], paths[1], false, diffview.NewStyleFromConfig(m.config))
+}
+
+func (m *Model) cmdDiffMode(args string, _ bool) tea.Cmd {
+	if m.diff == nil {
+		return messages.Error(fmt.Errorf("Kein Diff geöffnet"))
+	}
+
+	switch args {
+	case "":
+		// Ohne Argument zwischen den Darstellungen wechseln
+		m.setDiffMode(m.buf() != m.diff.unified)
+	case "unified":
+		m.setDiffMode(true)
+	case "split":
+		m.setDiffMode(false)
+	default:
+		return messages.Error(fmt.Errorf("Unbekannte Darstellung: %s (unified oder split)", args))
+	}
+	return nil
+}
+
+func (m *Model) completeDiffMode(arg string) []string {
+	return filterPrefix([]string{"split", "unified"}, arg)
+}
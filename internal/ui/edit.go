@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/editor"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// modified meldet, ob der Buffer ungespeicherte Änderungen hat
+func (b *Buffer) modified() bool {
+	return b.doc != nil && b.doc.Modified()
+}
+
+// editable prüft, ob sich ein Buffer bearbeiten und zurückschreiben lässt
+func (b *Buffer) editable() error {
+	switch {
+	case b.state != bufferReady:
+		return fmt.Errorf("%s ist noch nicht geladen", b.Title())
+	case b.path == "" || b.path == "-" || b.parent != nil:
+		return fmt.Errorf("%s hat keine Datei, in die gespeichert werden kann", b.Title())
+	case b.hex || b.binary || b.info.Encoding == file.EncodingAuto:
+		return fmt.Errorf("Binäre Inhalte lassen sich nicht bearbeiten")
+	case b.info.Compression != file.CompressionNone:
+		return fmt.Errorf("Komprimierte Dateien lassen sich nicht bearbeiten")
+	case b.patch != nil:
+		return fmt.Errorf("Patches werden nur angezeigt")
+	case b.mode != modeText:
+		return fmt.Errorf("Bearbeiten geht nur in der Textdarstellung (:mode text)")
+	case b.filter != "":
+		return fmt.Errorf("Bei aktivem Filter lässt sich nicht bearbeiten (:filter hebt ihn auf)")
+	}
+	return nil
+}
+
+// enterInsert wechselt in den Einfügemodus. Der Cursor beginnt in der
+// aktuellen Zeile.
+func (m *Model) enterInsert() tea.Cmd {
+	b := m.buf()
+	if err := b.editable(); err != nil {
+		return messages.Warn("%v", err)
+	}
+	if b.doc == nil {
+		b.doc = editor.New(b.sourceText())
+	}
+	line, col := m.tv().GetCurrentLine()-1, 0
+	if cur := b.doc.Cursor(); cur.Line == line {
+		col = cur.Col
+	}
+	b.doc.SetCursor(line, col)
+	m.mode = ModeInsert
+	m.showCursor(b)
+	return nil
+}
+
+// leaveInsert schließt den Bearbeitungsschritt ab und kehrt in den
+// Normalmodus zurück
+func (m *Model) leaveInsert() {
+	b := m.buf()
+	b.doc.Commit()
+	m.mode = ModeNormal
+	m.tv().ClearCursor()
+	m.refreshContent(b) // Suchtreffer und Marken nachziehen
+}
+
+// updateInsert verarbeitet Tasten im Einfügemodus
+func (m *Model) updateInsert(msg tea.KeyMsg) tea.Cmd {
+	b := m.buf()
+	doc := b.doc
+
+	switch {
+	case key.Matches(msg, m.keys.LeaveInsert), msg.Type == tea.KeyCtrlC:
+		m.leaveInsert()
+		return nil
+	case key.Matches(msg, m.keys.Save):
+		return m.saveBuffer(b)
+	case key.Matches(msg, m.keys.EditUndo):
+		return m.undo(b)
+	case key.Matches(msg, m.keys.EditRedo):
+		return m.redo(b)
+	case key.Matches(msg, m.keys.DeleteLine):
+		doc.DeleteLine()
+	case key.Matches(msg, m.keys.DuplicateLine):
+		doc.DuplicateLine()
+	case key.Matches(msg, m.keys.LineUp):
+		doc.MoveLine(-1)
+	case key.Matches(msg, m.keys.LineDown):
+		doc.MoveLine(1)
+	default:
+		if !m.insertKey(doc, msg) {
+			return nil
+		}
+	}
+	m.applyEdit(b)
+	return nil
+}
+
+// insertKey führt Bewegungen und Eingaben des Einfügemodus aus und meldet,
+// ob sich der Text geändert hat. Bewegungen schließen den
+// Bearbeitungsschritt ab, damit Rückgängig nicht über sie hinweg greift.
+func (m *Model) insertKey(doc *editor.Document, msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyDown, tea.KeyLeft, tea.KeyRight,
+		tea.KeyHome, tea.KeyEnd, tea.KeyPgUp, tea.KeyPgDown:
+		doc.Commit()
+		m.moveCursor(doc, msg.Type)
+		m.showCursor(m.buf())
+		return false
+	case tea.KeyEnter:
+		doc.Newline(m.config.Editor.AutoIndent)
+	case tea.KeyBackspace:
+		doc.Backspace()
+	case tea.KeyDelete:
+		doc.Delete()
+	case tea.KeyTab:
+		doc.Insert("\t")
+	case tea.KeySpace:
+		doc.Insert(" ")
+	case tea.KeyRunes:
+		if msg.Alt {
+			return false
+		}
+		// Eingefügter Text kann Zeilenumbrüche enthalten
+		for i, part := range strings.Split(strings.ReplaceAll(string(msg.Runes), "\r\n", "\n"), "\n") {
+			if i > 0 {
+				doc.Newline(false)
+			}
+			if part != "" {
+				doc.Insert(part)
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// moveCursor bewegt den Cursor für eine Bewegungstaste
+func (m *Model) moveCursor(doc *editor.Document, k tea.KeyType) {
+	page := max(m.tv().GetViewport().Height-1, 1)
+	switch k {
+	case tea.KeyUp:
+		doc.MoveLines(-1)
+	case tea.KeyDown:
+		doc.MoveLines(1)
+	case tea.KeyLeft:
+		doc.MoveCols(-1)
+	case tea.KeyRight:
+		doc.MoveCols(1)
+	case tea.KeyHome:
+		doc.LineStart()
+	case tea.KeyEnd:
+		doc.LineEnd()
+	case tea.KeyPgUp:
+		doc.MoveLines(-page)
+	case tea.KeyPgDown:
+		doc.MoveLines(page)
+	}
+}
+
+// showCursor zeigt den Cursor des Dokuments in der fokussierten Ansicht
+func (m *Model) showCursor(b *Buffer) {
+	cur := b.doc.Cursor()
+	m.tv().SetCursor(cur.Line+1, cur.Col)
+}
+
+// applyEdit überträgt den bearbeiteten Text in den Buffer und alle
+// Ansichten. Aus der Quelle erzeugte Darstellungen werden verworfen.
+func (m *Model) applyEdit(b *Buffer) {
+	b.stale = true
+	b.symbols = nil
+	b.json = nil
+	b.log = nil
+	b.table = nil
+	b.markdown = nil
+	b.close() // Die Hexansicht liest sonst den alten Inhalt
+	marksMoved := m.moveMarks(b, b.doc.Splices())
+
+	// Beim Tippen nur die geänderten Zeilen übernehmen, Suchtreffer und
+	// Marken folgen beim Verlassen des Einfügemodus
+	if m.mode == ModeInsert {
+		b.content = b.doc.Rope()
+		m.eachView(b, func(tv *textview.TextView) {
+			tv.SetText(b.content)
+			tv.SetDecorator("endings", nil) // Gilt nur für die Zeilen der Datei
+		})
+		if marksMoved {
+			m.refreshMarks(b)
+		}
+		m.showCursor(b)
+		return
+	}
+
+	if err := m.setMode(b, b.mode); err != nil {
+		m.setMode(b, modeText)
+	}
+	m.refreshContent(b)
+	if line := displayLine(b, b.doc.Cursor().Line+1); line > 0 {
+		m.jumpToLine(line)
+	}
+}
+
+// undo macht den letzten Bearbeitungsschritt rückgängig
+func (m *Model) undo(b *Buffer) tea.Cmd {
+	if b.doc == nil || !b.doc.Undo() {
+		return messages.Info("Nichts rückgängig zu machen")
+	}
+	m.applyEdit(b)
+	return nil
+}
+
+// redo wiederholt den zuletzt rückgängig gemachten Schritt
+func (m *Model) redo(b *Buffer) tea.Cmd {
+	if b.doc == nil || !b.doc.Redo() {
+		return messages.Info("Nichts zu wiederholen")
+	}
+	m.applyEdit(b)
+	return nil
+}
+
+// saveBuffer schreibt den bearbeiteten Text in Kodierung und Zeilenende der
+// geladenen Datei zurück
+func (m *Model) saveBuffer(b *Buffer) tea.Cmd {
+	if !b.modified() {
+		return messages.Info("%s ist unverändert", b.Title())
+	}
+	if err := file.WriteFileInfo(b.path, b.doc.Text(), b.info); err != nil {
+		return messages.Error(err)
+	}
+	b.doc.MarkSaved()
+	b.close() // Die Hexansicht zeigt die gespeicherte Datei
+	if stat, err := os.Stat(b.path); err == nil {
+		b.info.Size = stat.Size()
+		b.info.RawSize = stat.Size()
+	}
+
+	note := ""
+	if b.info.LineEndings.Mixed() {
+		// Gemischte Zeilenenden wurden beim Schreiben vereinheitlicht
+		b.info.LineEndings.Deviations = nil
+		m.refreshContent(b)
+		note = ", Zeilenenden vereinheitlicht auf " + b.info.LineEndings.Style.String()
+	}
+	return messages.Info("%s gespeichert, %d Zeilen%s", b.Title(), b.doc.LineCount(), note)
+}
+
+// modifiedBuffers liefert die Namen aller Buffer mit ungespeicherten
+// Änderungen
+func (m *Model) modifiedBuffers() []string {
+	var names []string
+	for _, b := range m.buffers {
+		if b.modified() {
+			names = append(names, b.Title())
+		}
+	}
+	return names
+}
+
+// quit beendet das Programm. Gibt es ungespeicherte Änderungen, muss das
+// mit force bestätigt werden (:q!).
+func (m *Model) quit(force bool) tea.Cmd {
+	if names := m.modifiedBuffers(); len(names) > 0 && !force {
+		return messages.Warn("Ungespeicherte Änderungen in %s (:w speichert, :q! beendet ohne Speichern)",
+			strings.Join(names, ", "))
+	}
+	return tea.Quit
+}
+
+// cmdWriteQuit speichert den aktuellen Buffer und beendet das Programm
+func (m *Model) cmdWriteQuit(_ string, force bool) tea.Cmd {
+	b := m.buf()
+	if cmd := m.saveBuffer(b); b.modified() {
+		return cmd
+	}
+	return m.quit(force)
+}
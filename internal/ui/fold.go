@@ -0,0 +1,52 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/ui/components/messages"
+)
+
+// foldAction ist ein Befehl zum Auf- und Zuklappen
+type foldAction int
+
+const (
+	foldToggle foldAction = iota
+	foldClose
+	foldOpen
+	foldCloseAll
+	foldOpenAll
+)
+
+// fold führt einen Klappbefehl in der aktuellen Darstellung aus
+func (m *Model) fold(action foldAction) tea.Cmd {
+	b := m.buf()
+	if b.state != bufferReady || b.hex {
+		return nil
+	}
+	if b.mode == modeJSON {
+		return m.foldJSON(action)
+	}
+	return m.foldText(action)
+}
+
+// foldText klappt Bereiche der Textansicht nach Einrückung und Klammern auf
+// oder zu
+func (m *Model) foldText(action foldAction) tea.Cmd {
+	tv := m.tv()
+	var ok bool
+	switch action {
+	case foldToggle:
+		ok = tv.ToggleFold()
+	case foldClose:
+		ok = tv.CloseFold()
+	case foldOpen:
+		ok = tv.OpenFold()
+	case foldCloseAll:
+		ok = tv.CloseAllFolds()
+	case foldOpenAll:
+		ok = tv.OpenAllFolds()
+	}
+	if !ok {
+		return messages.Warn("Hier gibt es nichts zu klappen")
+	}
+	return nil
+}
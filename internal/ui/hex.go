@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/ui/components/hexview"
+	"github.com/fase22/tui/internal/ui/components/messages"
+)
+
+type hexMatchMsg struct {
+	buf     *Buffer
+	offset  int64 // -1 ohne Treffer
+	wrapped bool
+	forward bool
+}
+
+// hex liefert die Hexansicht des fokussierten Panes
+func (m *Model) hex() *hexview.HexView {
+	return m.focus.hexView()
+}
+
+// eachHexView ruft fn für alle Hexansichten eines Buffers in allen Panes auf
+func (m *Model) eachHexView(b *Buffer, fn func(hv *hexview.HexView)) {
+	for _, p := range m.root.panes() {
+		p.eachHexView(b, fn)
+	}
+}
+
+// toggleHex wechselt zwischen Text- und Hexansicht des fokussierten Buffers
+func (m *Model) toggleHex() tea.Cmd {
+	b := m.buf()
+	if b.state != bufferReady {
+		return nil
+	}
+
+	b.hex = !b.hex
+	if b.hex {
+		// Position aus der Textansicht ungefähr übernehmen
+		m.hex().GotoOffset(m.lineOffset(m.tv().GetCurrentLine()))
+		return messages.Info("Hexansicht")
+	}
+
+	// Binärdateien wurden für die Hexansicht nicht vollständig gelesen
+	if b.binary && b.sourceText() == "" {
+		b.state = bufferLoading
+		return tea.Batch(b.loadText(), messages.Info("Textansicht einer Binärdatei"))
+	}
+	return messages.Info("Textansicht")
+}
+
+// lineOffset liefert den Byte-Offset einer Zeile (1-basiert) in den Bytes
+// der Hexansicht. Gezählt werden die Zeilenumbrüche dort, denn Kodierung
+// und Zeilenenden der Datei können vom Text abweichen. In anderen
+// Darstellungen als Text passen die Zeilen nicht zur Datei.
+func (m *Model) lineOffset(line int) int64 {
+	b := m.buf()
+	if b.mode != modeText {
+		return 0
+	}
+	pager := b.hexPager()
+	chunk := make([]byte, 64<<10)
+	offset := int64(0)
+	for pos := int64(0); line > 1 && pos < pager.Size(); {
+		n, err := pager.ReadAt(chunk, pos)
+		data := chunk[:n]
+		for line > 1 {
+			next := bytes.IndexByte(data, '\n')
+			if next < 0 {
+				break
+			}
+			offset = pos + int64(n-len(data)+next) + 1
+			data = data[next+1:]
+			line--
+		}
+		if err != nil {
+			break
+		}
+		pos += int64(n)
+	}
+
+	// In Little Endian folgen auf '\n' noch die übrigen Bytes des Zeichens
+	switch {
+	case offset == 0:
+	case b.info.Encoding == file.EncodingUTF16LE:
+		offset++
+	case b.info.Encoding == file.EncodingUTF32LE:
+		offset += 3
+	}
+	return offset
+}
+
+// updateHex verarbeitet die Bewegungstasten in der Hexansicht und meldet,
+// ob die Taste behandelt wurde
+func (m *Model) updateHex(keys keySequence) (tea.Cmd, bool) {
+	hv := m.hex()
+	b := m.buf()
+
+	switch {
+	case key.Matches(keys, m.keys.Up):
+		hv.ScrollUp(1)
+	case key.Matches(keys, m.keys.Down):
+		hv.ScrollDown(1)
+	case key.Matches(keys, m.keys.PageUp):
+		hv.ScrollUp(hv.Height())
+	case key.Matches(keys, m.keys.PageDown):
+		hv.ScrollDown(hv.Height())
+	case key.Matches(keys, m.keys.Top):
+		hv.ScrollToTop()
+	case key.Matches(keys, m.keys.Bottom):
+		hv.ScrollToBottom()
+	case key.Matches(keys, m.keys.NextMatch):
+		if len(b.hexPattern) == 0 {
+			return nil, true
+		}
+		return m.searchHex(b, hv.Offset()+1, true), true
+	case key.Matches(keys, m.keys.PrevMatch):
+		if len(b.hexPattern) == 0 {
+			return nil, true
+		}
+		return m.searchHex(b, hv.Offset(), false), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// parseHexPattern liest eine Bytefolge wie "de ad be ef" oder "0xdeadbeef"
+func parseHexPattern(query string) ([]byte, error) {
+	var digits strings.Builder
+	for _, field := range strings.Fields(query) {
+		field = strings.TrimPrefix(strings.ToLower(field), "0x")
+		digits.WriteString(field)
+	}
+
+	pattern, err := hex.DecodeString(digits.String())
+	if err != nil || len(pattern) == 0 {
+		return nil, fmt.Errorf("Ungültige Bytefolge: %q (z. B. de ad be ef)", query)
+	}
+	return pattern, nil
+}
+
+// startHexSearch sucht die Eingabe des Suchmodus als Bytefolge
+func (m *Model) startHexSearch(b *Buffer) tea.Cmd {
+	pattern, err := parseHexPattern(b.searchQuery)
+	if err != nil {
+		return messages.Error(err)
+	}
+	b.hexPattern = pattern
+	return m.searchHex(b, m.hex().Offset(), true)
+}
+
+// searchHex sucht die Bytefolge des Buffers ab bzw. vor einem Offset und
+// setzt die Suche am anderen Ende fort
+func (m *Model) searchHex(b *Buffer, from int64, forward bool) tea.Cmd {
+	pager := b.hexPager()
+	pattern := b.hexPattern
+
+	return func() tea.Msg {
+		var offset int64
+		var err error
+		wrapped := false
+
+		if forward {
+			offset, err = pager.Index(pattern, from)
+			if err == nil && offset < 0 && from > 0 {
+				offset, err = pager.Index(pattern, 0)
+				wrapped = true
+			}
+		} else {
+			offset, err = pager.LastIndex(pattern, from)
+			if err == nil && offset < 0 && from < pager.Size() {
+				offset, err = pager.LastIndex(pattern, pager.Size())
+				wrapped = true
+			}
+		}
+		if err != nil {
+			return messages.Error(err)()
+		}
+		return hexMatchMsg{buf: b, offset: offset, wrapped: wrapped, forward: forward}
+	}
+}
+
+// showHexMatch markiert einen Treffer der Bytesuche
+func (m *Model) showHexMatch(msg hexMatchMsg) tea.Cmd {
+	b := msg.buf
+	if msg.offset < 0 {
+		m.eachHexView(b, func(hv *hexview.HexView) {
+			hv.SetMatch(0, 0)
+		})
+		return messages.Warn("Keine Treffer für %q", b.searchQuery)
+	}
+
+	length := int64(len(b.hexPattern))
+	m.eachHexView(b, func(hv *hexview.HexView) {
+		hv.SetMatch(msg.offset, length)
+	})
+	if b == m.buf() {
+		m.hex().GotoOffset(msg.offset)
+	}
+
+	switch {
+	case msg.wrapped && msg.forward:
+		return messages.Info("Suche am Ende angelangt, weiter am Anfang")
+	case msg.wrapped:
+		return messages.Info("Suche am Anfang angelangt, weiter am Ende")
+	}
+	return messages.Info("Treffer bei Offset 0x%x", msg.offset)
+}
+
+// parseOffset liest einen Offset dezimal oder mit Präfix 0x, 0o bzw. 0b
+func parseOffset(s string) (int64, bool) {
+	offset, err := strconv.ParseInt(strings.TrimSpace(s), 0, 64)
+	return offset, err == nil && offset >= 0
+}
+
+// gotoOffset springt in der Hexansicht zu einem Offset
+func (m *Model) gotoOffset(args string) tea.Cmd {
+	offset, ok := parseOffset(args)
+	if !ok {
+		return messages.Error(fmt.Errorf("Ungültiger Offset: %s", args))
+	}
+	hv := m.hex()
+	if offset >= hv.Size() {
+		return messages.Error(fmt.Errorf("Offset 0x%x liegt hinter dem Dateiende (0x%x)", offset, hv.Size()))
+	}
+	hv.GotoOffset(offset)
+	return nil
+}
+
+func (m *Model) cmdHex(_ string, _ bool) tea.Cmd {
+	return m.toggleHex()
+}
@@ -6,7 +6,21 @@ type Renderer interface {
 	Render() string
 }
 
+// Focusable wird von Komponenten erfüllt, die den Tastaturfokus erhalten können
+type Focusable interface {
+	Focus()
+	Blur()
+	Focused() bool
+}
+
+// Sizer wird von Komponenten erfüllt, deren Größe das Layout vorgibt
+type Sizer interface {
+	SetSize(width, height int)
+}
+
 type Component interface {
 	Renderer
+	Focusable
+	Sizer
 	Update(msg tea.Msg) (tea.Model, tea.Cmd)
 }
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/fase22/tui/internal/ui/components/jsonview"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/muesli/termenv"
+)
+
+// foldJSON klappt Knoten des JSON-Baums auf oder zu. Der Cursor bleibt auf
+// dem betroffenen Knoten.
+func (m *Model) foldJSON(action foldAction) tea.Cmd {
+	b := m.buf()
+	line := m.tv().GetCurrentLine()
+
+	switch action {
+	case foldToggle:
+		line = b.json.Toggle(line)
+	case foldClose:
+		line = b.json.Collapse(line)
+	case foldOpen:
+		line = b.json.Expand(line)
+	case foldCloseAll:
+		line = b.json.CollapseAll(line)
+	case foldOpenAll:
+		line = b.json.ExpandAll(line)
+	}
+
+	b.content = rope.New(b.json.Content())
+	m.refreshContent(b)
+	if line != m.tv().GetCurrentLine() {
+		m.jumpToLine(line)
+	}
+	return nil
+}
+
+// copyJSONPath kopiert den Pfad des Knotens unter dem Cursor per OSC 52 in
+// die Zwischenablage des Terminals. Bubble Tea kann keine eigenen Sequenzen
+// ausgeben, sie geht daher direkt ans Terminal und ist nicht mit dem
+// Zeichnen abgestimmt. Sie wird in einem Stück geschrieben und bewegt den
+// Cursor nicht, ein Bild stört sie nur, wenn sie in eine seiner
+// Steuersequenzen fällt.
+func (m *Model) copyJSONPath() tea.Cmd {
+	b := m.buf()
+	if b.mode != modeJSON || b.hex {
+		return messages.Warn("Kein JSON-Knoten unter dem Cursor")
+	}
+	node := b.json.NodeAt(m.tv().GetCurrentLine())
+	if node == nil {
+		return nil
+	}
+
+	path := jsonview.Path(node)
+	return func() tea.Msg {
+		termenv.Copy(path)
+		return messages.Info("Kopiert: %s", path)()
+	}
+}
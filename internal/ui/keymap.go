@@ -0,0 +1,429 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/ui/components/helpview"
+)
+
+// KeyMap enthält alle Tastenbelegungen. Die Belegungen des Normalmodus
+// stammen aus config.Keybindings, die übrigen sind fest.
+type KeyMap struct {
+	// Normalmodus
+	Up         key.Binding
+	Down       key.Binding
+	PageUp     key.Binding
+	PageDown   key.Binding
+	Top        key.Binding
+	Bottom     key.Binding
+	Search     key.Binding
+	NextMatch  key.Binding
+	PrevMatch  key.Binding
+	StopSearch key.Binding
+	Command    key.Binding
+	NextBuffer key.Binding
+	PrevBuffer key.Binding
+	NextHunk   key.Binding
+	PrevHunk   key.Binding
+	NextFile   key.Binding
+	PrevFile   key.Binding
+	Files      key.Binding
+	Outline    key.Binding
+	SetMark    key.Binding
+	JumpMark   key.Binding
+	Hex        key.Binding
+	Open       key.Binding
+	ToggleMode key.Binding
+	ToggleWrap key.Binding
+
+	// Auf- und Zuklappen
+	FoldToggle   key.Binding
+	FoldClose    key.Binding
+	FoldOpen     key.Binding
+	FoldCloseAll key.Binding
+	FoldOpenAll  key.Binding
+	CopyPath     key.Binding
+
+	// Tabellen
+	ColumnLeft  key.Binding
+	ColumnRight key.Binding
+	MoveLeft    key.Binding
+	MoveRight   key.Binding
+	Sort        key.Binding
+	HideColumn  key.Binding
+
+	// Panes
+	Split      key.Binding
+	VSplit     key.Binding
+	NextPane   key.Binding
+	PaneLeft   key.Binding
+	PaneDown   key.Binding
+	PaneUp     key.Binding
+	PaneRight  key.Binding
+	GrowPane   key.Binding
+	ShrinkPane key.Binding
+	ClosePane  key.Binding
+	ScrollBind key.Binding
+
+	// Bearbeiten
+	Insert key.Binding
+	Undo   key.Binding
+	Redo   key.Binding
+	Save   key.Binding
+
+	ToggleLines key.Binding
+	Messages    key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+
+	// Einfügemodus
+	DeleteLine    key.Binding
+	DuplicateLine key.Binding
+	LineUp        key.Binding
+	LineDown      key.Binding
+	EditUndo      key.Binding
+	EditRedo      key.Binding
+	LeaveInsert   key.Binding
+
+	// Such- und Befehlsmodus
+	Submit      key.Binding
+	Cancel      key.Binding
+	Complete    key.Binding
+	HistoryPrev key.Binding
+	HistoryNext key.Binding
+
+	// Hilfe und Meldungsprotokoll
+	Close        key.Binding
+	FilterHelp   key.Binding
+	ScrollUp     key.Binding
+	ScrollDown   key.Binding
+	ScrollPageUp key.Binding
+	ScrollPageDn key.Binding
+}
+
+// NewKeyMap erzeugt die Tastenbelegung aus der Konfiguration
+func NewKeyMap(cfg *config.Config) KeyMap {
+	kb := cfg.Keybindings
+
+	return KeyMap{
+		Up:         binding(kb.UpKey, "Eine Zeile nach oben"),
+		Down:       binding(kb.DownKey, "Eine Zeile nach unten"),
+		PageUp:     binding(kb.PageUpKey, "Seitenweise nach oben"),
+		PageDown:   binding(kb.PageDownKey, "Seitenweise nach unten"),
+		Top:        binding(kb.TopKey, "Zum Anfang"),
+		Bottom:     binding(kb.BottomKey, "Zum Ende"),
+		Search:     binding(kb.SearchKey, "Suche starten"),
+		NextMatch:  binding(kb.NextMatchKey, "Nächster Treffer"),
+		PrevMatch:  binding(kb.PrevMatchKey, "Vorheriger Treffer"),
+		StopSearch: binding("esc", "Laufende Suche abbrechen"),
+		Command:    binding(kb.CommandKey, "Befehlsmodus"),
+		NextBuffer: binding(kb.NextBufferKey, "Nächster Buffer"),
+		PrevBuffer: binding(kb.PrevBufferKey, "Vorheriger Buffer"),
+		NextHunk:   binding(kb.NextHunkKey, "Nächste Änderung / nächster Hunk"),
+		PrevHunk:   binding(kb.PrevHunkKey, "Vorherige Änderung / vorheriger Hunk"),
+		NextFile:   binding(kb.NextFileKey, "Nächste Datei im Patch"),
+		PrevFile:   binding(kb.PrevFileKey, "Vorherige Datei im Patch"),
+		Files:      binding(kb.FilesKey, "Dateiliste des Patches"),
+		Outline:    binding(kb.OutlineKey, "Gliederung"),
+		SetMark:    binding(kb.SetMarkKey, "Marke setzen (a-z, A-Z global)"),
+		JumpMark:   binding(kb.JumpMarkKey, "Zu Marke springen"),
+		Hex:        binding(kb.HexKey, "Text- / Hexansicht umschalten"),
+		Open:       binding(kb.OpenKey, "Archiveintrag öffnen / Knoten umschalten / Details"),
+		ToggleMode: binding(kb.ToggleModeKey, "Darstellung umschalten (Text / erkannte Darstellung)"),
+		ToggleWrap: binding(kb.ToggleWrapKey, "Zeilenumbruch des Buffers umschalten"),
+
+		FoldToggle:   binding(kb.FoldToggleKey, "Knoten bzw. Bereich auf- / zuklappen"),
+		FoldClose:    binding(kb.FoldCloseKey, "Knoten bzw. Bereich zuklappen"),
+		FoldOpen:     binding(kb.FoldOpenKey, "Knoten bzw. Bereich aufklappen"),
+		FoldCloseAll: binding(kb.FoldCloseAllKey, "Alles zuklappen"),
+		FoldOpenAll:  binding(kb.FoldOpenAllKey, "Alles aufklappen"),
+		CopyPath:     binding(kb.CopyPathKey, "JSON-Pfad kopieren"),
+
+		ColumnLeft:  binding(kb.ColumnLeftKey, "Spalte links"),
+		ColumnRight: binding(kb.ColumnRightKey, "Spalte rechts"),
+		MoveLeft:    binding(kb.MoveLeftKey, "Spalte nach links verschieben"),
+		MoveRight:   binding(kb.MoveRightKey, "Spalte nach rechts verschieben"),
+		Sort:        binding(kb.SortKey, "Nach Spalte sortieren"),
+		HideColumn:  binding(kb.HideColumnKey, "Spalte ausblenden"),
+
+		Split:      binding(kb.SplitKey, "Pane horizontal teilen"),
+		VSplit:     binding(kb.VSplitKey, "Pane vertikal teilen"),
+		NextPane:   binding(kb.NextPaneKey, "Nächstes Pane"),
+		PaneLeft:   binding(kb.PaneLeftKey, "Pane links"),
+		PaneDown:   binding(kb.PaneDownKey, "Pane unten"),
+		PaneUp:     binding(kb.PaneUpKey, "Pane oben"),
+		PaneRight:  binding(kb.PaneRightKey, "Pane rechts"),
+		GrowPane:   binding(kb.GrowPaneKey, "Pane vergrößern"),
+		ShrinkPane: binding(kb.ShrinkPaneKey, "Pane verkleinern"),
+		ClosePane:  binding(kb.ClosePaneKey, "Pane schließen"),
+		ScrollBind: binding(kb.ScrollBindKey, "Scrollbindung umschalten"),
+
+		Insert: binding(kb.InsertKey, "Einfügemodus (bearbeiten)"),
+		Undo:   binding(kb.UndoKey, "Rückgängig"),
+		Redo:   binding(kb.RedoKey, "Wiederholen"),
+		Save:   binding(kb.SaveKey, "Speichern"),
+
+		ToggleLines: binding(kb.ToggleLinesKey, "Zeilennummern des Buffers umschalten"),
+		Messages:    binding(kb.MessagesKey, "Meldungsprotokoll"),
+		Help:        binding(kb.HelpKey, "Hilfe anzeigen"),
+		Quit:        binding(kb.QuitKey+",ctrl+c", "Beenden"),
+
+		DeleteLine:    binding("ctrl+k", "Zeile löschen"),
+		DuplicateLine: binding("ctrl+d", "Zeile verdoppeln"),
+		LineUp:        binding("alt+up", "Zeile nach oben verschieben"),
+		LineDown:      binding("alt+down", "Zeile nach unten verschieben"),
+		EditUndo:      binding("ctrl+z", "Rückgängig"),
+		EditRedo:      binding("ctrl+y", "Wiederholen"),
+		LeaveInsert:   binding("esc", "Einfügemodus verlassen"),
+
+		Submit:      binding("enter", "Ausführen"),
+		Cancel:      binding("esc", "Abbrechen"),
+		Complete:    binding("tab,shift+tab", "Vervollständigen"),
+		HistoryPrev: binding("up", "Vorheriger Befehl"),
+		HistoryNext: binding("down", "Nächster Befehl"),
+
+		Close:        binding("esc,q", "Schließen"),
+		FilterHelp:   binding("/", "Filtern"),
+		ScrollUp:     binding("up,k", "Nach oben"),
+		ScrollDown:   binding("down,j", "Nach unten"),
+		ScrollPageUp: binding("pgup", "Seite nach oben"),
+		ScrollPageDn: binding("pgdown", "Seite nach unten"),
+	}
+}
+
+// binding erzeugt eine Belegung aus einer kommagetrennten Tastenliste.
+// Eine leere Liste deaktiviert die Aktion.
+func binding(keys string, desc string) key.Binding {
+	var list []string
+	for _, k := range strings.Split(keys, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			list = append(list, k)
+		}
+	}
+	if len(list) == 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+
+	labels := make([]string, len(list))
+	for i, k := range list {
+		labels[i] = keyLabel(k)
+	}
+	return key.NewBinding(
+		key.WithKeys(list...),
+		key.WithHelp(strings.Join(labels, "/"), desc),
+	)
+}
+
+// keyLabel macht Tastennamen für die Hilfe lesbarer
+func keyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "pgup":
+		return "PgUp"
+	case "pgdown":
+		return "PgDn"
+	case "enter":
+		return "Enter"
+	case "esc":
+		return "Esc"
+	case "tab":
+		return "Tab"
+	case "shift+tab":
+		return "Shift+Tab"
+	case "home":
+		return "Pos1"
+	case "end":
+		return "Ende"
+	case " ":
+		return "Leertaste"
+	}
+	if strings.Contains(k, " ") {
+		parts := strings.Fields(k)
+		for i, part := range parts {
+			parts[i] = keyLabel(part)
+		}
+		return strings.Join(parts, " ")
+	}
+	if rest, ok := strings.CutPrefix(k, "ctrl+"); ok {
+		return "^" + strings.ToUpper(rest)
+	}
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok {
+		return "Alt+" + keyLabel(rest)
+	}
+	return k
+}
+
+// keySequence ist eine Folge von Tasten wie "gt" oder "ctrl+x s", die
+// key.Matches wie eine einzelne Taste vergleicht
+type keySequence []string
+
+func (s keySequence) String() string {
+	// Folgen mit benannten Tasten werden durch Leerzeichen getrennt
+	for _, k := range s {
+		if len([]rune(k)) > 1 {
+			return strings.Join(s, " ")
+		}
+	}
+	return strings.Join(s, "")
+}
+
+// namedKeys sind Tastennamen aus mehreren Zeichen, die keine Tastenfolge sind
+var namedKeys = map[string]bool{
+	"up": true, "down": true, "left": true, "right": true,
+	"pgup": true, "pgdown": true, "home": true, "end": true,
+	"enter": true, "esc": true, "tab": true, "backspace": true,
+	"delete": true, "insert": true, "space": true,
+}
+
+// splitSequence zerlegt eine Belegung in einzelne Tasten. "gt" ergibt
+// ["g", "t"], "ctrl+x s" ergibt ["ctrl+x", "s"], benannte Tasten wie "pgup"
+// oder "ctrl+w" bleiben ganz.
+func splitSequence(k string) []string {
+	if k != " " && strings.Contains(k, " ") {
+		return strings.Fields(k)
+	}
+	if len([]rune(k)) < 2 || namedKeys[k] || strings.Contains(k, "+") ||
+		(strings.HasPrefix(k, "f") && len(k) <= 3 && strings.Trim(k[1:], "0123456789") == "") {
+		return []string{k}
+	}
+
+	var keys []string
+	for _, r := range k {
+		keys = append(keys, string(r))
+	}
+	return keys
+}
+
+// isPrefix meldet, ob die getippten Tasten der Anfang einer längeren
+// Tastenfolge des Normalmodus sind
+func (k KeyMap) isPrefix(typed []string) bool {
+	for _, b := range append(k.normalBindings(), k.paneBindings()...) {
+		if !b.Enabled() {
+			continue
+		}
+		for _, bound := range b.Keys() {
+			seq := splitSequence(bound)
+			if len(seq) <= len(typed) {
+				continue
+			}
+			match := true
+			for i := range typed {
+				if seq[i] != typed[i] {
+					match = false
+					break
+				}
+			}
+			if match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ShortHelp erfüllt help.KeyMap für die Kurzhilfe
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Search, k.Quit}
+}
+
+// FullHelp erfüllt help.KeyMap und liefert eine Spalte pro Modus
+func (k KeyMap) FullHelp() [][]key.Binding {
+	var columns [][]key.Binding
+	for _, group := range k.Groups() {
+		columns = append(columns, group.Bindings)
+	}
+	return columns
+}
+
+// Groups liefert alle Belegungen nach Modus gruppiert für die Hilfeansicht
+func (k KeyMap) Groups() []helpview.Group {
+	return []helpview.Group{
+		{
+			Title:    "Normalmodus",
+			Bindings: k.normalBindings(),
+		},
+		{
+			Title:    "Panes",
+			Bindings: k.paneBindings(),
+		},
+		{
+			Title:    "Einfügemodus",
+			Bindings: k.insertBindings(),
+		},
+		{
+			Title:    "Suchmodus",
+			Bindings: []key.Binding{k.Submit, k.Cancel},
+		},
+		{
+			Title:    "Befehlsmodus",
+			Bindings: []key.Binding{k.Submit, k.Cancel, k.Complete, k.HistoryPrev, k.HistoryNext},
+		},
+		{
+			Title:    "Dateiliste",
+			Bindings: []key.Binding{k.ScrollUp, k.ScrollDown, k.Submit, k.Close},
+		},
+		{
+			Title: "Hilfe und Meldungen",
+			Bindings: []key.Binding{
+				k.ScrollUp, k.ScrollDown, k.ScrollPageUp, k.ScrollPageDn, k.FilterHelp, k.Close,
+			},
+		},
+	}
+}
+
+// statusHints fasst die Kurzhilfe als Text für die Statusleiste zusammen
+func (k KeyMap) statusHints() string {
+	var hints []string
+	for _, b := range k.ShortHelp() {
+		if b.Enabled() {
+			hints = append(hints, fmt.Sprintf("%s: %s", b.Help().Key, b.Help().Desc))
+		}
+	}
+	return strings.Join(hints, " | ")
+}
+
+// normalBindings liefert alle Belegungen des Normalmodus
+func (k KeyMap) normalBindings() []key.Binding {
+	return []key.Binding{
+		k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom,
+		k.Search, k.NextMatch, k.PrevMatch, k.StopSearch, k.Command,
+		k.NextBuffer, k.PrevBuffer, k.NextHunk, k.PrevHunk, k.NextFile, k.PrevFile, k.Files,
+		k.Outline, k.SetMark, k.JumpMark, k.Hex, k.Open, k.ToggleMode,
+		k.FoldToggle, k.FoldClose, k.FoldOpen, k.FoldCloseAll, k.FoldOpenAll, k.CopyPath,
+		k.ColumnLeft, k.ColumnRight, k.MoveLeft, k.MoveRight, k.Sort, k.HideColumn,
+		k.Insert, k.Undo, k.Redo, k.Save,
+		k.ToggleWrap, k.ToggleLines, k.Messages, k.Help, k.Quit,
+	}
+}
+
+// insertBindings liefert die Belegungen des Einfügemodus. Zeichen werden
+// eingefügt, Pfeiltasten, Pos1 und Ende bewegen den Cursor.
+func (k KeyMap) insertBindings() []key.Binding {
+	return []key.Binding{
+		k.DeleteLine, k.DuplicateLine, k.LineUp, k.LineDown,
+		k.EditUndo, k.EditRedo, k.Save, k.LeaveInsert,
+	}
+}
+
+// paneBindings liefert alle Belegungen zum Teilen und Wechseln von Panes
+func (k KeyMap) paneBindings() []key.Binding {
+	return []key.Binding{
+		k.Split, k.VSplit, k.NextPane, k.PaneLeft, k.PaneDown, k.PaneUp, k.PaneRight,
+		k.GrowPane, k.ShrinkPane, k.ClosePane, k.ScrollBind,
+	}
+}
+
+// overlayHelp liefert die Kurzhilfe für Hilfe und Meldungsprotokoll
+func (k KeyMap) overlayHelp() []key.Binding {
+	return []key.Binding{k.ScrollDown, k.ScrollUp, k.FilterHelp, k.Close}
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type splitDir int
+
+const (
+	splitNone       splitDir = iota
+	splitHorizontal          // Panes übereinander
+	splitVertical            // Panes nebeneinander
+)
+
+// direction gibt die Richtung für die Fokusnavigation an
+type direction int
+
+const (
+	dirLeft direction = iota
+	dirDown
+	dirUp
+	dirRight
+)
+
+const (
+	minRatio  = 0.1
+	maxRatio  = 0.9
+	ratioStep = 0.05
+)
+
+// layoutNode ist ein Knoten im Split-Baum. Blätter enthalten ein Pane,
+// innere Knoten teilen ihre Fläche im Verhältnis ratio auf zwei Kinder auf.
+type layoutNode struct {
+	pane          *Pane
+	dir           splitDir
+	ratio         float64
+	first, second *layoutNode
+	parent        *layoutNode
+	width, height int
+}
+
+func newLeaf(p *Pane) *layoutNode {
+	return &layoutNode{pane: p}
+}
+
+func (n *layoutNode) isLeaf() bool {
+	return n.pane != nil
+}
+
+// panes liefert alle Panes in Lesereihenfolge
+func (n *layoutNode) panes() []*Pane {
+	if n.isLeaf() {
+		return []*Pane{n.pane}
+	}
+	return append(n.first.panes(), n.second.panes()...)
+}
+
+// find sucht das Blatt eines Panes
+func (n *layoutNode) find(p *Pane) *layoutNode {
+	if n.isLeaf() {
+		if n.pane == p {
+			return n
+		}
+		return nil
+	}
+	if found := n.first.find(p); found != nil {
+		return found
+	}
+	return n.second.find(p)
+}
+
+// split teilt das Blatt und setzt das neue Pane als zweites Kind ein
+func (n *layoutNode) split(dir splitDir, p *Pane) {
+	n.first = &layoutNode{pane: n.pane, parent: n}
+	n.second = &layoutNode{pane: p, parent: n}
+	n.pane = nil
+	n.dir = dir
+	n.ratio = 0.5
+}
+
+// remove entfernt ein Blatt, sein Geschwister nimmt den Platz des Elternknotens ein
+func (n *layoutNode) remove() {
+	parent := n.parent
+	if parent == nil {
+		return
+	}
+
+	sibling := parent.first
+	if sibling == n {
+		sibling = parent.second
+	}
+
+	parent.pane = sibling.pane
+	parent.dir = sibling.dir
+	parent.ratio = sibling.ratio
+	parent.first = sibling.first
+	parent.second = sibling.second
+	if parent.first != nil {
+		parent.first.parent = parent
+		parent.second.parent = parent
+	}
+}
+
+// grow vergrößert (delta > 0) oder verkleinert das Blatt innerhalb seines Splits
+func (n *layoutNode) grow(delta float64) bool {
+	parent := n.parent
+	if parent == nil {
+		return false
+	}
+	if parent.second == n {
+		delta = -delta
+	}
+	parent.ratio = min(max(parent.ratio+delta, minRatio), maxRatio)
+	return true
+}
+
+// layout verteilt die Fläche auf alle Panes
+func (n *layoutNode) layout(x, y, width, height int, showTitles bool) {
+	n.width = width
+	n.height = height
+
+	if n.isLeaf() {
+		n.pane.x = x
+		n.pane.y = y
+		n.pane.showTitle = showTitles
+		n.pane.SetSize(width, height)
+		return
+	}
+
+	switch n.dir {
+	case splitVertical:
+		// Eine Spalte für den Trenner reservieren
+		firstWidth := max(int(float64(width-1)*n.ratio), 1)
+		n.first.layout(x, y, firstWidth, height, showTitles)
+		n.second.layout(x+firstWidth+1, y, max(width-1-firstWidth, 1), height, showTitles)
+	default:
+		firstHeight := max(int(float64(height)*n.ratio), 1)
+		n.first.layout(x, y, width, firstHeight, showTitles)
+		n.second.layout(x, y+firstHeight, width, max(height-firstHeight, 1), showTitles)
+	}
+}
+
+func (n *layoutNode) render(separator lipgloss.Style) string {
+	if n.isLeaf() {
+		return n.pane.Render()
+	}
+
+	first := n.first.render(separator)
+	second := n.second.render(separator)
+	if n.dir == splitVertical {
+		sep := strings.TrimSuffix(strings.Repeat(separator.Render("│")+"\n", n.height), "\n")
+		return lipgloss.JoinHorizontal(lipgloss.Top, first, sep, second)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, first, second)
+}
+
+// neighbor sucht das nächstgelegene Pane in der angegebenen Richtung
+func neighbor(panes []*Pane, from *Pane, dir direction) *Pane {
+	var best *Pane
+	bestDist := -1
+
+	for _, p := range panes {
+		if p == from {
+			continue
+		}
+
+		var dist int
+		var overlaps bool
+		switch dir {
+		case dirLeft:
+			dist = from.x - (p.x + p.width)
+			overlaps = p.y < from.y+from.height && from.y < p.y+p.height
+		case dirRight:
+			dist = p.x - (from.x + from.width)
+			overlaps = p.y < from.y+from.height && from.y < p.y+p.height
+		case dirUp:
+			dist = from.y - (p.y + p.height)
+			overlaps = p.x < from.x+from.width && from.x < p.x+p.width
+		case dirDown:
+			dist = p.y - (from.y + from.height)
+			overlaps = p.x < from.x+from.width && from.x < p.x+p.width
+		}
+
+		if dist < 0 || !overlaps {
+			continue
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = p, dist
+		}
+	}
+	return best
+}
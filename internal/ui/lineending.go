@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/file"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// endingMarks markiert Zeilen, deren Zeilenende vom überwiegenden abweicht,
+// mit dem abweichenden Zeilenende in der Randspalte
+type endingMarks struct {
+	endings file.LineEndings
+	style   lipgloss.Style
+	width   int
+}
+
+func newEndingMarks(endings file.LineEndings, style lipgloss.Style) *endingMarks {
+	width := 0
+	for _, e := range endings.Deviations {
+		width = max(width, len(e.String()))
+	}
+	return &endingMarks{endings: endings, style: style, width: width + 1}
+}
+
+func (e *endingMarks) GutterWidth() int {
+	return e.width
+}
+
+func (e *endingMarks) Gutter(line int) string {
+	if end, ok := e.endings.Deviations[line]; ok {
+		return e.style.Render(end.String())
+	}
+	return ""
+}
+
+func (e *endingMarks) Decorate(line int) (lipgloss.Style, []textview.Span, bool) {
+	if _, ok := e.endings.Deviations[line]; ok {
+		return e.style, nil, true
+	}
+	return lipgloss.Style{}, nil, false
+}
@@ -0,0 +1,408 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fase22/tui/internal/editor"
+	"github.com/fase22/tui/internal/state"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// markAction ist eine angefangene Markenfolge, die auf den Buchstaben wartet
+type markAction int
+
+const (
+	markNone markAction = iota
+	markSet
+	markJump
+)
+
+// SetState übernimmt den gespeicherten Zustand der letzten Sitzung. Ohne
+// ihn gelten Marken nur bis zum Beenden.
+func (m *Model) SetState(s *state.State) {
+	m.state = s
+}
+
+// statePath liefert den Schlüssel eines Buffers im gespeicherten Zustand,
+// "" für Buffer ohne eigene Datei
+func (b *Buffer) statePath() string {
+	if b.path == "" || b.path == "-" {
+		return ""
+	}
+	abs, err := filepath.Abs(b.path)
+	if err != nil {
+		return ""
+	}
+	return abs
+}
+
+// isMarkName meldet, ob eine Taste eine Marke benennt: a-z für Marken im
+// Buffer, A-Z für globale Marken
+func isMarkName(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// bufferMarks liefert die Marken a-z eines Buffers, beim ersten Zugriff aus
+// dem gespeicherten Zustand
+func (m *Model) bufferMarks(b *Buffer) map[rune]int {
+	if b.marks != nil {
+		return b.marks
+	}
+	b.marks = make(map[rune]int)
+	if f, ok := m.state.Files[b.statePath()]; ok {
+		for name, line := range f.Marks {
+			if r := []rune(name); len(r) == 1 && isMarkName(r[0]) {
+				b.marks[r[0]] = line
+			}
+		}
+	}
+	return b.marks
+}
+
+// syncMarks rechnet die Marken eines Buffers einschließlich der globalen
+// Marken seiner Datei in Zeilen der Darstellung um
+func (m *Model) syncMarks(b *Buffer) {
+	lines := make(map[int]rune)
+	add := func(r rune, src int) {
+		line := displayLine(b, src)
+		if line == 0 {
+			return
+		}
+		// Mehrere Marken auf einer Zeile: der kleinste Buchstabe gewinnt
+		if old, ok := lines[line-1]; !ok || r < old {
+			lines[line-1] = r
+		}
+	}
+
+	for r, src := range m.bufferMarks(b) {
+		add(r, src)
+	}
+	if path := b.statePath(); path != "" {
+		for name, mark := range m.state.Marks {
+			if mark.Path == path {
+				add([]rune(name)[0], mark.Line)
+			}
+		}
+	}
+	b.markLines = lines
+}
+
+// moveMarks führt die Marken eines Buffers und die globalen Marken seiner
+// Datei mit den Änderungen des Dokuments nach und meldet, ob sich eine
+// Marke bewegt hat. Marken in gelöschten Zeilen verschwinden.
+func (m *Model) moveMarks(b *Buffer, splices []editor.Splice) bool {
+	if len(splices) == 0 {
+		return false
+	}
+	moved := false
+	move := func(line int) (int, bool) {
+		to, ok := moveLine(line, splices)
+		moved = moved || to != line || !ok
+		return to, ok
+	}
+
+	marks := m.bufferMarks(b)
+	for r, line := range marks {
+		if to, ok := move(line); ok {
+			marks[r] = to
+		} else {
+			delete(marks, r)
+		}
+	}
+	path := b.statePath()
+	if path == "" {
+		return moved
+	}
+	for name, mark := range m.state.Marks {
+		if mark.Path != path {
+			continue
+		}
+		if to, ok := move(mark.Line); ok {
+			mark.Line = to
+			m.state.Marks[name] = mark
+		} else {
+			delete(m.state.Marks, name)
+		}
+	}
+	if moved {
+		m.state.SetMarks(path, markNames(marks))
+	}
+	return moved
+}
+
+// moveLine folgt einer Zeile (1-basiert) durch die Änderungen. Eine
+// geänderte Zeile landet auf der neuen Zeile mit demselben Text, z. B.
+// beim Verschieben, sonst an derselben Stelle im geänderten Bereich.
+// false heißt, dass die Zeile gelöscht wurde.
+func moveLine(line int, splices []editor.Splice) (int, bool) {
+	i := line - 1
+	for _, s := range splices {
+		switch {
+		case i < s.Line:
+		case i >= s.Line+len(s.Old):
+			i += len(s.New) - len(s.Old)
+		case len(s.New) == 0:
+			return 0, false
+		default:
+			j := slices.Index(s.New, s.Old[i-s.Line])
+			if j < 0 {
+				j = min(i-s.Line, len(s.New)-1)
+			}
+			i = s.Line + j
+		}
+	}
+	return i + 1, true
+}
+
+// refreshMarks aktualisiert die Randspalte aller Ansichten eines Buffers
+func (m *Model) refreshMarks(b *Buffer) {
+	if b.state != bufferReady {
+		return
+	}
+	m.syncMarks(b)
+	for _, p := range m.root.panes() {
+		p.eachView(b, func(tv *textview.TextView) {
+			p.decorate(tv, b)
+		})
+	}
+}
+
+// finishMark setzt die Marke bzw. springt zu ihr, sobald nach m oder ' der
+// Buchstabe getippt wurde. Andere Tasten brechen ab.
+func (m *Model) finishMark(action markAction, msg tea.KeyMsg) tea.Cmd {
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return nil
+	}
+	r := msg.Runes[0]
+	if !isMarkName(r) {
+		return messages.Warn("Ungültige Marke %q, erlaubt sind a-z und A-Z", r)
+	}
+
+	b := m.buf()
+	if b.state != bufferReady || b.hex {
+		return messages.Warn("Marken sind nur in der Textansicht möglich")
+	}
+	if action == markSet {
+		return m.setMark(r)
+	}
+	return m.jumpMark(r)
+}
+
+// setMark merkt die Zeile unter dem Cursor. Großbuchstaben merken Datei und
+// Zeile als globale Marke.
+func (m *Model) setMark(r rune) tea.Cmd {
+	b := m.buf()
+	src := sourceLine(b, m.tv().GetCurrentLine())
+	if src == 0 {
+		return messages.Warn("Marken sind in der Darstellung %s nicht möglich", b.mode)
+	}
+
+	path := b.statePath()
+	if unicode.IsUpper(r) {
+		if path == "" {
+			return messages.Warn("Globale Marken brauchen eine Datei")
+		}
+		m.state.Marks[string(r)] = state.Mark{Path: path, Line: src}
+		// Die Marke kann vorher in einer anderen Datei gestanden haben
+		for _, other := range m.buffers {
+			m.refreshMarks(other)
+		}
+	} else {
+		marks := m.bufferMarks(b)
+		marks[r] = src
+		if path != "" {
+			m.state.SetMarks(path, markNames(marks))
+		}
+		m.refreshMarks(b)
+	}
+
+	return tea.Batch(messages.Info("Marke %c gesetzt, Zeile %d", r, src), m.saveState())
+}
+
+// jumpMark springt zu einer Marke. Globale Marken öffnen ihre Datei, wenn
+// sie noch nicht offen ist.
+func (m *Model) jumpMark(r rune) tea.Cmd {
+	if !unicode.IsUpper(r) {
+		src, ok := m.bufferMarks(m.buf())[r]
+		if !ok {
+			return messages.Warn("Marke %c ist nicht gesetzt", r)
+		}
+		return m.jumpSource(src)
+	}
+
+	mark, ok := m.state.Marks[string(r)]
+	if !ok {
+		return messages.Warn("Marke %c ist nicht gesetzt", r)
+	}
+	if m.buf().statePath() == mark.Path {
+		return m.jumpSource(mark.Line)
+	}
+
+	var cmd tea.Cmd
+	if index := m.bufferIndex(mark.Path); index >= 0 {
+		cmd = m.switchBuffer(index)
+	} else {
+		if _, err := os.Stat(mark.Path); err != nil {
+			return messages.Error(err)
+		}
+		cmd = m.openBuffer(mark.Path)
+	}
+
+	// Noch nicht geladene Buffer springen nach dem Laden
+	if b := m.buf(); b.state != bufferReady {
+		b.jump = mark.Line
+		return cmd
+	}
+	return tea.Batch(cmd, m.jumpSource(mark.Line))
+}
+
+// bufferIndex sucht den Buffer einer Datei anhand ihres absoluten Pfads
+func (m *Model) bufferIndex(path string) int {
+	for i, b := range m.buffers {
+		if b.statePath() == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// jumpSource springt zu einer Quellzeile (1-basiert) des angezeigten
+// Buffers. Ist die Datei inzwischen kürzer, geht es zur letzten Zeile.
+func (m *Model) jumpSource(src int) tea.Cmd {
+	b := m.buf()
+	src = min(src, strings.Count(b.sourceText(), "\n")+1)
+	line := displayLine(b, src)
+	if line == 0 {
+		return messages.Warn("Zeile %d ist in der Darstellung %s nicht sichtbar", src, b.mode)
+	}
+
+	tv := m.tv()
+	offset := tv.GetViewport().YOffset
+	m.jumpToLine(line)
+	m.syncScroll(m.focus, tv, offset)
+	return nil
+}
+
+// markNames wandelt Marken für den gespeicherten Zustand um
+func markNames(marks map[rune]int) map[string]int {
+	names := make(map[string]int, len(marks))
+	for r, line := range marks {
+		names[string(r)] = line
+	}
+	return names
+}
+
+// saveState schreibt den Zustand und meldet Fehler als Warnung
+func (m *Model) saveState() tea.Cmd {
+	if err := m.state.Save(); err != nil {
+		return messages.Warn("Konnte Zustand nicht speichern: %v", err)
+	}
+	return nil
+}
+
+// cmdMarks listet die Marken des Buffers und alle globalen Marken im
+// Meldungsprotokoll auf
+func (m *Model) cmdMarks(_ string, _ bool) tea.Cmd {
+	b := m.buf()
+	lines := strings.Split(b.sourceText(), "\n")
+	marks := m.bufferMarks(b)
+
+	var cmds []tea.Cmd
+	for _, r := range sortedMarks(marks) {
+		line := marks[r]
+		text := ""
+		if line <= len(lines) {
+			text = strings.TrimSpace(lines[line-1])
+		}
+		cmds = append(cmds, messages.Info("%c  Zeile %d  %s", r, line, text))
+	}
+
+	global := make(map[rune]int, len(m.state.Marks))
+	for name := range m.state.Marks {
+		global[[]rune(name)[0]] = 0
+	}
+	for _, r := range sortedMarks(global) {
+		mark := m.state.Marks[string(r)]
+		cmds = append(cmds, messages.Info("%c  %s:%d", r, mark.Path, mark.Line))
+	}
+
+	if len(cmds) == 0 {
+		return messages.Info("Keine Marken gesetzt")
+	}
+	m.openLog()
+	return tea.Sequence(cmds...)
+}
+
+// cmdDelmarks löscht die genannten Marken, mit ! alle Marken a-z des Buffers
+func (m *Model) cmdDelmarks(args string, bang bool) tea.Cmd {
+	b := m.buf()
+	marks := m.bufferMarks(b)
+	if bang {
+		clear(marks)
+	} else if args == "" {
+		return messages.Warn("Welche Marken? z. B. :delmarks a B")
+	}
+
+	for _, r := range args {
+		switch {
+		case unicode.IsSpace(r):
+		case !isMarkName(r):
+			return messages.Warn("Ungültige Marke %q, erlaubt sind a-z und A-Z", r)
+		case unicode.IsUpper(r):
+			delete(m.state.Marks, string(r))
+		default:
+			delete(marks, r)
+		}
+	}
+
+	if path := b.statePath(); path != "" {
+		m.state.SetMarks(path, markNames(marks))
+	}
+	for _, other := range m.buffers {
+		m.refreshMarks(other)
+	}
+	return m.saveState()
+}
+
+// sortedMarks liefert die Namen von Marken in alphabetischer Reihenfolge
+func sortedMarks(marks map[rune]int) []rune {
+	names := make([]rune, 0, len(marks))
+	for r := range marks {
+		names = append(names, r)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// markGutter zeigt Marken in der Randspalte neben der Zeilennummer
+type markGutter struct {
+	lines map[int]rune // Marke je angezeigter Zeile (0-basiert)
+	style lipgloss.Style
+}
+
+func newMarkGutter(lines map[int]rune, style lipgloss.Style) *markGutter {
+	return &markGutter{lines: lines, style: style}
+}
+
+func (g *markGutter) GutterWidth() int {
+	return 2
+}
+
+func (g *markGutter) Gutter(line int) string {
+	if r, ok := g.lines[line]; ok {
+		return g.style.Render(string(r))
+	}
+	return ""
+}
+
+func (g *markGutter) Decorate(int) (lipgloss.Style, []textview.Span, bool) {
+	return lipgloss.Style{}, nil, false
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/fase22/tui/internal/editor"
+)
+
+func TestMoveLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line int // Cursor, 0-basiert
+		col  int
+		edit func(d *editor.Document)
+		want map[int]int // Marke in Zeile (1-basiert) → neue Zeile, 0 für gelöscht
+	}{
+		{"tippen", 1, 1, func(d *editor.Document) { d.Insert("x") }, map[int]int{1: 1, 2: 2, 3: 3}},
+		{"Zeile teilen", 1, 1, func(d *editor.Document) { d.Newline(false) }, map[int]int{1: 1, 2: 2, 3: 4, 4: 5}},
+		{"Zeilenumbruch am Anfang", 1, 0, func(d *editor.Document) { d.Newline(false) }, map[int]int{1: 1, 2: 3, 3: 4}},
+		{"Zeile löschen", 1, 0, (*editor.Document).DeleteLine, map[int]int{1: 1, 2: 0, 3: 2, 4: 3}},
+		{"letzte Zeile löschen", 3, 0, (*editor.Document).DeleteLine, map[int]int{3: 3, 4: 0}},
+		{"Zeile verdoppeln", 1, 0, (*editor.Document).DuplicateLine, map[int]int{1: 1, 2: 2, 3: 4}},
+		{"Zeile nach unten", 1, 0, func(d *editor.Document) { d.MoveLine(1) }, map[int]int{1: 1, 2: 3, 3: 2, 4: 4}},
+		{"Zeile nach oben", 1, 0, func(d *editor.Document) { d.MoveLine(-1) }, map[int]int{1: 2, 2: 1, 3: 3}},
+		{"Zeilen verbinden", 1, 0, (*editor.Document).JoinLines, map[int]int{2: 2, 3: 2, 4: 3}},
+		{"Rückschritt am Zeilenanfang", 2, 0, (*editor.Document).Backspace, map[int]int{2: 2, 3: 2, 4: 3}},
+		{"Löschen rückgängig", 1, 0, func(d *editor.Document) {
+			d.DeleteLine()
+			d.Undo()
+		}, map[int]int{1: 1, 2: 0, 3: 3, 4: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := editor.New("eins\nzwei\ndrei\nvier")
+			d.SetCursor(tt.line, tt.col)
+			tt.edit(d)
+			splices := d.Splices()
+			for from, want := range tt.want {
+				got, ok := moveLine(from, splices)
+				if !ok {
+					got = 0
+				}
+				if got != want {
+					t.Errorf("Marke in Zeile %d landet in %d, erwartet %d", from, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/fase22/tui/internal/ui/components/jsonview"
+	"github.com/fase22/tui/internal/ui/components/logview"
+	"github.com/fase22/tui/internal/ui/components/markdown"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/fase22/tui/internal/ui/components/tableview"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// viewMode ist die Darstellung eines Buffers
+type viewMode int
+
+const (
+	modeText viewMode = iota
+	modeJSON
+	modeNDJSON
+	modeTable
+	modeMarkdown
+)
+
+var modeNames = []string{
+	modeText:     "text",
+	modeJSON:     "json",
+	modeNDJSON:   "ndjson",
+	modeTable:    "table",
+	modeMarkdown: "markdown",
+}
+
+func (v viewMode) String() string {
+	return modeNames[v]
+}
+
+func parseMode(name string) (viewMode, bool) {
+	for i, n := range modeNames {
+		if n == name {
+			return viewMode(i), true
+		}
+	}
+	return modeText, false
+}
+
+// sniffMode erkennt die passende Darstellung an Dateiendung und Inhalt
+func sniffMode(b *Buffer) viewMode {
+	if b.diff != nil || b.patch != nil || b.archive != nil || b.binary {
+		return modeText
+	}
+
+	switch strings.ToLower(path.Ext(b.Name())) {
+	case ".json":
+		return modeJSON
+	case ".ndjson", ".jsonl":
+		return modeNDJSON
+	case ".csv", ".tsv":
+		return modeTable
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return modeMarkdown
+	}
+
+	trimmed := strings.TrimSpace(b.sourceText())
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return modeJSON
+	}
+	if logview.Sniff(b.sourceText()) {
+		return modeNDJSON
+	}
+	return modeText
+}
+
+// detectMode wählt nach dem Laden die Darstellung. Lässt sich der Inhalt
+// nicht so darstellen, bleibt es bei Text.
+func (m *Model) detectMode(b *Buffer) tea.Cmd {
+	b.json = nil
+	b.log = nil
+	b.table = nil
+	b.markdown = nil
+	mode := b.mode
+	if mode == modeText {
+		mode = sniffMode(b)
+	}
+	if err := m.setMode(b, mode); err != nil {
+		m.setMode(b, modeText)
+		return messages.Warn("%s: %v", b.Title(), err)
+	}
+	return nil
+}
+
+// setMode erzeugt den angezeigten Inhalt eines Buffers für eine Darstellung
+func (m *Model) setMode(b *Buffer, mode viewMode) error {
+	switch mode {
+	case modeJSON:
+		if b.json == nil {
+			root, err := jsonview.Parse(b.sourceText())
+			if err != nil {
+				return err
+			}
+			b.json = jsonview.New(root, jsonview.NewStyleFromConfig(m.config))
+		}
+		b.content = rope.New(b.json.Content())
+	case modeNDJSON:
+		if b.log == nil {
+			b.log = logview.New(b.sourceText(), logview.NewStyleFromConfig(m.config))
+		}
+		b.content = rope.New(b.log.Content())
+	case modeTable:
+		if b.table == nil {
+			comma := tableview.Delimiter(b.sourceText())
+			if strings.EqualFold(path.Ext(b.Name()), ".tsv") {
+				comma = '\t'
+			}
+			table, err := tableview.Parse(b.sourceText(), comma, tableview.NewStyleFromConfig(m.config))
+			if err != nil {
+				return err
+			}
+			b.table = table
+		}
+		b.content = rope.New(b.table.Content())
+	case modeMarkdown:
+		if b.markdown == nil {
+			b.markdown = markdown.New(b.sourceText(), markdown.NewStyleFromConfig(m.config))
+		}
+		b.content = rope.New(b.markdown.Content())
+	default:
+		if b.doc != nil {
+			b.content = b.doc.Rope()
+		} else {
+			b.content = rope.New(b.source)
+		}
+	}
+	b.mode = mode
+	return nil
+}
+
+// switchMode wechselt die Darstellung des aktuellen Buffers. Wenn beide
+// Darstellungen ihre Zeilen den Quellzeilen zuordnen können, bleibt der
+// Cursor ungefähr an derselben Stelle.
+func (m *Model) switchMode(mode viewMode) tea.Cmd {
+	b := m.buf()
+	if b.state != bufferReady || b.hex {
+		return nil
+	}
+	src := sourceLine(b, m.tv().GetCurrentLine())
+	if err := m.setMode(b, mode); err != nil {
+		return messages.Error(err)
+	}
+	b.resetSearch()
+	m.eachView(b, func(tv *textview.TextView) {
+		tv.SetSearchTerm("")
+	})
+	m.refreshContent(b)
+	if line := displayLine(b, src); line > 0 {
+		m.jumpToLine(line)
+	} else {
+		m.tv().ScrollToTop()
+	}
+	return messages.Info("Darstellung: %s", mode)
+}
+
+// sourceLine liefert die Quellzeile (1-basiert) einer angezeigten Zeile,
+// 0 wenn die Darstellung sie nicht kennt
+func sourceLine(b *Buffer, line int) int {
+	switch b.mode {
+	case modeText:
+		return line
+	case modeMarkdown:
+		return b.markdown.SourceLine(line)
+	case modeTable:
+		return b.table.Line(line)
+	case modeNDJSON:
+		if record := b.log.RecordAt(line); record != nil {
+			return record.Line + 1
+		}
+	}
+	return 0
+}
+
+// displayLine liefert die angezeigte Zeile (1-basiert) zu einer Quellzeile,
+// 0 wenn die Darstellung sie nicht kennt
+func displayLine(b *Buffer, src int) int {
+	if src < 1 {
+		return 0
+	}
+	switch b.mode {
+	case modeText:
+		return src
+	case modeMarkdown:
+		return b.markdown.Line(src)
+	}
+	return 0
+}
+
+// toggleMode schaltet zwischen Text und der erkannten Darstellung um
+func (m *Model) toggleMode() tea.Cmd {
+	b := m.buf()
+	if b.mode != modeText {
+		return m.switchMode(modeText)
+	}
+	mode := sniffMode(b)
+	if mode == modeText {
+		return messages.Warn("Keine andere Darstellung für %s", b.Title())
+	}
+	return m.switchMode(mode)
+}
+
+// refreshContent überträgt einen neu erzeugten Inhalt in alle Ansichten des
+// Buffers. Suchtreffer werden im Hintergrund neu berechnet, ohne zu
+// springen.
+func (m *Model) refreshContent(b *Buffer) {
+	m.syncMarks(b)
+	for _, p := range m.root.panes() {
+		p.eachView(b, func(tv *textview.TextView) {
+			p.decorate(tv, b)
+			tv.SetText(b.content)
+		})
+	}
+	b.startSearch(false)
+}
+
+func (m *Model) cmdMode(args string, _ bool) tea.Cmd {
+	if args == "" {
+		return messages.Info("mode=%s", m.buf().mode)
+	}
+	mode, ok := parseMode(args)
+	if !ok {
+		return messages.Error(fmt.Errorf("Unbekannte Darstellung: %s (erlaubt: %s)",
+			args, strings.Join(modeNames, ", ")))
+	}
+	return m.switchMode(mode)
+}
+
+func (m *Model) completeMode(arg string) []string {
+	return filterPrefix(modeNames, arg)
+}
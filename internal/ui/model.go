@@ -4,12 +4,27 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/diff"
 	"github.com/fase22/tui/internal/file"
-	"github.com/fase22/tui/internal/ui/components/scrollbar"
+	"github.com/fase22/tui/internal/outline"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/fase22/tui/internal/state"
+	"github.com/fase22/tui/internal/ui/components/commandline"
+	"github.com/fase22/tui/internal/ui/components/diffview"
+	"github.com/fase22/tui/internal/ui/components/helpview"
+	"github.com/fase22/tui/internal/ui/components/hexview"
+	"github.com/fase22/tui/internal/ui/components/jsonview"
+	"github.com/fase22/tui/internal/ui/components/listview"
+	"github.com/fase22/tui/internal/ui/components/logview"
+	"github.com/fase22/tui/internal/ui/components/markdown"
+	"github.com/fase22/tui/internal/ui/components/messages"
 	"github.com/fase22/tui/internal/ui/components/statusbar"
+	"github.com/fase22/tui/internal/ui/components/tabbar"
+	"github.com/fase22/tui/internal/ui/components/tableview"
 	"github.com/fase22/tui/internal/ui/components/textview"
 )
 
@@ -18,73 +33,130 @@ type Mode int
 const (
 	ModeNormal Mode = iota
 	ModeSearch
+	ModeCommand
+	ModeInsert
 )
 
 type Model struct {
-	textView    textview.TextView
-	statusBar   statusbar.StatusBar
-	scrollBar   scrollbar.Scrollbar
-	currentFile string
-	err         error
-	state       string
-	config      *config.Config
-	mode        Mode
-	searchQuery string
-	searchIndex int   // Aktueller Treffer-Index
-	searchHits  []int // Zeilennummern der Treffer
+	buffers        []*Buffer
+	root           *layoutNode  // Split-Baum aller Panes
+	focus          *Pane        // Pane mit dem Tastaturfokus
+	diff           *diffSession // Geöffneter Vergleich, sonst nil
+	details        *logDetails  // Detail-Pane einer NDJSON-Ansicht, sonst nil
+	fileList       listview.ListView
+	filesOf        *diff.Patch // Patch, dessen Dateien die Liste zeigt
+	outline        listview.ListView
+	outlineOf      *Buffer          // Buffer, dessen Gliederung die Liste zeigt
+	outlineContent rope.Rope        // Inhalt, aus dem die Liste erstellt wurde
+	outlineQuery   string           // Filter, mit dem die Liste erstellt wurde
+	outlineFilter  string           // Getippter Filter der Gliederung
+	outlineItems   []outline.Symbol // Gefilterte Einträge in Listenreihenfolge
+	tabBar         tabbar.TabBar
+	statusBar      statusbar.StatusBar
+	statusStyle    statusbar.Style // Wird nur bei Konfigurationsänderungen neu erstellt
+	messages       messages.Log
+	commandLine    commandline.CommandLine
+	helpView       helpview.HelpView
+	keys           KeyMap
+	pending        []string     // Bisher getippte Tasten einer Tastenfolge
+	markPending    markAction   // m oder ' getippt, es fehlt der Buchstabe
+	state          *state.State // Zustand, der zwischen Sitzungen erhalten bleibt
+	err            error        // Nur für fatale Fehler, ersetzt die gesamte Anzeige
+	config         *config.Config
+	mode           Mode
+	showLog        bool // Meldungsprotokoll anstelle des Textes anzeigen
+	showHelp       bool // Hilfe anstelle des Textes anzeigen
+	showFiles      bool // Dateiliste neben Patches anzeigen
+	showOutline    bool // Gliederung neben dem Text anzeigen
+	startup        []tea.Cmd
+	width          int
+	height         int
 }
 
-type errMsg struct {
-	err error
+// NewModel öffnet alle Dateien als Buffer, geladen wird erst beim Anzeigen
+func NewModel(filenames []string, cfg *config.Config) *Model {
+	// Styles aus der Konfiguration erstellen
+	sbStyle := statusbar.NewStyleFromConfig(cfg)
+	keys := NewKeyMap(cfg)
+
+	var buffers []*Buffer
+	for _, filename := range filenames {
+		buffers = append(buffers, newBuffer(filename))
+	}
+
+	var firstFile string
+	var root *layoutNode
+	var focus *Pane
+	if len(buffers) > 0 {
+		firstFile = filenames[0]
+		focus = newPane(buffers[0], cfg)
+		focus.Focus()
+		root = newLeaf(focus)
+	}
+
+	m := &Model{
+		buffers:     buffers,
+		root:        root,
+		focus:       focus,
+		tabBar:      tabbar.New(80, tabbar.NewStyleFromConfig(cfg)),
+		statusBar:   newStatusBar(firstFile, 80, sbStyle, keys),
+		statusStyle: sbStyle,
+		messages:    messages.NewLog(messages.DefaultTimeout, messages.NewStyleFromConfig(cfg)),
+		helpView:    helpview.New(keys.Groups(), keys.overlayHelp(), helpview.NewStyleFromConfig(cfg)),
+		fileList:    listview.New("Dateien", listview.NewStyleFromConfig(cfg)),
+		showFiles:   true,
+		outline:     listview.New(outlineTitle, listview.NewStyleFromConfig(cfg)),
+		keys:        keys,
+		state:       state.New(""),
+		config:      cfg,
+		mode:        ModeNormal,
+	}
+	m.commandLine = commandline.New(":", 80, commandline.NewStyleFromConfig(cfg), m.completeCommandLine)
+	return m
 }
 
-type fileLoadedMsg struct {
-	content string
+// Notify merkt eine Meldung vor, die beim Programmstart angezeigt wird
+func (m *Model) Notify(cmd tea.Cmd) {
+	m.startup = append(m.startup, cmd)
 }
 
-type searchMsg struct {
-	query string
+func (m *Model) Init() tea.Cmd {
+	cmds := m.startup
+	m.startup = nil
+	for _, b := range m.buffers {
+		m.restoreState(b)
+	}
+	if len(m.buffers) > 0 {
+		cmds = append(cmds, m.ensureLoaded(m.buf()))
+	}
+	return tea.Batch(cmds...)
 }
 
-type searchHitMsg struct {
-	hits []int
+// buf liefert den Buffer im fokussierten Pane
+func (m *Model) buf() *Buffer {
+	return m.focus.buf
 }
 
-func NewModel(filename string, cfg *config.Config) *Model {
-	// Styles aus der Konfiguration erstellen
-	tvStyle := textview.NewStyleFromConfig(cfg)
-	sbStyle := statusbar.NewStyleFromConfig(cfg)
-	scrollStyle := scrollbar.NewStyleFromConfig(cfg)
-
-	return &Model{
-		textView: textview.New(80, 24, textview.Config{
-			ShowLineNumbers: cfg.Editor.ShowLineNumbers,
-			TabWidth:        cfg.Editor.TabWidth,
-			WordWrap:        cfg.Editor.WordWrap,
-			Style:           tvStyle,
-		}),
-		statusBar:   statusbar.New(filename, 80, sbStyle),
-		scrollBar:   scrollbar.New(24, 0, 0, scrollStyle),
-		currentFile: filename,
-		state:       "initialized",
-		config:      cfg,
-		mode:        ModeNormal,
-	}
+// tv liefert die Textansicht des fokussierten Panes
+func (m *Model) tv() *textview.TextView {
+	return m.focus.view()
 }
 
-func (m *Model) Init() tea.Cmd {
-	if m.currentFile != "" {
-		return m.loadFile
+// currentIndex liefert den Index des fokussierten Buffers
+func (m *Model) currentIndex() int {
+	for i, b := range m.buffers {
+		if b == m.focus.buf {
+			return i
+		}
 	}
-	return nil
+	return 0
 }
 
-func (m *Model) loadFile() tea.Msg {
-	content, err := file.ReadFile(m.currentFile)
-	if err != nil {
-		return errMsg{err}
+// eachView ruft fn für alle Ansichten eines Buffers in allen Panes auf
+func (m *Model) eachView(b *Buffer, fn func(tv *textview.TextView)) {
+	for _, p := range m.root.panes() {
+		p.eachView(b, fn)
 	}
-	return fileLoadedMsg{content: content}
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -92,174 +164,781 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		// Komponenten an neue Fenstergröße anpassen
-		m.textView.Resize(msg.Width-2, msg.Height-2) // -2 für Statusleiste
-
-		// Styles aus der Konfiguration erstellen
-		sbStyle := statusbar.NewStyleFromConfig(m.config)
-		scrollStyle := scrollbar.NewStyleFromConfig(m.config)
-
-		// Komponenten mit Styles aktualisieren
-		m.statusBar = statusbar.New(m.currentFile, msg.Width, sbStyle)
-		m.scrollBar = scrollbar.New(
-			msg.Height-2,
-			m.textView.GetTotalLines(),
-			m.textView.GetCurrentLine()-1,
-			scrollStyle,
-		)
+		m.width = msg.Width
+		m.height = msg.Height
+		m.resize()
 
 	case tea.KeyMsg:
-		if m.mode == ModeSearch {
-			switch msg.Type {
-			case tea.KeyEnter:
-				// Suche starten
-				m.mode = ModeNormal
-				return m, m.search
-			case tea.KeyEsc:
-				// Suchmodus verlassen
-				m.mode = ModeNormal
-				m.searchQuery = ""
-				m.searchHits = nil
-				m.textView.SetSearchTerm("") // Highlighting entfernen
-			case tea.KeyBackspace:
-				if len(m.searchQuery) > 0 {
-					m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
-				}
-			default:
-				// Ignoriere Steuerungstasten
-				if msg.Type != tea.KeyCtrlC && msg.Type != tea.KeyCtrlH {
-					// Zeichen zur Suchanfrage hinzufügen
-					m.searchQuery += msg.String()
-				}
-			}
-		} else {
-			switch msg.String() {
-			case "q", "ctrl+c":
-				return m, tea.Quit
-			case "up", "k":
-				m.textView.ScrollUp(1)
-			case "down", "j":
-				m.textView.ScrollDown(1)
-			case "pgup":
-				m.textView.ScrollUp(m.textView.GetViewport().Height)
-			case "pgdown":
-				m.textView.ScrollDown(m.textView.GetViewport().Height)
-			case "/":
-				// In den Suchmodus wechseln
-				m.mode = ModeSearch
-				m.searchQuery = ""
-				m.searchHits = nil
-				m.searchIndex = 0
-			case "n":
-				// Zum nächsten Treffer
-				if len(m.searchHits) > 0 {
-					m.searchIndex = (m.searchIndex + 1) % len(m.searchHits)
-					m.jumpToLine(m.searchHits[m.searchIndex])
-				}
-			case "N":
-				// Zum vorherigen Treffer
-				if len(m.searchHits) > 0 {
-					m.searchIndex--
-					if m.searchIndex < 0 {
-						m.searchIndex = len(m.searchHits) - 1
-					}
-					m.jumpToLine(m.searchHits[m.searchIndex])
-				}
+		switch {
+		case len(m.buffers) == 0:
+			// Solange nichts geladen ist, kann nur beendet werden
+			if key.Matches(msg, m.keys.Quit) {
+				cmd = tea.Quit
 			}
+		case m.showHelp:
+			m.updateHelp(msg)
+		case m.showLog:
+			m.updateLog(msg)
+		case m.fileListVisible() && m.fileList.Focused():
+			cmd = m.updateFiles(msg)
+		case m.outlineVisible() && m.outline.Focused():
+			cmd = m.updateOutline(msg)
+		case m.mode == ModeSearch:
+			cmd = m.updateSearch(msg)
+		case m.mode == ModeCommand:
+			cmd = m.updateCommand(msg)
+		case m.mode == ModeInsert:
+			cmd = m.updateInsert(msg)
+		default:
+			cmd = m.updateNormal(msg)
 		}
 
 	case fileLoadedMsg:
-		m.textView.SetContent(msg.content)
-		m.state = "ready"
+		if msg.buf.pager != nil && msg.buf.pager != msg.pager {
+			msg.buf.pager.Close()
+		}
+		msg.buf.pager = msg.pager
+		msg.buf.binary = msg.binary
+		if msg.hex {
+			msg.buf.hex = true
+		}
+		msg.buf.source = msg.content
+		msg.buf.doc = nil // Neu geladen verwirft Änderungen
+		msg.buf.stale = false
+		msg.buf.symbols = nil
+		msg.buf.info = msg.info
+		msg.buf.archive = msg.archive
+		msg.buf.state = bufferReady
+		msg.buf.err = nil
+		m.detectPatch(msg.buf)
+		cmd = m.detectMode(msg.buf)
+		m.syncMarks(msg.buf)
+		for _, p := range m.root.panes() {
+			p.Update(msg)
+		}
+		m.applyRestore(msg.buf)
+		if msg.buf.jump > 0 && msg.buf == m.buf() {
+			cmd = tea.Batch(cmd, m.jumpSource(msg.buf.jump))
+			msg.buf.jump = 0
+		}
+		m.resize() // Dateiliste kann ein- oder ausgeblendet werden
 
 	case errMsg:
-		m.err = msg.err
-		m.state = "error"
+		msg.buf.state = bufferFailed
+		msg.buf.err = msg.err
+		// Fatal ist ein Fehler nur, wenn keine Datei angezeigt werden kann
+		if m.allFailed() {
+			m.err = msg.err
+		} else {
+			cmd = messages.Error(msg.err)
+		}
 
-	case searchHitMsg:
-		m.searchHits = msg.hits
-		m.textView.SetSearchTerm(m.searchQuery) // Highlighting aktivieren
-		if len(m.searchHits) > 0 {
-			m.jumpToLine(m.searchHits[0])
+	case hexMatchMsg:
+		cmd = m.showHexMatch(msg)
+
+	case diffLoadedMsg:
+		cmd = m.showDiff(msg)
+
+	case diffErrMsg:
+		if len(m.buffers) == 0 {
+			m.err = msg.err
+		} else {
+			cmd = messages.Error(msg.err)
 		}
+
+	case messages.Msg:
+		cmd = m.messages.Add(msg)
+
+	case messages.ExpiredMsg:
+		m.messages.Expire(msg.ID)
+
+	case searchHitMsg:
+		cmd = m.receiveHits(msg)
+	}
+	cmd = tea.Batch(cmd, m.searchCmds())
+
+	if len(m.buffers) == 0 {
+		return m, cmd
 	}
+	b := m.buf()
+	tv := m.tv()
+
+	m.syncDetails()
 
 	// Update StatusBar
 	m.statusBar.Update(
-		m.textView.GetCurrentLine(),
-		m.textView.GetTotalLines(),
-		len(m.textView.GetViewport().View()),
+		tv.GetCurrentLine(),
+		tv.GetTotalLines(),
+		tv.Text().Len(),
 	)
+	m.statusBar.SetCompression(b.info.Compression.String(), b.info.Size, b.info.RawSize)
+	m.statusBar.SetEncoding("", false)
+	if b.info.Encoding != file.EncodingAuto {
+		m.statusBar.SetEncoding(b.info.Encoding.String(), b.info.BOM)
+	}
+	m.statusBar.SetLineEnding(b.info.LineEndings.String())
+	m.statusBar.SetFolds(tv.ClosedFolds())
+	m.statusBar.SetModified(b.modified())
+	m.statusBar.SetProgress(searchProgress(b))
+	m.syncMatch(b)
+	m.statusBar.SetMode("")
+	if m.mode == ModeInsert {
+		m.statusBar.SetMode("EINFÜGEN")
+	}
+
+	// Datei und Hunk eines Patches anzeigen
+	m.syncFileList()
+	m.syncOutline()
+	m.statusBar.SetContext(m.patchContext())
+	if b.archive != nil {
+		m.statusBar.SetContext(fmt.Sprintf("%s, %d Einträge", b.archive.Kind, len(b.archive.Entries)))
+	}
+	if b.mode != modeText {
+		m.statusBar.SetContext(strings.ToUpper(b.mode.String()))
+	}
+	if b.mode == modeNDJSON && b.log != nil {
+		shown, total := b.log.Rows()
+		m.statusBar.SetContext(fmt.Sprintf("NDJSON %d/%d Zeilen", shown, total))
+	}
+	if b.mode == modeTable && b.table != nil {
+		m.statusBar.SetContext(m.tableContext(b.table))
+	}
+
+	// In der Hexansicht zählen Zeilen der Ausgabe und Offsets
+	if b.hex && b.state == bufferReady {
+		hv := m.hex()
+		m.statusBar.Update(hv.Top()+1, hv.Rows(), int(hv.Size()))
+		m.statusBar.SetContext(fmt.Sprintf("HEX Offset 0x%x", hv.Offset()))
+	}
 
 	// Update search info in status bar
 	if m.mode == ModeSearch {
 		m.statusBar.SetSearchInfo(
 			true,
-			m.searchQuery,
-			m.searchIndex,
-			len(m.searchHits),
+			b.searchQuery,
+			b.searchIndex,
+			len(b.searchHits),
 		)
 	} else {
 		m.statusBar.SetSearchInfo(false, "", 0, 0)
 	}
 
-	// Update ScrollBar
-	m.scrollBar = scrollbar.New(
-		m.textView.GetViewport().Height,
-		m.textView.GetTotalLines(),
-		m.textView.GetCurrentLine()-1,
-		scrollbar.NewStyleFromConfig(m.config),
-	)
+	// Aktuelle Meldung in der Statusleiste anzeigen
+	if current, ok := m.messages.Current(); ok {
+		m.statusBar.SetMessage(current.Level, current.Text)
+	} else {
+		m.statusBar.SetMessage(messages.LevelInfo, "")
+	}
+
+	// Tableiste aktualisieren
+	tabs := make([]tabbar.Tab, len(m.buffers))
+	for i, buffer := range m.buffers {
+		tabs[i] = tabbar.Tab{Title: buffer.Title(), Modified: buffer.modified()}
+	}
+	m.tabBar.SetTabs(tabs, m.currentIndex())
 
 	return m, cmd
 }
+
 func (m *Model) View() string {
 	if m.err != nil {
 		return errorStyle.Render(m.err.Error())
 	}
+	if len(m.buffers) == 0 {
+		return ""
+	}
 
 	// Hauptinhalt
-	content := lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		m.textView.Render(),
-		m.scrollBar.Render(),
-	)
+	var content string
+	if m.showHelp {
+		content = m.helpView.Render()
+	} else if m.showLog {
+		content = m.messages.Render()
+	} else {
+		content = m.root.render(m.focus.style.Separator)
+		if m.fileListVisible() {
+			content = lipgloss.JoinHorizontal(lipgloss.Top, m.fileList.Render(), content)
+		}
+		if m.outlineVisible() {
+			content = lipgloss.JoinHorizontal(lipgloss.Top, m.outline.Render(), content)
+		}
+	}
 
-	// Status und Sucheingabe
+	// Status, Such- und Befehlseingabe
 	var status string
-	if m.mode == ModeSearch {
-		searchPrompt := fmt.Sprintf("/%s", m.searchQuery)
-		if len(m.searchHits) > 0 {
-			searchPrompt += fmt.Sprintf(" (%d/%d)", m.searchIndex+1, len(m.searchHits))
+	switch {
+	case m.mode == ModeSearch:
+		b := m.buf()
+		searchPrompt := fmt.Sprintf("/%s", b.searchQuery)
+		if len(b.searchHits) > 0 {
+			searchPrompt += fmt.Sprintf(" (%d/%d)", b.searchIndex+1, len(b.searchHits))
 		}
 		status = searchPrompt
-	} else {
+	case m.mode == ModeCommand:
+		status = m.commandLine.Render()
+	case m.config.UI.ShowStatus:
 		status = m.statusBar.Render()
 	}
 
 	// Kombiniere alles
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		content,
-		status,
-	)
+	var parts []string
+	if m.showTabBar() {
+		parts = append(parts, m.tabBar.Render())
+	}
+	parts = append(parts, content, status)
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// updateNormal verarbeitet Tasten im Normalmodus
+func (m *Model) updateNormal(msg tea.KeyMsg) tea.Cmd {
+	// Nach m bzw. ' wählt die nächste Taste die Marke
+	if action := m.markPending; action != markNone {
+		m.markPending = markNone
+		return m.finishMark(action, msg)
+	}
+
+	// Tastenfolgen wie "gt" sammeln, bis sie eindeutig sind
+	seq := append(m.pending, msg.String())
+	if m.keys.isPrefix(seq) {
+		m.pending = seq
+		return nil
+	}
+	m.pending = nil
+	keys := keySequence(seq)
+
+	var cmd tea.Cmd
+	tv := m.tv()
+	b := m.buf()
+
+	// Die Hexansicht hat eigene Bewegungs- und Suchtasten
+	if b.hex && b.state == bufferReady {
+		if cmd, ok := m.updateHex(keys); ok {
+			return cmd
+		}
+	}
+
+	// Gebundene Panes folgen der Scrollbewegung des fokussierten Panes
+	offset := tv.GetViewport().YOffset
+	defer m.syncScroll(m.focus, tv, offset)
+
+	switch {
+	case key.Matches(keys, m.keys.StopSearch) && b.searching != nil:
+		cmd = m.cancelSearch(b)
+	case key.Matches(keys, m.keys.Quit):
+		return m.quit(false)
+	case key.Matches(keys, m.keys.Help):
+		m.showHelp = true
+		m.helpView.Reset()
+	case key.Matches(keys, m.keys.Messages):
+		m.openLog()
+	case key.Matches(keys, m.keys.Command):
+		// In den Befehlsmodus wechseln
+		m.mode = ModeCommand
+		m.commandLine.Open()
+	case key.Matches(keys, m.keys.NextBuffer):
+		cmd = m.switchBuffer(m.currentIndex() + 1)
+	case key.Matches(keys, m.keys.PrevBuffer):
+		cmd = m.switchBuffer(m.currentIndex() - 1)
+	case key.Matches(keys, m.keys.NextHunk):
+		cmd = m.jumpHunk(true)
+	case key.Matches(keys, m.keys.PrevHunk):
+		cmd = m.jumpHunk(false)
+	case key.Matches(keys, m.keys.NextFile):
+		cmd = m.jumpFile(true)
+	case key.Matches(keys, m.keys.PrevFile):
+		cmd = m.jumpFile(false)
+	case key.Matches(keys, m.keys.Files):
+		cmd = m.toggleFiles()
+	case key.Matches(keys, m.keys.Outline):
+		cmd = m.toggleOutline()
+	case key.Matches(keys, m.keys.SetMark):
+		m.markPending = markSet
+	case key.Matches(keys, m.keys.JumpMark):
+		m.markPending = markJump
+	case key.Matches(keys, m.keys.Hex):
+		cmd = m.toggleHex()
+	case key.Matches(keys, m.keys.Open):
+		cmd = m.openEntry()
+	case key.Matches(keys, m.keys.ToggleMode):
+		cmd = m.toggleMode()
+	case key.Matches(keys, m.keys.FoldToggle):
+		cmd = m.fold(foldToggle)
+	case key.Matches(keys, m.keys.FoldClose):
+		cmd = m.fold(foldClose)
+	case key.Matches(keys, m.keys.FoldOpen):
+		cmd = m.fold(foldOpen)
+	case key.Matches(keys, m.keys.FoldCloseAll):
+		cmd = m.fold(foldCloseAll)
+	case key.Matches(keys, m.keys.FoldOpenAll):
+		cmd = m.fold(foldOpenAll)
+	case key.Matches(keys, m.keys.CopyPath):
+		cmd = m.copyJSONPath()
+	case key.Matches(keys, m.keys.ColumnLeft):
+		cmd = m.tableAction(tableLeft)
+	case key.Matches(keys, m.keys.ColumnRight):
+		cmd = m.tableAction(tableRight)
+	case key.Matches(keys, m.keys.MoveLeft):
+		cmd = m.tableAction(tableMoveLeft)
+	case key.Matches(keys, m.keys.MoveRight):
+		cmd = m.tableAction(tableMoveRight)
+	case key.Matches(keys, m.keys.Sort):
+		cmd = m.tableAction(tableSort)
+	case key.Matches(keys, m.keys.HideColumn):
+		cmd = m.tableAction(tableHide)
+	case key.Matches(keys, m.keys.Split):
+		m.splitPane(splitHorizontal)
+	case key.Matches(keys, m.keys.VSplit):
+		m.splitPane(splitVertical)
+	case key.Matches(keys, m.keys.NextPane):
+		m.cyclePane()
+	case key.Matches(keys, m.keys.PaneLeft):
+		m.focusDirection(dirLeft)
+	case key.Matches(keys, m.keys.PaneDown):
+		m.focusDirection(dirDown)
+	case key.Matches(keys, m.keys.PaneUp):
+		m.focusDirection(dirUp)
+	case key.Matches(keys, m.keys.PaneRight):
+		m.focusDirection(dirRight)
+	case key.Matches(keys, m.keys.GrowPane):
+		m.resizePane(ratioStep)
+	case key.Matches(keys, m.keys.ShrinkPane):
+		m.resizePane(-ratioStep)
+	case key.Matches(keys, m.keys.ClosePane):
+		cmd = m.closePane()
+	case key.Matches(keys, m.keys.ScrollBind):
+		cmd = m.toggleScrollBind()
+	case key.Matches(keys, m.keys.Insert):
+		cmd = m.enterInsert()
+	case key.Matches(keys, m.keys.Undo):
+		cmd = m.undo(b)
+	case key.Matches(keys, m.keys.Redo):
+		cmd = m.redo(b)
+	case key.Matches(keys, m.keys.Save):
+		cmd = m.saveBuffer(b)
+	case key.Matches(keys, m.keys.Up):
+		tv.CursorUp(1)
+	case key.Matches(keys, m.keys.Down):
+		tv.CursorDown(1)
+	case key.Matches(keys, m.keys.PageUp):
+		tv.ScrollUp(tv.GetViewport().Height)
+	case key.Matches(keys, m.keys.PageDown):
+		tv.ScrollDown(tv.GetViewport().Height)
+	case key.Matches(keys, m.keys.Top):
+		tv.ScrollToTop()
+	case key.Matches(keys, m.keys.Bottom):
+		tv.ScrollToBottom()
+	case key.Matches(keys, m.keys.ToggleWrap):
+		m.toggleBufferOption(&b.wrap, m.config.Editor.WordWrap)
+	case key.Matches(keys, m.keys.ToggleLines):
+		m.toggleBufferOption(&b.numbers, m.config.Editor.ShowLineNumbers)
+	case key.Matches(keys, m.keys.Search):
+		// In den Suchmodus wechseln
+		m.mode = ModeSearch
+		b.resetSearch()
+	case key.Matches(keys, m.keys.NextMatch):
+		// Zum nächsten Treffer
+		if len(b.searchHits) > 0 {
+			b.searchIndex++
+			if b.searchIndex >= len(b.searchHits) {
+				b.searchIndex = 0
+				cmd = messages.Info("Suche am Ende angelangt, weiter am Anfang")
+			}
+			m.jumpToMatch(b.searchHits[b.searchIndex])
+		}
+	case key.Matches(keys, m.keys.PrevMatch):
+		// Zum vorherigen Treffer
+		if len(b.searchHits) > 0 {
+			b.searchIndex--
+			if b.searchIndex < 0 {
+				b.searchIndex = len(b.searchHits) - 1
+				cmd = messages.Info("Suche am Anfang angelangt, weiter am Ende")
+			}
+			m.jumpToMatch(b.searchHits[b.searchIndex])
+		}
+	}
+
+	return cmd
+}
+
+// updateSearch verarbeitet Tasten im Suchmodus
+func (m *Model) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	b := m.buf()
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		// Suche starten, in der Hexansicht nach einer Bytefolge
+		m.mode = ModeNormal
+		if b.hex {
+			return m.startHexSearch(b)
+		}
+		m.search(b)
+	case tea.KeyEsc:
+		// Suchmodus verlassen
+		m.mode = ModeNormal
+		b.resetSearch()
+		m.eachView(b, func(tv *textview.TextView) {
+			tv.SetSearchTerm("") // Highlighting entfernen
+		})
+		m.eachHexView(b, func(hv *hexview.HexView) {
+			hv.SetMatch(0, 0)
+		})
+	case tea.KeyBackspace:
+		if len(b.searchQuery) > 0 {
+			b.searchQuery = b.searchQuery[:len(b.searchQuery)-1]
+		}
+	default:
+		// Ignoriere Steuerungstasten
+		if msg.Type != tea.KeyCtrlC && msg.Type != tea.KeyCtrlH {
+			// Zeichen zur Suchanfrage hinzufügen
+			b.searchQuery += msg.String()
+		}
+	}
+	return nil
+}
+
+// updateCommand verarbeitet Tasten im Befehlsmodus
+func (m *Model) updateCommand(msg tea.KeyMsg) tea.Cmd {
+	result, cmd := m.commandLine.Update(msg)
+	switch result {
+	case commandline.ResultSubmit:
+		m.mode = ModeNormal
+		m.commandLine.Close()
+		return m.runCommand(m.commandLine.Value())
+	case commandline.ResultCancel:
+		m.mode = ModeNormal
+		m.commandLine.Close()
+	}
+	return cmd
+}
+
+// switchBuffer zeigt den Buffer mit dem Index im fokussierten Pane an
+// (zyklisch) und lädt ihn bei Bedarf
+func (m *Model) switchBuffer(index int) tea.Cmd {
+	if len(m.buffers) == 0 {
+		return nil
+	}
+	index %= len(m.buffers)
+	if index < 0 {
+		index += len(m.buffers)
+	}
+
+	m.focus.setBuffer(m.buffers[index])
+	m.resize() // Tableiste kann ein- oder ausgeblendet werden
+	return m.ensureLoaded(m.buf())
+}
+
+// ensureLoaded lädt einen Buffer bei Bedarf in der eingestellten Kodierung
+func (m *Model) ensureLoaded(b *Buffer) tea.Cmd {
+	if b.state == bufferUnloaded {
+		b.encoding, _ = file.ParseEncoding(m.config.Editor.Encoding)
+	}
+	return b.ensureLoaded()
+}
+
+// openBuffer öffnet eine Datei als neuen Buffer oder wechselt zu ihr
+func (m *Model) openBuffer(path string) tea.Cmd {
+	for i, b := range m.buffers {
+		if b.path == path {
+			return m.switchBuffer(i)
+		}
+	}
+
+	b := newBuffer(path)
+	m.restoreState(b)
+	m.buffers = append(m.buffers, b)
+	return m.switchBuffer(len(m.buffers) - 1)
+}
+
+// closeBuffer schließt den fokussierten Buffer, der letzte beendet das
+// Programm. Ungespeicherte Änderungen werden nur mit force verworfen.
+func (m *Model) closeBuffer(force bool) tea.Cmd {
+	closed := m.buf()
+	if closed.modified() && !force {
+		return messages.Warn("Ungespeicherte Änderungen in %s (:bd! verwirft sie)", closed.Title())
+	}
+	if len(m.buffers) == 1 {
+		return m.quit(force)
+	}
+
+	m.rememberState(closed)
+	closed.stopSearch()
+	closed.close()
+	index := m.currentIndex()
+	m.buffers = append(m.buffers[:index], m.buffers[index+1:]...)
+	if m.diff != nil && m.diff.contains(closed) {
+		m.diff = nil
+	}
+	if index >= len(m.buffers) {
+		index = len(m.buffers) - 1
+	}
+
+	// Panes, die den Buffer zeigen, auf einen anderen umstellen
+	for _, p := range m.root.panes() {
+		p.forget(closed)
+		if p.buf == closed {
+			p.setBuffer(m.buffers[index])
+		}
+	}
+	return m.switchBuffer(index)
+}
+
+// splitPane teilt das fokussierte Pane, das neue Pane erhält den Fokus
+func (m *Model) splitPane(dir splitDir) *Pane {
+	p := m.focus.clone()
+	m.root.find(m.focus).split(dir, p)
+	m.setFocus(p)
+	m.resize()
+	return p
+}
+
+// closePane schließt das fokussierte Pane
+func (m *Model) closePane() tea.Cmd {
+	panes := m.root.panes()
+	if len(panes) == 1 {
+		return messages.Warn("Das letzte Pane kann nicht geschlossen werden")
+	}
+
+	leaf := m.root.find(m.focus)
+	next := panes[0]
+	for i, p := range panes {
+		if p == m.focus && i > 0 {
+			next = panes[i-1]
+		} else if p == m.focus {
+			next = panes[1]
+		}
+	}
+	leaf.remove()
+	m.setFocus(next)
+	m.resize()
+	return nil
+}
+
+// onlyPane schließt alle Panes außer dem fokussierten
+func (m *Model) onlyPane() {
+	m.root = newLeaf(m.focus)
+	m.resize()
+}
+
+func (m *Model) setFocus(p *Pane) {
+	m.focus.Blur()
+	m.focus = p
+	m.focus.Focus()
+	m.statusBar = m.newStatusBar()
+}
+
+func (m *Model) cyclePane() {
+	panes := m.root.panes()
+	for i, p := range panes {
+		if p == m.focus {
+			m.setFocus(panes[(i+1)%len(panes)])
+			return
+		}
+	}
+}
+
+func (m *Model) focusDirection(dir direction) {
+	if p := neighbor(m.root.panes(), m.focus, dir); p != nil {
+		m.setFocus(p)
+	}
+}
+
+func (m *Model) resizePane(delta float64) {
+	if m.root.find(m.focus).grow(delta) {
+		m.resize()
+	}
+}
+
+// toggleScrollBind bindet das fokussierte Pane an die Scrollbewegung anderer
+// gebundener Panes oder löst die Bindung
+func (m *Model) toggleScrollBind() tea.Cmd {
+	m.focus.scrollBind = !m.focus.scrollBind
+	if m.focus.scrollBind {
+		return messages.Info("Scrollbindung aktiv")
+	}
+	return messages.Info("Scrollbindung aufgehoben")
 }
 
-func (m *Model) search() tea.Msg {
-	if m.searchQuery == "" {
-		return searchHitMsg{hits: nil}
+// syncScroll überträgt die Scrollbewegung eines gebundenen Panes auf alle
+// anderen gebundenen Panes
+func (m *Model) syncScroll(from *Pane, tv *textview.TextView, oldOffset int) {
+	// Nach einem Buffer- oder Pane-Wechsel gibt es keine Bewegung zu übertragen
+	if !from.scrollBind || from.view() != tv || m.root.find(from) == nil {
+		return
 	}
 
-	var hits []int
-	lines := strings.Split(m.textView.GetContent(), "\n")
-	for i, line := range lines {
-		if strings.Contains(strings.ToLower(line), strings.ToLower(m.searchQuery)) {
-			hits = append(hits, i+1)
+	delta := from.view().GetViewport().YOffset - oldOffset
+	if delta == 0 {
+		return
+	}
+	for _, p := range m.root.panes() {
+		if p == from || !p.scrollBind {
+			continue
+		}
+		if delta > 0 {
+			p.view().ScrollDown(delta)
+		} else {
+			p.view().ScrollUp(-delta)
+		}
+	}
+}
+
+func (m *Model) allFailed() bool {
+	for _, b := range m.buffers {
+		if b.state != bufferFailed {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Model) showTabBar() bool {
+	return len(m.buffers) > 1
+}
+
+func (m *Model) openLog() {
+	m.showLog = true
+	m.messages.ScrollToEnd()
+}
+
+// resize verteilt die Fenstergröße auf die Komponenten
+func (m *Model) resize() {
+	height := m.height - 2 // -2 für Statusleiste
+	if m.showTabBar() {
+		height--
+	}
+
+	listWidth := m.fileListWidth()
+	m.fileList.SetSize(listWidth, height)
+	outlineWidth := m.outlineWidth()
+	m.outline.SetSize(outlineWidth, height)
+	listWidth += outlineWidth
+	if m.root != nil {
+		m.root.layout(listWidth, 0, m.width-1-listWidth, height, len(m.root.panes()) > 1)
+	}
+	m.messages.Resize(m.width-2, height)
+	m.helpView.Resize(m.width-2, height)
+	m.commandLine.SetWidth(m.width)
+	m.tabBar.SetWidth(m.width)
+	m.statusBar = m.newStatusBar()
+}
+
+func (m *Model) newStatusBar() statusbar.StatusBar {
+	width := m.width
+	if width == 0 {
+		width = 80
+	}
+	var filename string
+	if len(m.buffers) > 0 {
+		filename = m.buf().Name()
+	}
+	return newStatusBar(filename, width, m.statusStyle, m.keys)
+}
+
+func newStatusBar(filename string, width int, style statusbar.Style, keys KeyMap) statusbar.StatusBar {
+	sb := statusbar.New(filename, width, style)
+	sb.SetShortcuts(keys.statusHints())
+	return sb
+}
+
+// applyConfig überträgt geänderte Einstellungen auf alle Komponenten
+func (m *Model) applyConfig() {
+	for _, b := range m.buffers {
+		if b.diff != nil {
+			b.diff.SetStyle(diffview.NewStyleFromConfig(m.config))
+		}
+		if b.patch != nil {
+			b.patch.SetStyle(diffview.NewStyleFromConfig(m.config))
+		}
+		if b.json != nil {
+			b.json.SetStyle(jsonview.NewStyleFromConfig(m.config))
+		}
+		if b.log != nil {
+			b.log.SetStyle(logview.NewStyleFromConfig(m.config))
+		}
+		if b.table != nil {
+			b.table.SetStyle(tableview.NewStyleFromConfig(m.config))
 		}
+		if b.markdown != nil {
+			b.markdown.SetStyle(markdown.NewStyleFromConfig(m.config))
+		}
+	}
+	for _, p := range m.root.panes() {
+		p.applyConfig()
+	}
+	m.messages.SetStyle(messages.NewStyleFromConfig(m.config))
+	m.commandLine.SetStyle(commandline.NewStyleFromConfig(m.config))
+	m.helpView.SetStyle(helpview.NewStyleFromConfig(m.config))
+	m.tabBar.SetStyle(tabbar.NewStyleFromConfig(m.config))
+	m.fileList.SetStyle(listview.NewStyleFromConfig(m.config))
+	m.outline.SetStyle(listview.NewStyleFromConfig(m.config))
+	m.statusStyle = statusbar.NewStyleFromConfig(m.config)
+	m.statusBar = m.newStatusBar()
+}
+
+// updateLog verarbeitet Tasten, solange das Meldungsprotokoll offen ist
+func (m *Model) updateLog(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Close, m.keys.Messages):
+		m.showLog = false
+	case key.Matches(msg, m.keys.ScrollUp):
+		m.messages.ScrollUp(1)
+	case key.Matches(msg, m.keys.ScrollDown):
+		m.messages.ScrollDown(1)
+	case key.Matches(msg, m.keys.ScrollPageUp):
+		m.messages.ScrollUp(m.tv().GetViewport().Height)
+	case key.Matches(msg, m.keys.ScrollPageDn):
+		m.messages.ScrollDown(m.tv().GetViewport().Height)
+	}
+}
+
+// updateHelp verarbeitet Tasten, solange die Hilfe offen ist
+func (m *Model) updateHelp(msg tea.KeyMsg) {
+	if m.helpView.Filtering() {
+		m.helpView.UpdateFilter(msg)
+		return
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Close, m.keys.Help):
+		m.showHelp = false
+	case key.Matches(msg, m.keys.FilterHelp):
+		m.helpView.StartFilter()
+	case key.Matches(msg, m.keys.ScrollUp):
+		m.helpView.ScrollUp(1)
+	case key.Matches(msg, m.keys.ScrollDown):
+		m.helpView.ScrollDown(1)
+	case key.Matches(msg, m.keys.ScrollPageUp):
+		m.helpView.ScrollUp(m.tv().GetViewport().Height)
+	case key.Matches(msg, m.keys.ScrollPageDn):
+		m.helpView.ScrollDown(m.tv().GetViewport().Height)
 	}
-	return searchHitMsg{hits: hits}
 }
 
 func (m *Model) jumpToLine(line int) {
-	m.textView.ScrollToLine(line)
+	m.tv().ScrollToLine(line)
+}
+
+// jumpNext springt zur nächsten bzw. vorherigen der sortierten Zeilen
+// (1-basiert) und meldet, ob dabei am Ende umgebrochen wurde
+func (m *Model) jumpNext(lines []int, forward bool) (wrapped bool) {
+	current := m.tv().GetCurrentLine()
+	if forward {
+		for _, line := range lines {
+			if line > current {
+				m.jumpToLine(line)
+				return false
+			}
+		}
+		m.jumpToLine(lines[0])
+		return true
+	}
+
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i] < current {
+			m.jumpToLine(lines[i])
+			return false
+		}
+	}
+	m.jumpToLine(lines[len(lines)-1])
+	return true
 }
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/fase22/tui/internal/ui/components/logview"
+	"github.com/fase22/tui/internal/ui/components/messages"
+)
+
+// logDetails ist das Pane, das den Eintrag unter dem Cursor einer
+// NDJSON-Ansicht vollständig als JSON-Baum zeigt
+type logDetails struct {
+	pane   *Pane
+	buf    *Buffer // Erzeugter Buffer mit dem Eintrag
+	of     *Buffer // NDJSON-Buffer, dessen Einträge gezeigt werden
+	record *logview.Record
+}
+
+// toggleDetails öffnet oder schließt das Detail-Pane
+func (m *Model) toggleDetails() tea.Cmd {
+	if m.details != nil {
+		m.closeDetails()
+		return nil
+	}
+
+	b := m.buf()
+	if b.mode != modeNDJSON || b.log == nil {
+		return nil
+	}
+	details := &Buffer{name: "Details", state: bufferReady}
+	m.buffers = append(m.buffers, details)
+
+	main := m.focus
+	p := m.splitPane(splitHorizontal)
+	p.setBuffer(details)
+	m.setFocus(main)
+	m.details = &logDetails{pane: p, buf: details, of: b}
+	m.syncDetails()
+	m.resize()
+	return nil
+}
+
+// closeDetails schließt das Detail-Pane und verwirft seinen Buffer
+func (m *Model) closeDetails() {
+	d := m.details
+	m.details = nil
+	if leaf := m.root.find(d.pane); leaf != nil && len(m.root.panes()) > 1 {
+		leaf.remove()
+		if m.focus == d.pane {
+			m.setFocus(m.root.panes()[0])
+		}
+	}
+	for i, b := range m.buffers {
+		if b == d.buf {
+			m.buffers = append(m.buffers[:i], m.buffers[i+1:]...)
+			break
+		}
+	}
+	for _, p := range m.root.panes() {
+		p.forget(d.buf)
+		if p.buf == d.buf {
+			p.setBuffer(d.of)
+		}
+	}
+	m.resize()
+}
+
+// syncDetails zeigt im Detail-Pane den Eintrag unter dem Cursor des
+// NDJSON-Buffers. Wurde das Pane oder einer der Buffer geschlossen oder die
+// Darstellung gewechselt, wird auch das Detail-Pane verworfen.
+func (m *Model) syncDetails() {
+	d := m.details
+	if d == nil {
+		return
+	}
+	if m.root.find(d.pane) == nil || d.pane.buf != d.buf || !m.hasBuffer(d.buf) || !m.hasBuffer(d.of) || d.of.mode != modeNDJSON {
+		m.closeDetails()
+		return
+	}
+	if m.focus.buf != d.of || d.of.log == nil || d.of.state != bufferReady {
+		return
+	}
+
+	record := d.of.log.RecordAt(m.tv().GetCurrentLine())
+	if record == d.record {
+		return
+	}
+	d.record = record
+
+	b := d.buf
+	b.json = nil
+	b.source = ""
+	b.name = "Details"
+	if record != nil {
+		b.source = record.Raw
+		b.name = fmt.Sprintf("Details Zeile %d", record.Line+1)
+	}
+	if record == nil || record.Node == nil || m.setMode(b, modeJSON) != nil {
+		m.setMode(b, modeText)
+	}
+	m.refreshContent(b)
+	d.pane.view().ScrollToTop()
+}
+
+// hasBuffer meldet, ob der Buffer noch geöffnet ist
+func (m *Model) hasBuffer(b *Buffer) bool {
+	for _, other := range m.buffers {
+		if other == b {
+			return true
+		}
+	}
+	return false
+}
+
+// ndjson liefert die Spaltenansicht des aktuellen Buffers
+func (m *Model) ndjson() (*logview.View, error) {
+	b := m.buf()
+	if b.mode != modeNDJSON || b.log == nil || b.hex {
+		return nil, fmt.Errorf("Nur in der NDJSON-Darstellung möglich")
+	}
+	return b.log, nil
+}
+
+// refreshLog überträgt geänderte Spalten oder Filter in die Ansichten. Der
+// Cursor bleibt nach Möglichkeit auf demselben Eintrag.
+func (m *Model) refreshLog(update func(v *logview.View)) {
+	b := m.buf()
+	record := b.log.RecordAt(m.tv().GetCurrentLine())
+	update(b.log)
+	b.content = rope.New(b.log.Content())
+	m.refreshContent(b)
+
+	if line := b.log.Line(record); line > 0 {
+		m.jumpToLine(line)
+	} else {
+		m.tv().ScrollToTop()
+	}
+}
+
+func (m *Model) cmdColumns(args string, _ bool) tea.Cmd {
+	if m.buf().mode == modeTable {
+		return m.tableColumns(args)
+	}
+	v, err := m.ndjson()
+	if err != nil {
+		return messages.Error(err)
+	}
+	m.refreshLog(func(v *logview.View) {
+		v.SetColumns(strings.Fields(args))
+	})
+	return messages.Info("Spalten: %s", strings.Join(v.Columns(), " "))
+}
+
+func (m *Model) cmdWhere(args string, _ bool) tea.Cmd {
+	v, err := m.ndjson()
+	if err != nil {
+		return messages.Error(err)
+	}
+	conds, err := logview.ParseFilter(args)
+	if err != nil {
+		return messages.Error(err)
+	}
+	m.refreshLog(func(v *logview.View) {
+		v.SetFilter(conds)
+	})
+	if len(conds) == 0 {
+		return messages.Info("Feldfilter aufgehoben")
+	}
+	shown, total := v.Rows()
+	return messages.Info("Feldfilter %q: %d von %d Zeilen", args, shown, total)
+}
+
+// completeField vervollständigt die Felder bzw. Spalten des aktuellen
+// Buffers
+func (m *Model) completeField(arg string) []string {
+	b := m.buf()
+	switch {
+	case b.mode == modeTable && b.table != nil:
+		return filterPrefix(b.table.Columns(), arg)
+	case b.log != nil:
+		return filterPrefix(b.log.Fields(), arg)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/file"
+)
+
+type optionKind int
+
+const (
+	optionBool optionKind = iota
+	optionInt
+	optionString
+)
+
+// option beschreibt eine zur Laufzeit über :set änderbare Einstellung
+type option struct {
+	name    string
+	aliases []string
+	kind    optionKind
+	values  []string // Erlaubte Werte für optionString, leer = beliebig
+	get     func(cfg *config.Config) string
+	set     func(cfg *config.Config, value string) error
+}
+
+// options enthält alle Laufzeit-Schalter aus config.Config
+var options = []option{
+	boolOption("number", []string{"nu"}, func(cfg *config.Config) *bool { return &cfg.Editor.ShowLineNumbers }),
+	boolOption("wrap", nil, func(cfg *config.Config) *bool { return &cfg.Editor.WordWrap }),
+	boolOption("autoindent", []string{"ai"}, func(cfg *config.Config) *bool { return &cfg.Editor.AutoIndent }),
+	boolOption("scrollbar", nil, func(cfg *config.Config) *bool { return &cfg.UI.ShowScrollbar }),
+	boolOption("statusline", []string{"status"}, func(cfg *config.Config) *bool { return &cfg.UI.ShowStatus }),
+	boolOption("markendings", []string{"mle"}, func(cfg *config.Config) *bool { return &cfg.Editor.MarkLineEndings }),
+	{
+		name:    "tabwidth",
+		aliases: []string{"ts", "tabstop"},
+		kind:    optionInt,
+		get:     func(cfg *config.Config) string { return strconv.Itoa(cfg.Editor.TabWidth) },
+		set: func(cfg *config.Config, value string) error {
+			width, err := strconv.Atoi(value)
+			if err != nil || width < 1 || width > 16 {
+				return fmt.Errorf("Ungültige Tabbreite: %s", value)
+			}
+			cfg.Editor.TabWidth = width
+			return nil
+		},
+	},
+	{
+		name:   "scrollstyle",
+		kind:   optionString,
+		values: []string{"bar", "block"},
+		get:    func(cfg *config.Config) string { return cfg.UI.ScrollStyle },
+		set: func(cfg *config.Config, value string) error {
+			cfg.UI.ScrollStyle = value
+			return nil
+		},
+	},
+	{
+		name:    "encoding",
+		aliases: []string{"enc", "fileencoding", "fenc"},
+		kind:    optionString,
+		values:  file.EncodingNames(),
+		get:     func(cfg *config.Config) string { return cfg.Editor.Encoding },
+		set: func(cfg *config.Config, value string) error {
+			enc, err := file.ParseEncoding(value)
+			if err != nil {
+				return err
+			}
+			cfg.Editor.Encoding = enc.String()
+			return nil
+		},
+	},
+	{
+		name:   "theme",
+		kind:   optionString,
+		values: themeNames(),
+		get:    func(cfg *config.Config) string { return string(cfg.Theme.Name) },
+		set: func(cfg *config.Config, value string) error {
+			theme, ok := config.ThemeByName(config.ThemeName(value))
+			if !ok {
+				return fmt.Errorf("Unbekanntes Theme: %s", value)
+			}
+			cfg.Theme = theme
+			return nil
+		},
+	},
+}
+
+func boolOption(name string, aliases []string, field func(cfg *config.Config) *bool) option {
+	return option{
+		name:    name,
+		aliases: aliases,
+		kind:    optionBool,
+		get:     func(cfg *config.Config) string { return strconv.FormatBool(*field(cfg)) },
+		set: func(cfg *config.Config, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("Ungültiger Wert für %s: %s", name, value)
+			}
+			*field(cfg) = enabled
+			return nil
+		},
+	}
+}
+
+func themeNames() []string {
+	var names []string
+	for _, theme := range config.Themes() {
+		names = append(names, string(theme.Name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func findOption(name string) (*option, bool) {
+	for i := range options {
+		opt := &options[i]
+		if opt.name == name {
+			return opt, true
+		}
+		for _, alias := range opt.aliases {
+			if alias == name {
+				return opt, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// applySetting wertet ein einzelnes :set-Argument aus, z. B. "wrap", "nowrap",
+// "wrap!", "wrap?" oder "tabwidth=8". Abfragen liefern den Wert als Text zurück.
+func applySetting(cfg *config.Config, arg string) (string, error) {
+	name, value, hasValue := strings.Cut(arg, "=")
+
+	switch {
+	case strings.HasSuffix(name, "?"):
+		opt, ok := findOption(strings.TrimSuffix(name, "?"))
+		if !ok {
+			return "", fmt.Errorf("Unbekannte Option: %s", name)
+		}
+		return fmt.Sprintf("%s=%s", opt.name, opt.get(cfg)), nil
+
+	case hasValue:
+		opt, ok := findOption(name)
+		if !ok {
+			return "", fmt.Errorf("Unbekannte Option: %s", name)
+		}
+		if len(opt.values) > 0 && !contains(opt.values, value) {
+			return "", fmt.Errorf("Ungültiger Wert für %s: %s (erlaubt: %s)",
+				opt.name, value, strings.Join(opt.values, ", "))
+		}
+		return "", opt.set(cfg, value)
+	}
+
+	toggle := strings.HasSuffix(name, "!")
+	name = strings.TrimSuffix(name, "!")
+
+	enable := true
+	opt, ok := findOption(name)
+	if !ok && strings.HasPrefix(name, "no") {
+		opt, ok = findOption(strings.TrimPrefix(name, "no"))
+		enable = false
+	}
+	if !ok {
+		return "", fmt.Errorf("Unbekannte Option: %s", name)
+	}
+
+	if opt.kind != optionBool {
+		// Nicht-boolesche Optionen ohne Wert werden abgefragt
+		return fmt.Sprintf("%s=%s", opt.name, opt.get(cfg)), nil
+	}
+	if toggle {
+		enable = opt.get(cfg) != "true"
+	}
+	return "", opt.set(cfg, strconv.FormatBool(enable))
+}
+
+// describeOptions fasst alle Optionen mit ihren aktuellen Werten zusammen
+func describeOptions(cfg *config.Config) string {
+	var parts []string
+	for _, opt := range options {
+		value := opt.get(cfg)
+		switch {
+		case opt.kind != optionBool:
+			parts = append(parts, fmt.Sprintf("%s=%s", opt.name, value))
+		case value == "true":
+			parts = append(parts, opt.name)
+		default:
+			parts = append(parts, "no"+opt.name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// optionCandidates liefert alle Schreibweisen, die :set akzeptiert
+func optionCandidates() []string {
+	var candidates []string
+	for _, opt := range options {
+		switch opt.kind {
+		case optionBool:
+			candidates = append(candidates, opt.name, "no"+opt.name)
+		default:
+			if len(opt.values) == 0 {
+				candidates = append(candidates, opt.name+"=")
+			}
+			for _, value := range opt.values {
+				candidates = append(candidates, opt.name+"="+value)
+			}
+		}
+	}
+	return candidates
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
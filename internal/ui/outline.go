@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/outline"
+	"github.com/fase22/tui/internal/ui/components/jsonview"
+	"github.com/fase22/tui/internal/ui/components/listview"
+	"github.com/fase22/tui/internal/ui/components/messages"
+)
+
+const outlineTitle = "Gliederung"
+
+// outlineVisible meldet, ob die Gliederung neben dem Text angezeigt wird
+func (m *Model) outlineVisible() bool {
+	return m.showOutline && len(m.buffers) > 0 && !m.buf().hex
+}
+
+func (m *Model) outlineWidth() int {
+	if !m.outlineVisible() {
+		return 0
+	}
+	return min(max(m.width/4, minFileListWidth), maxFileListWidth)
+}
+
+// symbols liefert die Gliederung eines Buffers mit Zeilen der aktuellen
+// Darstellung. Einträge, die in der Darstellung keine Zeile haben, fehlen.
+func (m *Model) symbols(b *Buffer) []outline.Symbol {
+	if b.state != bufferReady {
+		return nil
+	}
+
+	// Im JSON-Baum folgen die Zeilen dem Auf- und Zuklappen
+	if b.mode == modeJSON && b.json != nil {
+		root := b.json.Root()
+		if root.Kind != jsonview.Object {
+			return nil
+		}
+		symbols := make([]outline.Symbol, len(root.Children))
+		for i, child := range root.Children {
+			symbols[i] = outline.Symbol{Name: child.Key, Kind: "key", Level: 1, Line: b.json.Line(child)}
+		}
+		return symbols
+	}
+
+	if b.symbols == nil {
+		b.symbols = outline.Extract(b.Name(), b.sourceText())
+		if b.symbols == nil {
+			b.symbols = []outline.Symbol{}
+		}
+	}
+	var symbols []outline.Symbol
+	for _, s := range b.symbols {
+		if line := displayLine(b, s.Line); line > 0 {
+			s.Line = line
+			symbols = append(symbols, s)
+		}
+	}
+	return symbols
+}
+
+// syncOutline übernimmt die Gliederung des angezeigten Buffers gefiltert in
+// die Liste und markiert den Eintrag, in dessen Abschnitt der Cursor steht.
+// Beim Tippen bleibt die Gliederung stehen, wie Suchtreffer und Marken
+// folgt sie beim Verlassen des Einfügemodus.
+func (m *Model) syncOutline() {
+	if !m.outlineVisible() {
+		return
+	}
+	b := m.buf()
+	stale := m.outlineContent != b.content && m.mode != ModeInsert
+	if m.outlineOf != b || stale || m.outlineQuery != m.outlineFilter {
+		m.outlineItems = m.outlineItems[:0]
+		var items []listview.Item
+		for _, s := range m.symbols(b) {
+			if !outline.Match(m.outlineFilter, s.Name) {
+				continue
+			}
+			m.outlineItems = append(m.outlineItems, s)
+			items = append(items, listview.Item{
+				Title:  strings.Repeat("  ", max(s.Level-1, 0)) + s.Name,
+				Detail: s.Kind,
+			})
+		}
+		m.outline.SetItems(items)
+		m.outlineOf, m.outlineContent, m.outlineQuery = b, b.content, m.outlineFilter
+	}
+
+	title := outlineTitle
+	if m.outlineFilter != "" || m.outline.Focused() {
+		title += " /" + m.outlineFilter
+	}
+	m.outline.SetTitle(title)
+
+	line := m.tv().GetCurrentLine()
+	current := -1
+	for i, s := range m.outlineItems {
+		if s.Line > line {
+			break
+		}
+		current = i
+	}
+	m.outline.SetCurrent(current)
+}
+
+// toggleOutline blendet die Gliederung ein und gibt ihr den Fokus bzw. gibt
+// den Fokus an den Text zurück
+func (m *Model) toggleOutline() tea.Cmd {
+	switch {
+	case !m.showOutline:
+		m.showOutline = true
+		m.outline.Focus()
+		m.resize()
+	case m.outline.Focused():
+		m.outline.Blur()
+	default:
+		m.outline.Focus()
+	}
+	m.syncOutline()
+	if len(m.outlineItems) == 0 && m.outlineFilter == "" {
+		return messages.Info("Keine Gliederung für %s", m.buf().Title())
+	}
+	return nil
+}
+
+// cmdOutline blendet die Gliederung ein oder aus
+func (m *Model) cmdOutline(_ string, _ bool) tea.Cmd {
+	m.showOutline = !m.showOutline
+	if !m.showOutline {
+		m.outline.Blur()
+	}
+	m.resize()
+	return nil
+}
+
+// updateOutline verarbeitet Tasten, solange die Gliederung den Fokus hat.
+// Getippte Zeichen filtern die Einträge.
+func (m *Model) updateOutline(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit(false)
+	case tea.KeyEsc:
+		// Erst den Filter aufheben, dann den Fokus abgeben
+		if m.outlineFilter != "" {
+			m.outlineFilter = ""
+		} else {
+			m.outline.Blur()
+		}
+	case tea.KeyUp:
+		m.outline.MoveUp(1)
+	case tea.KeyDown:
+		m.outline.MoveDown(1)
+	case tea.KeyPgUp:
+		m.outline.MoveUp(m.tv().GetViewport().Height)
+	case tea.KeyPgDown:
+		m.outline.MoveDown(m.tv().GetViewport().Height)
+	case tea.KeyBackspace:
+		if runes := []rune(m.outlineFilter); len(runes) > 0 {
+			m.outlineFilter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.outlineFilter += string(msg.Runes)
+	case tea.KeyEnter:
+		// Zum ausgewählten Eintrag springen, der Fokus geht an den Text zurück
+		if index := m.outline.Selected(); index >= 0 && index < len(m.outlineItems) {
+			tv := m.tv()
+			offset := tv.GetViewport().YOffset
+			m.jumpToLine(m.outlineItems[index].Line)
+			m.syncScroll(m.focus, tv, offset)
+		}
+		m.outline.Blur()
+	}
+	m.syncOutline()
+	return nil
+}
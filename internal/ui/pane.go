@@ -0,0 +1,390 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/fase22/tui/internal/config"
+	"github.com/fase22/tui/internal/ui/components/hexview"
+	"github.com/fase22/tui/internal/ui/components/scrollbar"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// paneStyle enthält die Styles für Titelzeile und Trenner der Panes
+type paneStyle struct {
+	Title        lipgloss.Style
+	FocusedTitle lipgloss.Style
+	Separator    lipgloss.Style
+	Error        lipgloss.Style
+	LineEnding   lipgloss.Style // Abweichende Zeilenenden
+	Mark         lipgloss.Style // Marken in der Randspalte
+}
+
+func newPaneStyle(cfg *config.Config) paneStyle {
+	theme := cfg.Theme
+
+	return paneStyle{
+		Title: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.LineNumbers)).
+			Background(lipgloss.Color(theme.Selection)),
+
+		FocusedTitle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Background)).
+			Background(lipgloss.Color(theme.Accent)).
+			Bold(true),
+
+		Separator: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Selection)).
+			Background(lipgloss.Color(theme.Background)),
+
+		Error: errorStyle,
+
+		LineEnding: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Changed)),
+
+		Mark: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(theme.Accent)).
+			Bold(true),
+	}
+}
+
+// Pane zeigt einen Buffer mit eigener Textansicht, Scrollbar und Position.
+// Für jeden Buffer, den das Pane schon angezeigt hat, bleibt die Ansicht
+// erhalten, damit die Scrollposition beim Zurückwechseln stimmt.
+type Pane struct {
+	buf         *Buffer
+	views       map[*Buffer]*textview.TextView
+	hexViews    map[*Buffer]*hexview.HexView
+	config      *config.Config
+	tvStyle     textview.Style
+	hexStyle    hexview.Style
+	scrollStyle scrollbar.Style
+	style       paneStyle
+	focused     bool
+	showTitle   bool
+	scrollBind  bool
+	x, y        int // Position im Layout für die Fokusnavigation
+	width       int
+	height      int
+}
+
+func newPane(buf *Buffer, cfg *config.Config) *Pane {
+	return &Pane{
+		buf:         buf,
+		views:       make(map[*Buffer]*textview.TextView),
+		hexViews:    make(map[*Buffer]*hexview.HexView),
+		config:      cfg,
+		tvStyle:     textview.NewStyleFromConfig(cfg),
+		hexStyle:    hexview.NewStyleFromConfig(cfg),
+		scrollStyle: scrollbar.NewStyleFromConfig(cfg),
+		style:       newPaneStyle(cfg),
+		width:       80,
+		height:      24,
+	}
+}
+
+// clone erzeugt ein neues Pane auf denselben Buffer an derselben Position
+func (p *Pane) clone() *Pane {
+	c := newPane(p.buf, p.config)
+	c.SetSize(p.width, p.height)
+	if p.buf.state == bufferReady {
+		c.view().ScrollToLine(p.view().GetCurrentLine())
+	}
+	return c
+}
+
+// view liefert die Ansicht des angezeigten Buffers und legt sie bei Bedarf an
+func (p *Pane) view() *textview.TextView {
+	return p.viewFor(p.buf)
+}
+
+func (p *Pane) viewFor(b *Buffer) *textview.TextView {
+	if tv, ok := p.views[b]; ok {
+		return tv
+	}
+
+	width, height := p.textSize()
+	tv := textview.New(width, height, p.viewConfig(b))
+	tv.SetFilter(b.filter)
+	p.decorate(&tv, b)
+	if len(b.searchHits) > 0 || b.searching != nil {
+		tv.SetSearchTerm(b.searchQuery)
+	}
+	if b.state == bufferReady {
+		tv.SetText(b.content)
+	}
+	p.views[b] = &tv
+	return &tv
+}
+
+// hexView liefert die Hexansicht des angezeigten Buffers
+func (p *Pane) hexView() *hexview.HexView {
+	return p.hexViewFor(p.buf)
+}
+
+func (p *Pane) hexViewFor(b *Buffer) *hexview.HexView {
+	if hv, ok := p.hexViews[b]; ok {
+		return hv
+	}
+
+	width, height := p.textSize()
+	hv := hexview.New(width, height, p.hexStyle)
+	if b.state == bufferReady {
+		pager := b.hexPager()
+		hv.SetSource(pager, pager.Size())
+	}
+	p.hexViews[b] = &hv
+	return &hv
+}
+
+// eachHexView ruft fn für die Hexansicht des Buffers auf, sofern es eine gibt
+func (p *Pane) eachHexView(b *Buffer, fn func(hv *hexview.HexView)) {
+	if hv, ok := p.hexViews[b]; ok {
+		fn(hv)
+	}
+}
+
+// decorate überträgt die Decorators eines Buffers auf eine Ansicht
+func (p *Pane) decorate(tv *textview.TextView, b *Buffer) {
+	if b.diff != nil {
+		tv.SetDecorator("diff", b.diff)
+	}
+	if b.patch != nil {
+		tv.SetDecorator("patch", b.patch)
+	} else {
+		tv.SetDecorator("patch", nil)
+	}
+	if b.mode == modeJSON && b.json != nil {
+		tv.SetDecorator("json", b.json)
+	} else {
+		tv.SetDecorator("json", nil)
+	}
+	if b.mode == modeNDJSON && b.log != nil {
+		tv.SetDecorator("log", b.log)
+	} else {
+		tv.SetDecorator("log", nil)
+	}
+	if b.mode == modeMarkdown && b.markdown != nil {
+		tv.SetDecorator("markdown", b.markdown)
+	} else {
+		tv.SetDecorator("markdown", nil)
+	}
+	if b.mode == modeTable && b.table != nil {
+		tv.SetDecorator("table", b.table)
+		tv.SetHeader(b.table.Header())
+	} else {
+		tv.SetDecorator("table", nil)
+		tv.SetHeader("", nil)
+	}
+	if len(b.markLines) > 0 {
+		tv.SetDecorator("marks", newMarkGutter(b.markLines, p.style.Mark))
+	} else {
+		tv.SetDecorator("marks", nil)
+	}
+	// Abweichende Zeilenenden gehören zu den Zeilen der unveränderten Datei
+	if p.config.Editor.MarkLineEndings && b.info.LineEndings.Mixed() &&
+		b.mode == modeText && !b.modified() {
+		tv.SetDecorator("endings", newEndingMarks(b.info.LineEndings, p.style.LineEnding))
+	} else {
+		tv.SetDecorator("endings", nil)
+	}
+}
+
+// showLineNumbers meldet, ob die Ansicht eines Buffers Zeilennummern zeigt.
+// Diffs bringen eigene Zeilennummern in der Randspalte mit.
+func (p *Pane) showLineNumbers(b *Buffer) bool {
+	return overrideOr(b.numbers, p.config.Editor.ShowLineNumbers) && b.diff == nil
+}
+
+// wordWrap meldet, ob die Ansicht eines Buffers Zeilen umbricht
+func (p *Pane) wordWrap(b *Buffer) bool {
+	return overrideOr(b.wrap, p.config.Editor.WordWrap)
+}
+
+// setBuffer zeigt einen anderen Buffer im Pane an
+func (p *Pane) setBuffer(b *Buffer) {
+	p.buf = b
+	p.viewFor(b)
+}
+
+// forget verwirft die Ansicht eines geschlossenen Buffers
+func (p *Pane) forget(b *Buffer) {
+	delete(p.views, b)
+	delete(p.hexViews, b)
+}
+
+// eachView ruft fn für die Ansicht des Buffers auf, sofern das Pane eine hat
+func (p *Pane) eachView(b *Buffer, fn func(tv *textview.TextView)) {
+	if tv, ok := p.views[b]; ok {
+		fn(tv)
+	}
+}
+
+func (p *Pane) Focus() {
+	p.focused = true
+}
+
+func (p *Pane) Blur() {
+	p.focused = false
+}
+
+func (p *Pane) Focused() bool {
+	return p.focused
+}
+
+// SetSize setzt die Gesamtgröße inklusive Titelzeile und Scrollbar
+func (p *Pane) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+
+	textWidth, textHeight := p.textSize()
+	for _, tv := range p.views {
+		tv.Resize(textWidth, textHeight)
+	}
+	for _, hv := range p.hexViews {
+		hv.Resize(textWidth, textHeight)
+	}
+}
+
+// textSize liefert die Größe, die der Textansicht bleibt
+func (p *Pane) textSize() (int, int) {
+	width, height := p.width, p.height
+	if p.config.UI.ShowScrollbar {
+		width--
+	}
+	if p.showTitle {
+		height--
+	}
+	return max(width, 1), max(height, 1)
+}
+
+// applyConfig überträgt geänderte Einstellungen auf alle Ansichten
+func (p *Pane) applyConfig() {
+	p.tvStyle = textview.NewStyleFromConfig(p.config)
+	p.scrollStyle = scrollbar.NewStyleFromConfig(p.config)
+	p.hexStyle = hexview.NewStyleFromConfig(p.config)
+	p.style = newPaneStyle(p.config)
+
+	for _, hv := range p.hexViews {
+		hv.SetStyle(p.hexStyle)
+	}
+
+	// Neu aufgebaut wird eine Ansicht nur, wenn sich ihr Layout ändert
+	for b, tv := range p.views {
+		p.decorate(tv, b)
+		tv.SetConfig(p.viewConfig(b))
+	}
+	p.SetSize(p.width, p.height)
+}
+
+// viewConfig liefert die Einstellungen der Textansicht eines Buffers
+func (p *Pane) viewConfig(b *Buffer) textview.Config {
+	return textview.Config{
+		ShowLineNumbers: p.showLineNumbers(b),
+		TabWidth:        p.config.Editor.TabWidth,
+		WordWrap:        p.wordWrap(b),
+		Style:           p.tvStyle,
+	}
+}
+
+func (p *Pane) Init() tea.Cmd {
+	return nil
+}
+
+// Update übernimmt neu geladene Inhalte in die passende Ansicht
+func (p *Pane) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case fileLoadedMsg:
+		p.eachView(msg.buf, func(tv *textview.TextView) {
+			p.decorate(tv, msg.buf)
+			tv.SetText(msg.buf.content)
+		})
+		p.eachHexView(msg.buf, func(hv *hexview.HexView) {
+			pager := msg.buf.hexPager()
+			hv.SetSource(pager, pager.Size())
+		})
+	}
+	return p, nil
+}
+
+func (p *Pane) View() string {
+	return p.Render()
+}
+
+func (p *Pane) Render() string {
+	var body string
+	switch {
+	case p.buf.state == bufferFailed:
+		body = p.style.Error.Render(p.buf.err.Error())
+	case p.buf.hex:
+		hv := p.hexView()
+		width, height := p.textSize()
+		body = fitBlock(hv.Render(), width, height)
+		if p.config.UI.ShowScrollbar {
+			sb := scrollbar.New(hv.Height(), hv.Rows(), hv.Top(), p.scrollStyle)
+			body = lipgloss.JoinHorizontal(lipgloss.Left, body, sb.Render())
+		}
+	case p.config.UI.ShowScrollbar:
+		tv := p.view()
+		sb := scrollbar.New(
+			tv.GetViewport().Height,
+			tv.GetDisplayLines(),
+			tv.GetViewport().YOffset,
+			p.scrollStyle,
+		)
+		// Die Scrollbar beginnt unterhalb einer festen Kopfzeile
+		bar := strings.Repeat(" \n", tv.HeaderHeight()) + sb.Render()
+		body = lipgloss.JoinHorizontal(lipgloss.Left, tv.Render(), bar)
+	default:
+		body = p.view().Render()
+	}
+
+	_, textHeight := p.textSize()
+	body = fitBlock(body, p.width, textHeight)
+	if !p.showTitle {
+		return body
+	}
+	return p.renderTitle() + "\n" + body
+}
+
+func (p *Pane) renderTitle() string {
+	title := " " + p.buf.Title()
+	if p.buf.modified() {
+		title += " [+]"
+	}
+	if p.scrollBind {
+		title += " [gebunden]"
+	}
+
+	style := p.style.Title
+	if p.focused {
+		style = p.style.FocusedTitle
+	}
+	return style.Render(fitLine(title, p.width))
+}
+
+// fitBlock bringt einen gerenderten Block auf genau width × height Zellen
+func fitBlock(block string, width, height int) string {
+	lines := strings.Split(strings.TrimSuffix(block, "\n"), "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		lines[i] = fitLine(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fitLine kürzt oder füllt eine (ggf. gestylte) Zeile auf die Breite
+func fitLine(line string, width int) string {
+	w := lipgloss.Width(line)
+	if w > width {
+		return ansi.Truncate(line, width, "")
+	}
+	return line + strings.Repeat(" ", width-w)
+}
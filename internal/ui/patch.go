@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/diff"
+	"github.com/fase22/tui/internal/ui/components/diffview"
+	"github.com/fase22/tui/internal/ui/components/listview"
+	"github.com/fase22/tui/internal/ui/components/messages"
+)
+
+const (
+	minFileListWidth = 20
+	maxFileListWidth = 40
+)
+
+// detectPatch erkennt, ob ein geladener Buffer ein Unified Diff ist
+func (m *Model) detectPatch(b *Buffer) {
+	b.patch = nil
+	if b.diff != nil {
+		return
+	}
+	if patch, ok := diff.ParsePatch(strings.Split(b.sourceText(), "\n")); ok {
+		b.patch = diffview.NewPatchView(patch, diffview.NewStyleFromConfig(m.config))
+	}
+}
+
+// patchLines rechnet 0-basierte Zeilen eines Patches in 1-basierte um
+func patchLines(lines []int) []int {
+	converted := make([]int, len(lines))
+	for i, line := range lines {
+		converted[i] = line + 1
+	}
+	return converted
+}
+
+// fileListVisible meldet, ob die Dateiliste neben dem Text angezeigt wird
+func (m *Model) fileListVisible() bool {
+	return m.showFiles && len(m.buffers) > 0 && m.buf().patch != nil
+}
+
+func (m *Model) fileListWidth() int {
+	if !m.fileListVisible() {
+		return 0
+	}
+	return min(max(m.width/4, minFileListWidth), maxFileListWidth)
+}
+
+// syncFileList übernimmt die Dateien des angezeigten Patches in die Liste
+// und markiert die Datei unter dem Cursor
+func (m *Model) syncFileList() {
+	b := m.buf()
+	if b.patch == nil {
+		return
+	}
+
+	patch := b.patch.Patch()
+	if m.filesOf != patch {
+		items := make([]listview.Item, len(patch.Files))
+		for i, f := range patch.Files {
+			items[i] = listview.Item{
+				Title:  f.Name,
+				Detail: fmt.Sprintf("+%d -%d", f.Added, f.Removed),
+			}
+		}
+		m.fileList.SetItems(items)
+		m.filesOf = patch
+	}
+	m.fileList.SetCurrent(patch.FileAt(m.tv().GetCurrentLine() - 1))
+}
+
+// patchContext beschreibt Datei und Hunk unter dem Cursor für die Statusleiste
+func (m *Model) patchContext() string {
+	b := m.buf()
+	if b.patch == nil {
+		return ""
+	}
+
+	patch := b.patch.Patch()
+	line := m.tv().GetCurrentLine() - 1
+	index := patch.FileAt(line)
+	if index < 0 {
+		return ""
+	}
+
+	f := patch.Files[index]
+	context := fmt.Sprintf("%s (%d/%d)", f.Name, index+1, len(patch.Files))
+	if hunk := patch.HunkAt(index, line); hunk >= 0 {
+		context += fmt.Sprintf(" Hunk %d/%d", hunk+1, len(f.Hunks))
+	}
+	return context
+}
+
+// jumpFile springt zur nächsten oder vorherigen Datei eines Patches
+func (m *Model) jumpFile(forward bool) tea.Cmd {
+	b := m.buf()
+	if b.patch == nil {
+		return messages.Warn("Kein Patch im aktuellen Buffer")
+	}
+
+	if m.jumpNext(patchLines(b.patch.Patch().FileLines()), forward) {
+		if forward {
+			return messages.Info("Letzte Datei erreicht, weiter am Anfang")
+		}
+		return messages.Info("Erste Datei erreicht, weiter am Ende")
+	}
+	return nil
+}
+
+// toggleFiles blendet die Dateiliste ein und gibt ihr den Fokus bzw. gibt
+// den Fokus an den Text zurück
+func (m *Model) toggleFiles() tea.Cmd {
+	if m.buf().patch == nil {
+		return messages.Warn("Kein Patch im aktuellen Buffer")
+	}
+
+	switch {
+	case !m.showFiles:
+		m.showFiles = true
+		m.fileList.Focus()
+		m.resize()
+	case m.fileList.Focused():
+		m.fileList.Blur()
+	default:
+		m.fileList.Focus()
+	}
+	return nil
+}
+
+// cmdFiles blendet die Dateiliste ein oder aus
+func (m *Model) cmdFiles(_ string, _ bool) tea.Cmd {
+	if m.buf().patch == nil {
+		return messages.Warn("Kein Patch im aktuellen Buffer")
+	}
+	m.showFiles = !m.showFiles
+	if !m.showFiles {
+		m.fileList.Blur()
+	}
+	m.resize()
+	return nil
+}
+
+// updateFiles verarbeitet Tasten, solange die Dateiliste den Fokus hat
+func (m *Model) updateFiles(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m.quit(false)
+	case key.Matches(msg, m.keys.Close, m.keys.Files):
+		m.fileList.Blur()
+	case key.Matches(msg, m.keys.ScrollUp):
+		m.fileList.MoveUp(1)
+	case key.Matches(msg, m.keys.ScrollDown):
+		m.fileList.MoveDown(1)
+	case key.Matches(msg, m.keys.ScrollPageUp):
+		m.fileList.MoveUp(m.tv().GetViewport().Height)
+	case key.Matches(msg, m.keys.ScrollPageDn):
+		m.fileList.MoveDown(m.tv().GetViewport().Height)
+	case key.Matches(msg, m.keys.Submit):
+		// Zur ausgewählten Datei springen, der Fokus geht an den Text zurück
+		if index := m.fileList.Selected(); index >= 0 && m.buf().patch != nil {
+			tv := m.tv()
+			offset := tv.GetViewport().YOffset
+			m.jumpToLine(m.buf().patch.Patch().Files[index].Line + 1)
+			m.syncScroll(m.focus, tv, offset)
+		}
+		m.fileList.Blur()
+	}
+	return nil
+}
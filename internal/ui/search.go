@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// searchBatch ist die Anzahl Zeilen, nach der eine laufende Suche ihre
+// Treffer meldet
+const searchBatch = 10000
+
+// searchMatch ist ein Treffer: Zeile (1-basiert) und Byte-Bereich in der
+// Zeile
+type searchMatch struct {
+	line  int
+	start int
+	end   int
+}
+
+// searchJob ist eine Suche, die im Hintergrund über einen Stand des
+// angezeigten Textes läuft. Ein Rope ändert sich nicht, der Text kann
+// währenddessen weiter bearbeitet werden.
+type searchJob struct {
+	query   string
+	lines   int // Zeilen insgesamt
+	done    int // Bisher durchsuchte Zeilen
+	cancel  context.CancelFunc
+	results chan searchHitMsg
+	jump    bool // Zum ersten Treffer springen und melden, wenn es keinen gibt
+	waiting bool // Es wartet bereits ein Kommando auf die Treffer
+}
+
+// searchHitMsg liefert die Treffer eines Abschnitts, done ist die Anzahl
+// der bis dahin durchsuchten Zeilen
+type searchHitMsg struct {
+	buf  *Buffer
+	job  *searchJob
+	hits []searchMatch
+	done int
+	last bool
+}
+
+// search startet die Suche nach dem Suchbegriff des Buffers und springt
+// zum ersten Treffer. Eine noch laufende Suche wird abgebrochen.
+func (m *Model) search(b *Buffer) {
+	b.searchIndex = 0
+	m.eachView(b, func(tv *textview.TextView) {
+		tv.SetSearchTerm(b.searchQuery) // Highlighting aktivieren
+	})
+	b.startSearch(true)
+}
+
+// startSearch berechnet die Treffer im Hintergrund neu. Ohne jump bleibt
+// der Cursor stehen und der Index des aktuellen Treffers erhalten, z. B.
+// wenn sich nur der Inhalt geändert hat. Auf die Treffer wartet das
+// Kommando aus searchCmds.
+func (b *Buffer) startSearch(jump bool) {
+	b.stopSearch()
+	b.searchHits = nil
+	if b.searchQuery == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &searchJob{
+		query:   b.searchQuery,
+		lines:   b.content.LineCount(),
+		cancel:  cancel,
+		results: make(chan searchHitMsg),
+		jump:    jump,
+	}
+	b.searching = job
+	go job.run(ctx, b, b.content)
+}
+
+// searchCmds liefert für neu gestartete Suchen das Kommando, das auf ihre
+// Treffer wartet. Suchen starten auch dort, wo kein Kommando zurückgegeben
+// werden kann, z. B. wenn der Inhalt neu aufgebaut wird.
+func (m *Model) searchCmds() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, b := range m.buffers {
+		if job := b.searching; job != nil && !job.waiting {
+			job.waiting = true
+			cmds = append(cmds, job.wait())
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// run durchsucht den Text und schickt die Treffer abschnittsweise, bis er
+// durchsucht ist oder die Suche abgebrochen wird
+func (job *searchJob) run(ctx context.Context, b *Buffer, text rope.Rope) {
+	defer close(job.results)
+
+	var hits []searchMatch
+	send := func(done int, last bool) bool {
+		select {
+		case job.results <- searchHitMsg{buf: b, job: job, hits: hits, done: done, last: last}:
+			hits = nil
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	lowQuery := strings.ToLower(job.query)
+	running := true
+	text.EachLine(0, func(i int, line string) bool {
+		hits = lineMatches(hits, i+1, line, lowQuery)
+		if (i+1)%searchBatch == 0 {
+			running = send(i+1, false)
+		}
+		return running
+	})
+	if running {
+		send(job.lines, true)
+	}
+}
+
+// wait liefert das Kommando, das auf den nächsten Abschnitt wartet
+func (job *searchJob) wait() tea.Cmd {
+	return func() tea.Msg {
+		if msg, ok := <-job.results; ok {
+			return msg
+		}
+		return nil
+	}
+}
+
+// receiveHits übernimmt die Treffer eines Abschnitts. Mit dem ersten
+// Treffer springt der Cursor bei einer neuen Suche dorthin, die übrigen
+// folgen im Hintergrund.
+func (m *Model) receiveHits(msg searchHitMsg) tea.Cmd {
+	b, job := msg.buf, msg.job
+	if b.searching != job {
+		return nil // Abgebrochen oder von einer neuen Suche abgelöst
+	}
+
+	first := len(b.searchHits) == 0
+	b.searchHits = append(b.searchHits, msg.hits...)
+	job.done = msg.done
+	if job.jump && first && len(b.searchHits) > 0 && b == m.buf() {
+		m.jumpToMatch(b.searchHits[0])
+	}
+	if !msg.last {
+		return job.wait()
+	}
+
+	b.searching = nil
+	job.cancel()
+	b.searchIndex = min(b.searchIndex, max(len(b.searchHits)-1, 0))
+	if job.jump && len(b.searchHits) == 0 {
+		return messages.Warn("Keine Treffer für %q", job.query)
+	}
+	return nil
+}
+
+// cancelSearch bricht die laufende Suche des Buffers ab. Die bis dahin
+// gefundenen Treffer bleiben erhalten.
+func (m *Model) cancelSearch(b *Buffer) tea.Cmd {
+	job := b.searching
+	if job == nil {
+		return nil
+	}
+	b.stopSearch()
+	return messages.Info("Suche abgebrochen nach %d von %d Zeilen, %d Treffer",
+		job.done, job.lines, len(b.searchHits))
+}
+
+// stopSearch bricht die laufende Suche ohne Meldung ab, z. B. wenn die
+// Treffer ohnehin neu berechnet werden
+func (b *Buffer) stopSearch() {
+	if b.searching != nil {
+		b.searching.cancel()
+		b.searching = nil
+	}
+}
+
+// syncMatch hebt den aktuellen Treffer in den Ansichten des Buffers hervor
+// und zeigt seine Position in der Statusleiste, solange der Cursor in
+// seiner Zeile steht. Beim Einfügen passen die Treffer nicht mehr zum
+// Text, bis sie beim Verlassen neu berechnet werden.
+func (m *Model) syncMatch(b *Buffer) {
+	var hit searchMatch
+	if b.searchIndex < len(b.searchHits) && m.mode != ModeInsert {
+		hit = b.searchHits[b.searchIndex]
+	}
+	m.eachView(b, func(tv *textview.TextView) {
+		tv.SetCurrentMatch(hit.line, hit.start, hit.end)
+	})
+
+	m.statusBar.SetMatch("")
+	if hit.line > 0 && !b.hex && hit.line == m.tv().GetCurrentLine() {
+		m.statusBar.SetMatch(fmt.Sprintf("Treffer %d/%d, Zeile %d Spalte %d",
+			b.searchIndex+1, len(b.searchHits), hit.line, matchColumn(b.content, hit)))
+	}
+}
+
+// searchProgress liefert den Fortschritt einer laufenden Suche für die
+// Statusleiste
+func searchProgress(b *Buffer) string {
+	job := b.searching
+	if job == nil {
+		return ""
+	}
+	return fmt.Sprintf("Suche %d%%", job.done*100/max(job.lines, 1))
+}
+
+// lineMatches hängt die Treffer des kleingeschriebenen Begriffs in einer
+// Zeile an hits an, siehe textview.EachMatch
+func lineMatches(hits []searchMatch, lineNo int, line, lowQuery string) []searchMatch {
+	textview.EachMatch(line, lowQuery, func(start, end int) {
+		hits = append(hits, searchMatch{line: lineNo, start: start, end: end})
+	})
+	return hits
+}
+
+// jumpToMatch zeigt einen Treffer samt Spalte in der fokussierten Ansicht
+func (m *Model) jumpToMatch(hit searchMatch) {
+	m.tv().ScrollToMatch(hit.line, hit.start, hit.end)
+}
+
+// matchColumn liefert die Spalte (1-basiert, in Zeichen), in der ein
+// Treffer beginnt
+func matchColumn(text rope.Rope, hit searchMatch) int {
+	line := text.Line(hit.line - 1)
+	return utf8.RuneCountInString(line[:min(hit.start, len(line))]) + 1
+}
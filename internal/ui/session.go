@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/fase22/tui/internal/state"
+	"github.com/fase22/tui/internal/ui/components/textview"
+)
+
+// position ist eine gespeicherte Position, die nach dem Laden gilt
+type position struct {
+	line   int // Quellzeile des Cursors (1-basiert)
+	offset int // Erste sichtbare Zeile
+}
+
+// restoreState übernimmt den gespeicherten Zustand einer Datei. Die Position
+// gilt nur, solange dort noch dieselbe Datei liegt, und folgt nach dem Laden.
+func (m *Model) restoreState(b *Buffer) {
+	f, ok := m.state.Files[b.statePath()]
+	if !ok {
+		return
+	}
+	b.wrap, b.numbers = f.Wrap, f.LineNumbers
+	for _, p := range m.root.panes() {
+		p.eachView(b, func(tv *textview.TextView) {
+			tv.SetWordWrap(p.wordWrap(b))
+			tv.SetShowLineNumbers(p.showLineNumbers(b))
+		})
+	}
+	if b.searchQuery == "" {
+		b.searchQuery = f.Search
+	}
+
+	if id, err := state.Stat(b.path); err == nil && id.Continues(f.Identity) && f.Line > 0 {
+		b.restore = &position{line: f.Line, offset: f.Offset}
+	}
+}
+
+// applyRestore stellt nach dem Laden Suchtreffer und Position her
+func (m *Model) applyRestore(b *Buffer) {
+	if b.searchQuery != "" && b.searchHits == nil && b.searching == nil {
+		m.eachView(b, func(tv *textview.TextView) {
+			tv.SetSearchTerm(b.searchQuery)
+		})
+		b.startSearch(false)
+	}
+
+	if b.restore == nil || b.hex {
+		return
+	}
+	if line := displayLine(b, b.restore.line); line > 0 {
+		m.eachView(b, func(tv *textview.TextView) {
+			tv.SetPosition(line, b.restore.offset)
+		})
+	}
+	b.restore = nil
+}
+
+// rememberState merkt Position, Suche und Schalter eines Buffers im Zustand
+func (m *Model) rememberState(b *Buffer) {
+	path := b.statePath()
+	tv := m.viewOf(b)
+	if path == "" || b.state != bufferReady || b.hex || tv == nil {
+		return
+	}
+	id, err := state.Stat(b.path)
+	if err != nil {
+		return
+	}
+
+	f := m.state.File(path)
+	f.Identity = id
+	f.Line = sourceLine(b, tv.GetCurrentLine())
+	f.Offset = tv.GetViewport().YOffset
+	f.Search = ""
+	if len(b.searchQuery) <= state.MaxSearch {
+		f.Search = b.searchQuery
+	}
+	f.Wrap, f.LineNumbers = b.wrap, b.numbers
+	f.Used = time.Now()
+}
+
+// viewOf liefert die Ansicht eines Buffers, bevorzugt im fokussierten Pane
+func (m *Model) viewOf(b *Buffer) *textview.TextView {
+	if m.focus != nil && m.focus.buf == b {
+		return m.focus.view()
+	}
+	for _, p := range m.root.panes() {
+		if p.buf == b {
+			return p.view()
+		}
+	}
+	return nil
+}
+
+// SaveState merkt den Zustand aller geöffneten Buffer und schreibt ihn,
+// z. B. beim Beenden
+func (m *Model) SaveState() error {
+	for _, b := range m.buffers {
+		m.rememberState(b)
+	}
+	return m.state.Save()
+}
+
+// toggleBufferOption schaltet Zeilenumbruch oder Zeilennummern für den
+// angezeigten Buffer um. :set ändert dagegen die Vorgabe für alle Buffer.
+func (m *Model) toggleBufferOption(option **bool, fallback bool) {
+	value := !overrideOr(*option, fallback)
+	*option = &value
+	m.applyConfig()
+}
+
+// overrideOr liefert den Schalter eines Buffers, ohne ihn die Vorgabe
+func overrideOr(override *bool, fallback bool) bool {
+	if override != nil {
+		return *override
+	}
+	return fallback
+}
+
+// resetBufferOptions hebt die Schalter einzelner Buffer auf, wenn :set die
+// Vorgabe für Zeilenumbruch oder Zeilennummern ändert
+func (m *Model) resetBufferOptions(wrap, numbers bool) {
+	for _, b := range m.buffers {
+		if wrap != m.config.Editor.WordWrap {
+			b.wrap = nil
+		}
+		if numbers != m.config.Editor.ShowLineNumbers {
+			b.numbers = nil
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fase22/tui/internal/rope"
+	"github.com/fase22/tui/internal/ui/components/messages"
+	"github.com/fase22/tui/internal/ui/components/tableview"
+)
+
+// tableAction ist ein Befehl auf den Spalten einer Tabelle
+type tableAction int
+
+const (
+	tableLeft tableAction = iota
+	tableRight
+	tableMoveLeft
+	tableMoveRight
+	tableSort
+	tableHide
+)
+
+// tableView liefert die Tabelle des aktuellen Buffers
+func (m *Model) tableView() (*tableview.View, error) {
+	b := m.buf()
+	if b.mode != modeTable || b.table == nil || b.hex {
+		return nil, fmt.Errorf("Nur in der Tabellendarstellung möglich")
+	}
+	return b.table, nil
+}
+
+// tableAction führt einen Spaltenbefehl aus. Außerhalb von Tabellen tun die
+// Tasten nichts.
+func (m *Model) tableAction(action tableAction) tea.Cmd {
+	t, err := m.tableView()
+	if err != nil {
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.refreshTable(func(t *tableview.View) {
+		switch action {
+		case tableLeft:
+			t.Step(-1)
+		case tableRight:
+			t.Step(1)
+		case tableMoveLeft:
+			t.Move(-1)
+		case tableMoveRight:
+			t.Move(1)
+		case tableSort:
+			t.Sort()
+		case tableHide:
+			if err := t.Hide(); err != nil {
+				cmd = messages.Warn("%v", err)
+			}
+		}
+	})
+	if action == tableSort {
+		name, desc := t.Sorting()
+		cmd = messages.Info("Sortiert nach %s %s", name, sortDirection(desc))
+	}
+	return cmd
+}
+
+// refreshTable überträgt Änderungen an der Tabelle in die Ansichten. Die
+// Breite des fokussierten Panes bestimmt, welche Spalten sichtbar sind.
+func (m *Model) refreshTable(update func(t *tableview.View)) {
+	b := m.buf()
+	b.table.SetWidth(m.tv().TextWidth())
+	update(b.table)
+	b.content = rope.New(b.table.Content())
+	m.refreshContent(b)
+}
+
+// tableContext beschreibt die aktuelle Spalte für die Statusleiste
+func (m *Model) tableContext(t *tableview.View) string {
+	index, name, count := t.Current()
+	context := fmt.Sprintf("TABELLE %d Zeilen, Spalte %d/%d %s", t.Rows(), index, count, name)
+	if sorted, desc := t.Sorting(); sorted != "" {
+		context += fmt.Sprintf(", sortiert nach %s %s", sorted, sortDirection(desc))
+	}
+	return context
+}
+
+func sortDirection(desc bool) string {
+	if desc {
+		return "▼"
+	}
+	return "▲"
+}
+
+func (m *Model) cmdSort(args string, force bool) tea.Cmd {
+	t, err := m.tableView()
+	if err != nil {
+		return messages.Error(err)
+	}
+	if err := t.SortBy(args, force); err != nil {
+		return messages.Error(err)
+	}
+	m.refreshTable(func(*tableview.View) {})
+	if args == "" {
+		return messages.Info("Sortierung aufgehoben")
+	}
+	return messages.Info("Sortiert nach %s %s", args, sortDirection(force))
+}
+
+// tableColumns wählt die angezeigten Spalten einer Tabelle
+func (m *Model) tableColumns(args string) tea.Cmd {
+	var err error
+	m.refreshTable(func(t *tableview.View) {
+		if args == "" {
+			t.ShowAll()
+			return
+		}
+		err = t.SetColumns(strings.Fields(args))
+	})
+	if err != nil {
+		return messages.Error(err)
+	}
+	return messages.Info("Spalten: %s", strings.Join(m.buf().table.Shown(), " "))
+}